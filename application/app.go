@@ -5,6 +5,12 @@ import (
 	"bless-activity/pkg/fishpi_sdk"
 	"bless-activity/service/events"
 	"bless-activity/service/fetch_article"
+	"bless-activity/service/jury_reminder"
+	"bless-activity/service/permission"
+	"bless-activity/service/point_ledger"
+	"bless-activity/service/vote_chain"
+	"bless-activity/service/vote_jury"
+	"bless-activity/service/vote_result"
 	"log/slog"
 	"net/http"
 	"os"
@@ -33,6 +39,10 @@ type Application struct {
 	fishPiSdk *sdk.FishPiSDK
 
 	fetchArticleService *fetch_article.Service
+	voteJuryService     *vote_jury.Service
+	juryReminderService *jury_reminder.Service
+	pointLedgerService  *point_ledger.Service
+	permissionService   *permission.Service
 
 	baseController               *controller.BaseController
 	fishPiController             *controller.FishPiController
@@ -43,8 +53,12 @@ type Application struct {
 	voteJuryController           *controller.VoteJuryController
 	medalController              *controller.MedalController
 	pointController              *controller.PointController
+	voteController               *controller.VoteController
+	permissionController         *controller.PermissionController
 
-	eventbus *events.Service
+	eventbus          *events.Service
+	voteResultService *vote_result.Service
+	voteChainService  *vote_chain.Service
 }
 
 func NewApp() *Application {
@@ -96,6 +110,32 @@ func (application *Application) init(event *core.BootstrapEvent) error {
 		}
 	}
 
+	// 评审团状态调度
+	application.voteJuryService = vote_jury.NewService(application.app)
+	if err = application.voteJuryService.Run(); err != nil {
+		event.App.Logger().Error("启动评审团状态调度失败", slog.Any("err", err))
+		return err
+	}
+
+	// 评审团催投提醒，开发环境不发送私信
+	application.juryReminderService = jury_reminder.NewService(application.app, application.fishPiSdk)
+	if !application.app.IsDev() {
+		if err = application.juryReminderService.Run(); err != nil {
+			event.App.Logger().Error("启动评审团催投提醒失败", slog.Any("err", err))
+			return err
+		}
+	}
+
+	// 积分发放与流水，定时发放调度
+	application.pointLedgerService = point_ledger.NewService(application.app, application.fishPiSdk)
+	if err = application.pointLedgerService.Run(); err != nil {
+		event.App.Logger().Error("启动积分定时发放调度失败", slog.Any("err", err))
+		return err
+	}
+
+	// 权限校验
+	application.permissionService = permission.NewService(application.app)
+
 	// 问题修复
 	if err = application.fixBug(event); err != nil {
 		return err
@@ -118,10 +158,12 @@ func (application *Application) registerRoutes(event *core.ServeEvent) error {
 		queryToHeader,
 	)
 
-	application.eventbus = events.NewService(event.App)
+	application.voteResultService = vote_result.NewService(event.App)
+	application.voteChainService = vote_chain.NewService(event.App)
+	application.eventbus = events.NewService(event.App, application.voteResultService, application.permissionService)
 
 	// 调整
-	application.baseController = controller.NewBaseController(event, application.eventbus, application.fishPiSdk)
+	application.baseController = controller.NewBaseController(event, application.eventbus, application.voteResultService, application.voteChainService, application.voteJuryService, application.juryReminderService, application.pointLedgerService, application.permissionService, application.fishPiSdk)
 
 	backendGroup := event.Router.Group("/backend")
 
@@ -137,10 +179,16 @@ func (application *Application) registerRoutes(event *core.ServeEvent) error {
 	// 积分管理
 	application.pointController = controller.NewPointController(event, backendGroup, application.baseController)
 
+	// 投票管理
+	application.voteController = controller.NewVoteController(event, backendGroup, application.baseController)
+
+	// 权限管理
+	application.permissionController = controller.NewPermissionController(event, backendGroup, application.baseController)
+
 	// 待定
 	application.userController = controller.NewUserController(event)
 	application.activityController = controller.NewActivityController(event)
-	application.shieldFiveYearController = controller.NewShieldFiveYearController(event)
+	application.shieldFiveYearController = controller.NewShieldFiveYearController(event, application.baseController)
 	application.rewardDistributionController = controller.NewRewardDistributionController(event, application.baseController)
 
 	event.Router.GET("/status", func(e *core.RequestEvent) error {
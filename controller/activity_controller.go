@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
 )
 
@@ -66,6 +67,46 @@ func (controller *ActivityController) GetActivities(e *core.RequestEvent) error
 		Start       string       `json:"start"`
 		End         string       `json:"end"`
 		Rewards     []RewardItem `json:"rewards,omitempty"`
+		TiePolicy   string       `json:"tiePolicy,omitempty"`     // 同票处理方式
+		TieDesc     string       `json:"tiePolicyDesc,omitempty"` // 同票规则说明
+	}
+
+	// 一次查询所有活动关联的奖励，按奖励组分组
+	rewardGroupIds := make([]any, 0, len(activities))
+	for _, record := range activities {
+		if rewardGroupId := model.NewActivity(record).GetRewardGroupId(); rewardGroupId != "" {
+			rewardGroupIds = append(rewardGroupIds, rewardGroupId)
+		}
+	}
+	rewardsByGroup := make(map[string][]RewardItem)
+	tiePolicies := make(map[string]model.RewardTiePolicy)
+	if len(rewardGroupIds) > 0 {
+		var rewards []*model.Reward
+		if err := controller.app.RecordQuery(model.DbNameRewards).
+			Where(dbx.In(model.RewardsFieldRewardGroupId, rewardGroupIds...)).
+			OrderBy(model.RewardsFieldMin). // 按最小名次排序
+			All(&rewards); err != nil {
+			slog.Warn("查询奖励失败", slog.Any("err", err))
+		}
+		for _, reward := range rewards {
+			rewardsByGroup[reward.RewardGroupId()] = append(rewardsByGroup[reward.RewardGroupId()], RewardItem{
+				Name:  reward.Name(),
+				Min:   reward.Min(),
+				Max:   reward.Max(),
+				Point: reward.Point(),
+				More:  reward.More(),
+			})
+		}
+
+		var rewardGroups []*model.RewardGroup
+		if err := controller.app.RecordQuery(model.DbNameRewardGroups).
+			Where(dbx.In(model.CommonFieldId, rewardGroupIds...)).
+			All(&rewardGroups); err != nil {
+			slog.Warn("查询奖励组失败", slog.Any("err", err))
+		}
+		for _, rewardGroup := range rewardGroups {
+			tiePolicies[rewardGroup.Id] = rewardGroup.TiePolicy()
+		}
 	}
 
 	activityList := make([]ActivityResponse, 0, len(activities))
@@ -84,34 +125,12 @@ func (controller *ActivityController) GetActivities(e *core.RequestEvent) error
 			End:         activity.GetEnd().String(),
 		}
 
-		// 查询活动关联的奖励信息
-		rewardGroupId := activity.GetRewardGroupId()
-		slog.Info("活动信息", slog.String("activity", activity.GetName()), slog.String("rewardGroupId", rewardGroupId))
-		if rewardGroupId != "" {
-			// 根据 rewardGroupId 查询 rewards 表
-			rewards, err := controller.app.FindRecordsByFilter(
-				model.DbNameRewards,
-				model.RewardsFieldRewardGroupId+" = {:rewardGroupId}",
-				model.RewardsFieldMin, // 按最小名次排序
-				0,
-				0,
-				map[string]any{"rewardGroupId": rewardGroupId},
-			)
-
-			slog.Info("奖励内容 ", slog.String("activity", activity.GetName()), slog.Any("err", err), slog.Int("count", len(rewards)))
-			if err == nil && len(rewards) > 0 {
-				rewardItems := make([]RewardItem, 0, len(rewards))
-				for _, rewardRecord := range rewards {
-					reward := model.NewReward(rewardRecord)
-					rewardItems = append(rewardItems, RewardItem{
-						Name:  reward.Name(),
-						Min:   reward.Min(),
-						Max:   reward.Max(),
-						Point: reward.Point(),
-						More:  reward.More(),
-					})
-				}
-				activityResp.Rewards = rewardItems
+		// 活动关联的奖励信息
+		if rewardGroupId := activity.GetRewardGroupId(); rewardGroupId != "" {
+			activityResp.Rewards = rewardsByGroup[rewardGroupId]
+			if policy, ok := tiePolicies[rewardGroupId]; ok {
+				activityResp.TiePolicy = policy.String()
+				activityResp.TieDesc = policy.Description()
 			}
 		}
 
@@ -149,8 +168,16 @@ func (controller *ActivityController) GetActivityRewards(e *core.RequestEvent) e
 	}
 
 	var rewardItems []RewardItem
+	var tiePolicy model.RewardTiePolicy
 
 	if rewardGroupId != "" {
+		rewardGroup := model.NewRewardGroup(nil)
+		if err := controller.app.RecordQuery(model.DbNameRewardGroups).
+			Where(dbx.HashExp{model.CommonFieldId: rewardGroupId}).
+			One(rewardGroup); err == nil {
+			tiePolicy = rewardGroup.TiePolicy()
+		}
+
 		// 根据 rewardGroupId 查询 rewards 表
 		rewards, err := controller.app.FindRecordsByFilter(
 			model.DbNameRewards,
@@ -177,12 +204,14 @@ func (controller *ActivityController) GetActivityRewards(e *core.RequestEvent) e
 	}
 
 	return e.JSON(http.StatusOK, map[string]interface{}{
-		"activityId": activityId,
-		"name":       activityModel.GetName(),
-		"desc":       activityModel.GetDesc(),
-		"rewards":    rewardItems,
-		"start":      activityModel.GetStart(),
-		"end":        activityModel.GetEnd(),
+		"activityId":    activityId,
+		"name":          activityModel.GetName(),
+		"desc":          activityModel.GetDesc(),
+		"rewards":       rewardItems,
+		"tiePolicy":     tiePolicy,
+		"tiePolicyDesc": tiePolicy.Description(),
+		"start":         activityModel.GetStart(),
+		"end":           activityModel.GetEnd(),
 	})
 }
 
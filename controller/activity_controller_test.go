@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"bless-activity/model"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// seedActivities 创建 count 个带奖励组的活动，每个奖励组两档奖励
+func seedActivities(t *testing.T, server *testServer, count int) {
+	for i := range count {
+		rewardGroup := server.createRecord(t, model.DbNameRewardGroups, map[string]any{
+			model.RewardGroupsFieldName:      fmt.Sprintf("奖励组%d", i),
+			model.RewardGroupsFieldTiePolicy: model.RewardTiePolicyShared.String(),
+		})
+		for rank := 1; rank <= 2; rank++ {
+			server.createRecord(t, model.DbNameRewards, map[string]any{
+				model.RewardsFieldRewardGroupId: rewardGroup.Id,
+				model.RewardsFieldName:          fmt.Sprintf("第%d名", rank),
+				model.RewardsFieldMin:           rank,
+				model.RewardsFieldMax:           rank,
+				model.RewardsFieldPoint:         100,
+			})
+		}
+		server.createRecord(t, model.DbNameActivities, map[string]any{
+			model.ActivitiesFieldName:          fmt.Sprintf("活动%d", i),
+			model.ActivitiesFieldSlug:          fmt.Sprintf("activity-%d", i),
+			model.ActivitiesFieldRewardGroupId: rewardGroup.Id,
+		})
+	}
+}
+
+func TestGetActivitiesQueryCount(t *testing.T) {
+	queries := make(map[int]int64)
+	for _, count := range []int{1, 10} {
+		server := newTestServer(t)
+		seedActivities(t, server, count)
+
+		queries[count] = server.countQueries(func() {
+			res := server.request(t, http.MethodGet, "/activity-api/activities", "", nil)
+			if res.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", res.Code, res.Body.String())
+			}
+		})
+	}
+
+	// 奖励与奖励组批量查询，查询次数与活动数量无关
+	if queries[1] != queries[10] {
+		t.Errorf("query count grows with activities: 1 activity %d queries, 10 activities %d queries", queries[1], queries[10])
+	}
+}
@@ -3,6 +3,12 @@ package controller
 import (
 	"bless-activity/model"
 	"bless-activity/service/events"
+	"bless-activity/service/jury_reminder"
+	"bless-activity/service/permission"
+	"bless-activity/service/point_ledger"
+	"bless-activity/service/vote_chain"
+	"bless-activity/service/vote_jury"
+	"bless-activity/service/vote_result"
 	"time"
 
 	"github.com/FishPiOffical/golang-sdk/sdk"
@@ -14,17 +20,31 @@ type BaseController struct {
 	event *core.ServeEvent
 	app   core.App
 
-	fishPiSdk *sdk.FishPiSDK
-	eventbus  *events.Service
+	fishPiSdk  *sdk.FishPiSDK
+	eventbus   *events.Service
+	voteResult *vote_result.Service
+	voteChain  *vote_chain.Service
+	voteJury   *vote_jury.Service
+
+	juryReminder *jury_reminder.Service
+	pointLedger  *point_ledger.Service
+	permission   *permission.Service
 }
 
-func NewBaseController(event *core.ServeEvent, eventbus *events.Service, fishPiSdk *sdk.FishPiSDK) *BaseController {
+func NewBaseController(event *core.ServeEvent, eventbus *events.Service, voteResult *vote_result.Service, voteChain *vote_chain.Service, voteJury *vote_jury.Service, juryReminder *jury_reminder.Service, pointLedger *point_ledger.Service, permission *permission.Service, fishPiSdk *sdk.FishPiSDK) *BaseController {
 	controller := &BaseController{
 		event: event,
 		app:   event.App,
 
-		fishPiSdk: fishPiSdk,
-		eventbus:  eventbus,
+		fishPiSdk:  fishPiSdk,
+		eventbus:   eventbus,
+		voteResult: voteResult,
+		voteChain:  voteChain,
+		voteJury:   voteJury,
+
+		juryReminder: juryReminder,
+		pointLedger:  pointLedger,
+		permission:   permission,
 	}
 	return controller
 }
@@ -39,25 +59,96 @@ func (controller *BaseController) CheckActivity(event *core.RequestEvent) error
 	return event.Next()
 }
 
-// RequireAdminRole 验证用户是否拥有管理员角色
-// 此中间件会先验证用户是否已登录，然后检查用户的 role 字段是否为 admin
-func RequireAdminRole() *hook.Handler[*core.RequestEvent] {
+// PermissionScope 解析请求涉及的活动ID，用于匹配按活动授予的权限；返回空时只认全局授权
+type PermissionScope func(event *core.RequestEvent) ([]string, error)
+
+// RequirePermission 验证当前用户是否拥有指定权限
+// 超级管理员拥有全部权限，管理员角色拥有发放积分、勋章与投票管理权限，其余情况需要在 permissions 表中被全局授权或按 scope 解析出的活动授权
+func (controller *BaseController) RequirePermission(permission model.Permission, scope PermissionScope) *hook.Handler[*core.RequestEvent] {
+	return &hook.Handler[*core.RequestEvent]{
+		Id: "require_permission_" + string(permission),
+		Func: func(event *core.RequestEvent) error {
+			if event.Auth == nil {
+				return event.UnauthorizedError("未登录", nil)
+			}
+
+			var activityIds []string
+			if scope != nil {
+				var err error
+				if activityIds, err = scope(event); err != nil {
+					return err
+				}
+			}
+
+			if !controller.permission.Has(event.Auth, permission, activityIds...) {
+				return event.ForbiddenError("缺少权限："+string(permission), nil)
+			}
+
+			return event.Next()
+		},
+	}
+}
+
+// RequireVotePermission 验证当前用户是否拥有投票所属活动的指定权限，投票ID取自路径、查询参数或请求体
+func (controller *BaseController) RequireVotePermission(permission model.Permission) *hook.Handler[*core.RequestEvent] {
 	return &hook.Handler[*core.RequestEvent]{
-		Id: "require_admin_role",
+		Id: "require_vote_permission_" + string(permission),
 		Func: func(event *core.RequestEvent) error {
-			// 获取当前用户记录
-			authRecord := event.Auth
-			if authRecord == nil {
+			if event.Auth == nil {
 				return event.UnauthorizedError("未登录", nil)
 			}
 
-			// 检查用户角色
-			role := authRecord.GetString(model.UsersFieldRole)
-			if role != string(model.UserRoleAdmin) {
-				return event.ForbiddenError("需要管理员权限", nil)
+			voteId := requestValue(event, "voteId")
+			if voteId == "" {
+				return event.BadRequestError("投票ID不能为空", nil)
+			}
+
+			if !controller.permission.HasForVote(event.Auth, permission, voteId) {
+				return event.ForbiddenError("缺少权限："+string(permission), nil)
 			}
 
 			return event.Next()
 		},
 	}
 }
+
+// ActivityScope 从路径、查询参数或请求体中的 activityId 解析授权范围
+func ActivityScope(event *core.RequestEvent) ([]string, error) {
+	if activityId := requestValue(event, "activityId"); activityId != "" {
+		return []string{activityId}, nil
+	}
+	return nil, nil
+}
+
+// requestValue 依次从路径参数、查询参数、请求体中读取字段
+func requestValue(event *core.RequestEvent, key string) string {
+	if value := event.Request.PathValue(key); value != "" {
+		return value
+	}
+	if value := event.Request.URL.Query().Get(key); value != "" {
+		return value
+	}
+
+	data := map[string]any{}
+	if err := event.BindBody(&data); err != nil {
+		return ""
+	}
+	value, _ := data[key].(string)
+	return value
+}
+
+// IsAdminRole 判断用户记录的 role 字段是否为 admin
+func IsAdminRole(authRecord *core.Record) bool {
+	if authRecord == nil {
+		return false
+	}
+	return authRecord.GetString(model.UsersFieldRole) == string(model.UserRoleAdmin)
+}
+
+// HasAdminAuth 判断当前请求是否为超级管理员或管理员角色
+func HasAdminAuth(event *core.RequestEvent) bool {
+	if event.Auth == nil {
+		return false
+	}
+	return event.HasSuperuserAuth() || IsAdminRole(event.Auth)
+}
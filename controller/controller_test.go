@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"bless-activity/model"
+	"bless-activity/service/events"
+	"bless-activity/service/jury_reminder"
+	"bless-activity/service/permission"
+	"bless-activity/service/point_ledger"
+	"bless-activity/service/vote_chain"
+	"bless-activity/service/vote_jury"
+	"bless-activity/service/vote_result"
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+// testServer 注册了全部业务路由的测试服务，每个测试使用独立的数据目录
+type testServer struct {
+	app *tests.TestApp
+	mux http.Handler
+
+	rewardDistribution *RewardDistributionController
+}
+
+func newTestServer(t testing.TB) *testServer {
+	t.Helper()
+	return newTestServerWithConfig(t, core.BaseAppConfig{})
+}
+
+// newTestServerWithConfig 按指定配置创建测试服务，开发模式下跳过对摸鱼派的实际调用
+func newTestServerWithConfig(t testing.TB, config core.BaseAppConfig) *testServer {
+	t.Helper()
+
+	config.DataDir = t.TempDir()
+	app, err := tests.NewTestAppWithConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(app.Cleanup)
+
+	if err = createTestCollections(app); err != nil {
+		t.Fatal(err)
+	}
+
+	baseRouter, err := apis.NewRouter(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := &core.ServeEvent{App: app, Router: baseRouter}
+
+	permissionService := permission.NewService(app)
+	voteResultService := vote_result.NewService(app)
+	base := NewBaseController(event,
+		events.NewService(app, voteResultService, permissionService),
+		voteResultService,
+		vote_chain.NewService(app),
+		vote_jury.NewService(app),
+		jury_reminder.NewService(app, nil),
+		point_ledger.NewService(app, nil),
+		permissionService,
+		nil,
+	)
+
+	backendGroup := baseRouter.Group("/backend")
+	NewVoteJuryController(base, backendGroup)
+	NewMedalController(event, backendGroup, base)
+	NewPointController(event, backendGroup, base)
+	NewVoteController(event, backendGroup, base)
+	NewPermissionController(event, backendGroup, base)
+	NewActivityController(event)
+	NewShieldFiveYearController(event, base)
+	rewardDistribution := NewRewardDistributionController(event, base)
+
+	mux, err := baseRouter.BuildMux()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testServer{app: app, mux: mux, rewardDistribution: rewardDistribution}
+}
+
+// request 以指定用户身份发送请求，auth 为 nil 时匿名访问
+func (server *testServer) request(t testing.TB, method string, url string, body string, auth *core.Record) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if auth != nil {
+		token, err := auth.NewAuthToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", token)
+	}
+
+	recorder := httptest.NewRecorder()
+	server.mux.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// countQueries 统计执行 fn 期间发出的 SQL 语句数
+func (server *testServer) countQueries(fn func()) int64 {
+	var count atomic.Int64
+	for _, builder := range []dbx.Builder{server.app.ConcurrentDB(), server.app.NonconcurrentDB()} {
+		db := builder.(*dbx.DB)
+		queryLog, execLog := db.QueryLogFunc, db.ExecLogFunc
+		db.QueryLogFunc = func(context.Context, time.Duration, string, *sql.Rows, error) {
+			count.Add(1)
+		}
+		db.ExecLogFunc = func(context.Context, time.Duration, string, sql.Result, error) {
+			count.Add(1)
+		}
+		defer func() {
+			db.QueryLogFunc, db.ExecLogFunc = queryLog, execLog
+		}()
+	}
+
+	fn()
+	return count.Load()
+}
+
+// createRecord 创建记录，fields 中的值按字段原样写入
+func (server *testServer) createRecord(t testing.TB, collectionName string, fields map[string]any) *core.Record {
+	t.Helper()
+
+	collection, err := server.app.FindCollectionByNameOrId(collectionName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := core.NewRecord(collection)
+	for key, value := range fields {
+		record.Set(key, value)
+	}
+	if collection.IsAuth() {
+		record.SetRandomPassword()
+	}
+	if err = server.app.Save(record); err != nil {
+		t.Fatalf("save %s: %v", collectionName, err)
+	}
+	return record
+}
+
+// createUser 创建普通用户，role 为空表示普通成员
+func (server *testServer) createUser(t testing.TB, name string, role model.UserRole) *core.Record {
+	t.Helper()
+	return server.createRecord(t, model.DbNameUsers, map[string]any{
+		model.UsersFieldName:     name,
+		model.UsersFieldNickname: name,
+		model.UsersFieldRole:     string(role),
+	})
+}
+
+// createSuperuser 创建超级管理员
+func (server *testServer) createSuperuser(t testing.TB) *core.Record {
+	t.Helper()
+	return server.createRecord(t, core.CollectionNameSuperusers, map[string]any{
+		core.FieldNameEmail: "root@example.com",
+	})
+}
@@ -46,7 +46,7 @@ func NewMedalController(event *core.ServeEvent, group *router.RouterGroup[*core.
 
 func (controller *MedalController) registerRoutes() {
 	group := controller.group.Group("/admin/medal").Bind(
-		RequireAdminRole(),
+		controller.RequirePermission(model.PermissionMedalGrant, nil),
 	)
 
 	// 勋章列表
@@ -80,7 +80,7 @@ func (controller *MedalController) registerRoutes() {
 
 	// 用户列表相关接口 - 放在 /backend/admin/user-list/* 下
 	userListGroup := controller.group.Group("/admin/user-list").Bind(
-		RequireAdminRole(),
+		controller.RequirePermission(model.PermissionMedalGrant, nil),
 	)
 	userListGroup.GET("/search", controller.SearchUsers)
 	userListGroup.GET("/activities", controller.GetActivities)
@@ -957,23 +957,9 @@ func (controller *MedalController) GetMedalOwners(event *core.RequestEvent) erro
 	}
 
 	// 批量查询用户
-	usersMap := make(map[string]*model.User)
-	if len(userIds) > 0 {
-		var users []*model.User
-		// 使用 IN 查询批量获取用户
-		userIdsAny := make([]any, len(userIds))
-		for i, id := range userIds {
-			userIdsAny[i] = id
-		}
-		if err := event.App.RecordQuery(model.DbNameUsers).Where(
-			dbx.In(model.CommonFieldId, userIdsAny...),
-		).All(&users); err != nil {
-			logger.Warn("批量查询用户失败", slog.Any("err", err))
-		} else {
-			for _, user := range users {
-				usersMap[user.Id] = user
-			}
-		}
+	users := controller.Users(event).Add(userIds...)
+	if err := users.Load(); err != nil {
+		logger.Warn("批量查询用户失败", slog.Any("err", err))
 	}
 
 	// 构建包含用户信息的响应
@@ -1010,7 +996,7 @@ func (controller *MedalController) GetMedalOwners(event *core.RequestEvent) erro
 			Created:      owner.Created().String(),
 			Updated:      owner.Updated().String(),
 		}
-		if user, exists := usersMap[owner.UserId()]; exists {
+		if user := users.Get(owner.UserId()); user != nil {
 			item.Expand = &struct {
 				UserId *struct {
 					Id       string `json:"id"`
@@ -1366,7 +1352,7 @@ func (controller *MedalController) GetActivityParticipants(event *core.RequestEv
 	}
 
 	// 批量查询用户
-	userIds := make([]any, 0, len(userIdSet))
+	userIds := make([]string, 0, len(userIdSet))
 	for userId := range userIdSet {
 		userIds = append(userIds, userId)
 	}
@@ -1379,23 +1365,21 @@ func (controller *MedalController) GetActivityParticipants(event *core.RequestEv
 		Avatar   string `json:"avatar"`
 	}
 
-	items := make([]*userItem, 0)
-	if len(userIds) > 0 {
-		var users []*model.User
-		if err := event.App.RecordQuery(model.DbNameUsers).
-			Where(dbx.In(model.CommonFieldId, userIds...)).
-			All(&users); err != nil {
-			logger.Warn("批量查询用户失败", slog.Any("err", err))
-		} else {
-			for _, user := range users {
-				items = append(items, &userItem{
-					Id:       user.Id,
-					OId:      user.OId(),
-					Name:     user.Name(),
-					Nickname: user.Nickname(),
-					Avatar:   user.Avatar(),
-				})
-			}
+	users := controller.Users(event).Add(userIds...)
+	if err := users.Load(); err != nil {
+		logger.Warn("批量查询用户失败", slog.Any("err", err))
+	}
+
+	items := make([]*userItem, 0, len(userIds))
+	for _, userId := range userIds {
+		if user := users.Get(userId); user != nil {
+			items = append(items, &userItem{
+				Id:       user.Id,
+				OId:      user.OId(),
+				Name:     user.Name(),
+				Nickname: user.Nickname(),
+				Avatar:   user.Avatar(),
+			})
 		}
 	}
 
@@ -1426,7 +1410,7 @@ func (controller *MedalController) GetVoteJuryMembers(event *core.RequestEvent)
 	}
 
 	// 收集用户ID
-	userIds := make([]any, 0, len(juryUsers))
+	userIds := make([]string, 0, len(juryUsers))
 	for _, juryUser := range juryUsers {
 		if userId := juryUser.UserId(); userId != "" {
 			userIds = append(userIds, userId)
@@ -1441,23 +1425,21 @@ func (controller *MedalController) GetVoteJuryMembers(event *core.RequestEvent)
 		Avatar   string `json:"avatar"`
 	}
 
-	items := make([]*userItem, 0)
-	if len(userIds) > 0 {
-		var users []*model.User
-		if err := event.App.RecordQuery(model.DbNameUsers).
-			Where(dbx.In(model.CommonFieldId, userIds...)).
-			All(&users); err != nil {
-			logger.Warn("批量查询用户失败", slog.Any("err", err))
-		} else {
-			for _, user := range users {
-				items = append(items, &userItem{
-					Id:       user.Id,
-					OId:      user.OId(),
-					Name:     user.Name(),
-					Nickname: user.Nickname(),
-					Avatar:   user.Avatar(),
-				})
-			}
+	users := controller.Users(event).Add(userIds...)
+	if err := users.Load(); err != nil {
+		logger.Warn("批量查询用户失败", slog.Any("err", err))
+	}
+
+	items := make([]*userItem, 0, len(userIds))
+	for _, userId := range userIds {
+		if user := users.Get(userId); user != nil {
+			items = append(items, &userItem{
+				Id:       user.Id,
+				OId:      user.OId(),
+				Name:     user.Name(),
+				Nickname: user.Nickname(),
+				Avatar:   user.Avatar(),
+			})
 		}
 	}
 
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"bless-activity/model"
+	"bless-activity/service/permission"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+type PermissionController struct {
+	*BaseController
+
+	event  *core.ServeEvent
+	group  *router.RouterGroup[*core.RequestEvent]
+	app    core.App
+	logger *slog.Logger
+}
+
+func NewPermissionController(event *core.ServeEvent, group *router.RouterGroup[*core.RequestEvent], base *BaseController) *PermissionController {
+	logger := event.App.Logger().With(
+		slog.String("controller", "permission"),
+	)
+
+	controller := &PermissionController{
+		BaseController: base,
+		event:          event,
+		group:          group,
+		app:            event.App,
+		logger:         logger,
+	}
+
+	controller.registerRoutes()
+
+	return controller
+}
+
+func (controller *PermissionController) registerRoutes() {
+	// 授权管理只开放给超级管理员
+	group := controller.group.Group("/admin/permission").Bind(
+		apis.RequireSuperuserAuth(),
+	)
+
+	// 可授予的权限名称
+	group.GET("/names", controller.Names)
+	// 授权列表
+	group.GET("/list", controller.List)
+	// 授予权限
+	group.POST("/grant", controller.Grant)
+	// 撤销授权
+	group.DELETE("/revoke/{id}", controller.Revoke)
+}
+
+func (controller *PermissionController) makeActionLogger(action string) *slog.Logger {
+	return controller.logger.With(
+		slog.String("action", action),
+	)
+}
+
+// Names 可授予的权限名称
+func (controller *PermissionController) Names(event *core.RequestEvent) error {
+	return event.JSON(http.StatusOK, map[string]any{
+		"items": model.PermissionNames(),
+	})
+}
+
+// List 授权列表，可按用户筛选
+func (controller *PermissionController) List(event *core.RequestEvent) error {
+	grants, err := controller.permission.Grants(event.Request.URL.Query().Get("userId"))
+	if err != nil {
+		return event.InternalServerError("获取授权列表失败", err)
+	}
+	return event.JSON(http.StatusOK, map[string]any{
+		"items": grants,
+	})
+}
+
+// Grant 授予权限，activityId 为空表示全局授权
+func (controller *PermissionController) Grant(event *core.RequestEvent) error {
+	logger := controller.makeActionLogger("grant")
+
+	var req struct {
+		UserId     string `json:"userId"`
+		Permission string `json:"permission"`
+		ActivityId string `json:"activityId"`
+	}
+	if err := event.BindBody(&req); err != nil {
+		return event.BadRequestError("参数错误", err)
+	}
+	if req.UserId == "" {
+		return event.BadRequestError("用户ID不能为空", nil)
+	}
+
+	name, err := model.ParsePermission(req.Permission)
+	if err != nil {
+		return event.BadRequestError("无效的权限名称", err)
+	}
+
+	if _, err = controller.app.FindRecordById(model.DbNameUsers, req.UserId); err != nil {
+		return event.NotFoundError("用户不存在", err)
+	}
+	if req.ActivityId != "" {
+		if _, err = controller.app.FindRecordById(model.DbNameActivities, req.ActivityId); err != nil {
+			return event.NotFoundError("活动不存在", err)
+		}
+	}
+
+	grant, err := controller.permission.Grant(req.UserId, name, req.ActivityId, event.Auth.Id)
+	if err != nil {
+		if errors.Is(err, permission.ErrGrantExists) {
+			return event.BadRequestError("授权已存在", nil)
+		}
+		logger.Error("授予权限失败", slog.String("userId", req.UserId), slog.Any("err", err))
+		return event.InternalServerError("授予权限失败", err)
+	}
+
+	return event.JSON(http.StatusOK, grant)
+}
+
+// Revoke 撤销授权
+func (controller *PermissionController) Revoke(event *core.RequestEvent) error {
+	logger := controller.makeActionLogger("revoke")
+
+	id := event.Request.PathValue("id")
+	if err := controller.permission.Revoke(id); err != nil {
+		if errors.Is(err, permission.ErrGrantNotFound) {
+			return event.NotFoundError("授权记录不存在", nil)
+		}
+		logger.Error("撤销授权失败", slog.String("id", id), slog.Any("err", err))
+		return event.InternalServerError("撤销授权失败", err)
+	}
+
+	return event.JSON(http.StatusOK, map[string]any{
+		"deleted": true,
+	})
+}
@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"bless-activity/model"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// accessRoute 受权限保护的路由
+type accessRoute struct {
+	method     string
+	url        string
+	body       string
+	permission model.Permission // 路由要求的权限，为空表示只开放给超级管理员
+	scoped     bool             // 是否可以通过活动范围的授权访问
+}
+
+// accessRoutes 全部受权限保护的路由，activityId、voteId、approvalId 为路由涉及的活动、投票与审批单
+func accessRoutes(activityId string, voteId string, approvalId string) []accessRoute {
+	var routes []accessRoute
+	add := func(permission model.Permission, scoped bool, method string, url string, body string) {
+		routes = append(routes, accessRoute{method: method, url: url, body: body, permission: permission, scoped: scoped})
+	}
+
+	activityBody := fmt.Sprintf(`{"activityId":%q}`, activityId)
+	add(model.PermissionRewardDistribute, true, http.MethodPost, "/activity-api/reward/preview", activityBody)
+	add(model.PermissionRewardDistribute, true, http.MethodPost, "/activity-api/reward/distribute", activityBody)
+	add(model.PermissionRewardDistribute, true, http.MethodPost, "/activity-api/reward/retry?activityId="+activityId, "")
+	add(model.PermissionRewardDistribute, true, http.MethodGet, "/activity-api/reward/report?activityId="+activityId, "")
+	add(model.PermissionRewardDistribute, false, http.MethodGet, "/activity-api/reward/approvals", "")
+	approvalBody := fmt.Sprintf(`{"approvalId":%q}`, approvalId)
+	add(model.PermissionRewardDistribute, true, http.MethodPost, "/activity-api/reward/approval/approve", approvalBody)
+	add(model.PermissionRewardDistribute, true, http.MethodPost, "/activity-api/reward/approval/reject", approvalBody)
+
+	add(model.PermissionPointDistribute, true, http.MethodPost, "/backend/admin/point/batch/create", activityBody)
+	add(model.PermissionPointDistribute, true, http.MethodPost, "/backend/admin/point/import", activityBody)
+	add(model.PermissionPointDistribute, false, http.MethodGet, "/backend/admin/point/list", "")
+	add(model.PermissionPointDistribute, false, http.MethodPost, "/backend/admin/point/batch/distribute", "{}")
+	add(model.PermissionPointDistribute, false, http.MethodPost, "/backend/admin/point/batch/retry", "{}")
+	add(model.PermissionPointDistribute, false, http.MethodDelete, "/backend/admin/point/delete/missing", "")
+	add(model.PermissionPointDistribute, false, http.MethodPost, "/backend/admin/point/batch/delete", "{}")
+	add(model.PermissionPointDistribute, false, http.MethodPost, "/backend/admin/point/schedule", "{}")
+	add(model.PermissionPointDistribute, false, http.MethodPost, "/backend/admin/point/schedule/cancel", "{}")
+	add(model.PermissionPointDistribute, false, http.MethodGet, "/backend/admin/point/approvals", "")
+	add(model.PermissionPointDistribute, false, http.MethodPost, "/backend/admin/point/approval/approve", "{}")
+	add(model.PermissionPointDistribute, false, http.MethodPost, "/backend/admin/point/approval/reject", "{}")
+
+	add(model.PermissionMedalGrant, false, http.MethodGet, "/backend/admin/medal/list", "")
+	add(model.PermissionMedalGrant, false, http.MethodGet, "/backend/admin/medal/detail/missing", "")
+	add(model.PermissionMedalGrant, false, http.MethodPost, "/backend/admin/medal/create", "{}")
+	add(model.PermissionMedalGrant, false, http.MethodPut, "/backend/admin/medal/edit/missing", "{}")
+	add(model.PermissionMedalGrant, false, http.MethodDelete, "/backend/admin/medal/delete/missing", "")
+	add(model.PermissionMedalGrant, false, http.MethodPost, "/backend/admin/medal/sync/all", "")
+	add(model.PermissionMedalGrant, false, http.MethodPost, "/backend/admin/medal/sync/missing", "")
+	add(model.PermissionMedalGrant, false, http.MethodPost, "/backend/admin/medal/sync/owners/all", "")
+	add(model.PermissionMedalGrant, false, http.MethodPost, "/backend/admin/medal/sync/owners/missing", "")
+	add(model.PermissionMedalGrant, false, http.MethodPost, "/backend/admin/medal/sync/user/missing", "")
+	add(model.PermissionMedalGrant, false, http.MethodGet, "/backend/admin/medal/owners/missing", "")
+	add(model.PermissionMedalGrant, false, http.MethodPost, "/backend/admin/medal/grant", "{}")
+	add(model.PermissionMedalGrant, false, http.MethodPost, "/backend/admin/medal/grant/batch", "{}")
+	add(model.PermissionMedalGrant, false, http.MethodPost, "/backend/admin/medal/revoke", "{}")
+	add(model.PermissionMedalGrant, false, http.MethodGet, "/backend/admin/medal/search", "")
+	add(model.PermissionMedalGrant, false, http.MethodGet, "/backend/admin/user-list/search", "")
+	add(model.PermissionMedalGrant, false, http.MethodGet, "/backend/admin/user-list/activities", "")
+	add(model.PermissionMedalGrant, false, http.MethodGet, "/backend/admin/user-list/activity/"+activityId+"/participants", "")
+	add(model.PermissionMedalGrant, false, http.MethodGet, "/backend/admin/user-list/vote/"+voteId+"/jury", "")
+
+	add(model.PermissionVoteManage, true, http.MethodPost, "/backend/admin/vote/publish/"+voteId, "")
+	add(model.PermissionVoteManage, true, http.MethodGet, "/backend/admin/vote/fraud/"+voteId, "")
+	add(model.PermissionVoteManage, true, http.MethodPost, "/backend/admin/vote/fraud/invalidate", fmt.Sprintf(`{"voteId":%q}`, voteId))
+
+	voteBody := fmt.Sprintf(`{"voteId":%q}`, voteId)
+	for _, path := range []string{
+		"user/create", "member/add", "member/remove", "apply/audit", "status/switch", "calculate", "round/rollback",
+		"select", "reveal/open", "remind", "conflict/declare", "conflict/remove", "objection/resolve",
+	} {
+		add(model.PermissionJuryManage, true, http.MethodPost, "/backend/vote/jury/"+path, voteBody)
+	}
+	add(model.PermissionJuryManage, true, http.MethodGet, "/backend/vote/jury/vote-details/"+voteId, "")
+	add(model.PermissionJuryManage, true, http.MethodGet, "/backend/vote/jury/juror-history/"+voteId+"/missing", "")
+
+	add("", false, http.MethodGet, "/backend/admin/permission/names", "")
+	add("", false, http.MethodGet, "/backend/admin/permission/list", "")
+	add("", false, http.MethodPost, "/backend/admin/permission/grant", "{}")
+	add("", false, http.MethodDelete, "/backend/admin/permission/revoke/missing", "")
+
+	return routes
+}
+
+func TestRouteAccessMatrix(t *testing.T) {
+	server := newTestServer(t)
+
+	vote := server.createRecord(t, model.DbNameVotes, map[string]any{
+		model.VotesFieldName: "评审团投票",
+		model.VotesFieldType: model.VoteTypeJury.String(),
+	})
+	// 评审团规则中的管理员只管理这一个投票的评审团
+	ruleAdmin := server.createUser(t, "rule-admin", "")
+	server.createRecord(t, model.DbNameVoteJuryRules, map[string]any{
+		model.VoteJuryRuleFieldVoteId: vote.Id,
+		model.VoteJuryRuleFieldStatus: model.VoteJuryRuleStatusPending.String(),
+		model.VoteJuryRuleFieldAdmins: []string{ruleAdmin.Id},
+	})
+	activity := server.createRecord(t, model.DbNameActivities, map[string]any{
+		model.ActivitiesFieldName:   "活动",
+		model.ActivitiesFieldVoteId: vote.Id,
+	})
+	approval := server.createRecord(t, model.DbNamePayoutApprovals, map[string]any{
+		model.PayoutApprovalsFieldKind:       model.PayoutKindReward.String(),
+		model.PayoutApprovalsFieldActivityId: activity.Id,
+		model.PayoutApprovalsFieldStatus:     model.PayoutApprovalStatusPending.String(),
+	})
+
+	superuser := server.createSuperuser(t)
+	roleAdmin := server.createUser(t, "role-admin", model.UserRoleAdmin)
+	nobody := server.createUser(t, "nobody", "")
+
+	// 每个权限各建一个全局授权用户和一个活动范围授权用户
+	globalUsers := map[model.Permission]*core.Record{}
+	activityUsers := map[model.Permission]*core.Record{}
+	for _, permission := range model.PermissionValues() {
+		globalUsers[permission] = server.createUser(t, "global-"+string(permission), "")
+		server.createRecord(t, model.DbNamePermissions, map[string]any{
+			model.PermissionsFieldUserId:     globalUsers[permission].Id,
+			model.PermissionsFieldPermission: string(permission),
+		})
+		activityUsers[permission] = server.createUser(t, "activity-"+string(permission), "")
+		server.createRecord(t, model.DbNamePermissions, map[string]any{
+			model.PermissionsFieldUserId:     activityUsers[permission].Id,
+			model.PermissionsFieldPermission: string(permission),
+			model.PermissionsFieldActivityId: activity.Id,
+		})
+	}
+
+	for _, route := range accessRoutes(activity.Id, vote.Id, approval.Id) {
+		cases := []struct {
+			name    string
+			auth    *core.Record
+			allowed bool
+		}{
+			{"superuser", superuser, true},
+			// 管理员角色没有 jury.manage，评审团由规则中的管理员或授权用户管理
+			{"role-admin", roleAdmin, route.permission != "" && route.permission != model.PermissionRewardDistribute && route.permission != model.PermissionJuryManage},
+			{"rule-admin", ruleAdmin, route.permission == model.PermissionJuryManage},
+			{"global-grant", globalUsers[route.permission], route.permission != ""},
+			{"activity-grant", activityUsers[route.permission], route.scoped},
+			{"no-grant", nobody, false},
+			{"anonymous", nil, false},
+		}
+		for _, c := range cases {
+			if c.auth == nil && c.name != "anonymous" {
+				// 仅超级管理员可访问的路由没有对应的授权用户
+				c.auth = nobody
+			}
+			t.Run(c.name+" "+route.method+" "+strings.SplitN(route.url, "?", 2)[0], func(t *testing.T) {
+				rec := server.request(t, route.method, route.url, route.body, c.auth)
+				denied := rec.Code == http.StatusUnauthorized || rec.Code == http.StatusForbidden
+				if denied == c.allowed {
+					t.Errorf("expected allowed=%v, got %d: %s", c.allowed, rec.Code, rec.Body.String())
+				}
+			})
+		}
+	}
+}
@@ -2,15 +2,19 @@ package controller
 
 import (
 	"bless-activity/model"
+	"bless-activity/service/point_import"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 type PointController struct {
@@ -20,6 +24,8 @@ type PointController struct {
 	group  *router.RouterGroup[*core.RequestEvent]
 	app    core.App
 	logger *slog.Logger
+
+	pointImport *point_import.Service
 }
 
 func NewPointController(event *core.ServeEvent, group *router.RouterGroup[*core.RequestEvent], base *BaseController) *PointController {
@@ -33,6 +39,8 @@ func NewPointController(event *core.ServeEvent, group *router.RouterGroup[*core.
 		group:          group,
 		app:            event.App,
 		logger:         logger,
+
+		pointImport: point_import.NewService(event.App, base.fishPiSdk),
 	}
 
 	controller.registerRoutes()
@@ -41,14 +49,21 @@ func NewPointController(event *core.ServeEvent, group *router.RouterGroup[*core.
 }
 
 func (controller *PointController) registerRoutes() {
+	// 批量创建积分记录，可按活动授权
+	activityGroup := controller.group.Group("/admin/point").Bind(
+		controller.RequirePermission(model.PermissionPointDistribute, ActivityScope),
+	)
+	activityGroup.POST("/batch/create", controller.BatchCreate)
+	// 从 csv/xlsx 导入积分记录
+	activityGroup.POST("/import", controller.Import)
+
+	// 其余接口涉及全部积分记录，需要全局授权
 	group := controller.group.Group("/admin/point").Bind(
-		RequireAdminRole(),
+		controller.RequirePermission(model.PermissionPointDistribute, nil),
 	)
 
 	// 积分记录列表
 	group.GET("/list", controller.List)
-	// 批量创建积分记录
-	group.POST("/batch/create", controller.BatchCreate)
 	// 批量发放积分
 	group.POST("/batch/distribute", controller.BatchDistribute)
 	// 批量重试发放
@@ -57,6 +72,16 @@ func (controller *PointController) registerRoutes() {
 	group.DELETE("/delete/{id}", controller.Delete)
 	// 批量删除积分记录
 	group.POST("/batch/delete", controller.BatchDelete)
+	// 设置或调整定时发放
+	group.POST("/schedule", controller.Schedule)
+	// 取消定时发放
+	group.POST("/schedule/cancel", controller.CancelSchedule)
+	// 大额发放审批列表
+	group.GET("/approvals", controller.ListApprovals)
+	// 审批通过并发放
+	group.POST("/approval/approve", controller.ApproveDistribution)
+	// 驳回发放
+	group.POST("/approval/reject", controller.RejectDistribution)
 }
 
 func (controller *PointController) makeActionLogger(action string) *slog.Logger {
@@ -136,15 +161,20 @@ func (controller *PointController) List(event *core.RequestEvent) error {
 
 	// 构建响应
 	type pointItem struct {
-		Id      string `json:"id"`
-		Group   string `json:"group"`
-		UserId  string `json:"userId"`
-		Point   int    `json:"point"`
-		Status  string `json:"status"`
-		Memo    string `json:"memo"`
-		Created string `json:"created"`
-		Updated string `json:"updated"`
-		Expand  *struct {
+		Id         string `json:"id"`
+		Group      string `json:"group"`
+		ActivityId string `json:"activityId"`
+		UserId     string `json:"userId"`
+		Point      int    `json:"point"`
+		Status     string `json:"status"`
+		Memo       string `json:"memo"`
+		// 定时发放
+		ScheduledAt     string `json:"scheduledAt"`
+		Recurrence      string `json:"recurrence"`
+		RecurrenceUntil string `json:"recurrenceUntil"`
+		Created         string `json:"created"`
+		Updated         string `json:"updated"`
+		Expand          *struct {
 			UserId *struct {
 				Id       string `json:"id"`
 				OId      string `json:"oId"`
@@ -158,14 +188,19 @@ func (controller *PointController) List(event *core.RequestEvent) error {
 	items := make([]*pointItem, 0, len(points))
 	for _, p := range points {
 		item := &pointItem{
-			Id:      p.Id,
-			Group:   p.Group(),
-			UserId:  p.UserId(),
-			Point:   p.Point(),
-			Status:  string(p.Status()),
-			Memo:    p.Memo(),
-			Created: p.Created().String(),
-			Updated: p.Updated().String(),
+			Id:         p.Id,
+			Group:      p.Group(),
+			ActivityId: p.ActivityId(),
+			UserId:     p.UserId(),
+			Point:      p.Point(),
+			Status:     string(p.Status()),
+			Memo:       p.Memo(),
+
+			ScheduledAt:     p.ScheduledAt().String(),
+			Recurrence:      string(p.Recurrence()),
+			RecurrenceUntil: p.RecurrenceUntil().String(),
+			Created:         p.Created().String(),
+			Updated:         p.Updated().String(),
 		}
 		if user, exists := usersMap[p.UserId()]; exists {
 			item.Expand = &struct {
@@ -223,10 +258,11 @@ func (controller *PointController) BatchCreate(event *core.RequestEvent) error {
 	logger := controller.makeActionLogger("batch_create")
 
 	var req struct {
-		UserIds []string `json:"userIds"` // 用户oId列表
-		Point   int      `json:"point"`   // 积分数量
-		Memo    string   `json:"memo"`    // 备注
-		Group   string   `json:"group"`   // 分组标识
+		UserIds    []string `json:"userIds"`    // 用户oId列表
+		Point      int      `json:"point"`      // 积分数量
+		Memo       string   `json:"memo"`       // 备注
+		Group      string   `json:"group"`      // 分组标识
+		ActivityId string   `json:"activityId"` // 关联活动ID，发放时计入活动积分预算
 	}
 
 	if err := event.BindBody(&req); err != nil {
@@ -280,6 +316,7 @@ func (controller *PointController) BatchCreate(event *core.RequestEvent) error {
 		// 创建积分记录
 		pointRecord := model.NewPointFromCollection(pointCollection)
 		pointRecord.SetGroup(req.Group)
+		pointRecord.SetActivityId(req.ActivityId)
 		pointRecord.SetUserId(localUser.Id)
 		pointRecord.SetPoint(req.Point)
 		pointRecord.SetStatus(model.PointStatusPending)
@@ -308,158 +345,130 @@ func (controller *PointController) BatchCreate(event *core.RequestEvent) error {
 	})
 }
 
-// BatchDistribute 批量发放积分
-func (controller *PointController) BatchDistribute(event *core.RequestEvent) error {
-	logger := controller.makeActionLogger("batch_distribute")
+// importMaxSize 导入文件大小上限
+const importMaxSize = 5 << 20
 
-	var req struct {
-		Ids []string `json:"ids"` // 积分记录ID列表
-	}
+// Import 从 csv 或 xlsx 导入积分记录，表头需包含 username 或 oId 以及 point 列，可选 memo、group 列
+// commit 不为 true 时只返回逐行校验结果；提交时存在校验失败的行则不创建任何记录
+func (controller *PointController) Import(event *core.RequestEvent) error {
+	logger := controller.makeActionLogger("import")
 
-	if err := event.BindBody(&req); err != nil {
-		return event.BadRequestError("请求参数错误", err)
+	file, header, err := event.Request.FormFile("file")
+	if err != nil {
+		return event.BadRequestError("请上传导入文件", err)
 	}
+	defer file.Close()
 
-	if len(req.Ids) == 0 {
-		return event.BadRequestError("记录ID列表不能为空", nil)
+	if header.Size > importMaxSize {
+		return event.BadRequestError("导入文件不能超过5MB", nil)
+	}
+	data, err := io.ReadAll(io.LimitReader(file, importMaxSize+1))
+	if err != nil {
+		return event.BadRequestError("读取导入文件失败", err)
 	}
 
-	isDev := controller.app.IsDev()
-
-	var (
-		successCount int
-		failedCount  int
-		skippedCount int
-		results      []map[string]any
-	)
+	rows, err := point_import.Parse(header.Filename, data)
+	if err != nil {
+		return event.BadRequestError(err.Error(), nil)
+	}
 
-	for _, id := range req.Ids {
-		result := map[string]any{
-			"id":      id,
-			"success": false,
+	activityId := event.Request.FormValue("activityId")
+	if activityId != "" {
+		if _, err = event.App.FindRecordById(model.DbNameActivities, activityId); err != nil {
+			return event.NotFoundError("活动不存在", err)
 		}
+	}
 
-		// 查询积分记录
-		pointRecord := new(model.Point)
-		if err := event.App.RecordQuery(model.DbNamePoints).Where(dbx.HashExp{
-			model.CommonFieldId: id,
-		}).One(pointRecord); err != nil {
-			logger.Warn("积分记录不存在", slog.String("id", id))
-			result["error"] = "记录不存在"
-			failedCount++
-			results = append(results, result)
-			continue
-		}
+	report := controller.pointImport.Validate(rows, point_import.Defaults{
+		Memo:  event.Request.FormValue("memo"),
+		Group: event.Request.FormValue("group"),
+	})
 
-		// 检查状态
-		if pointRecord.Status() == model.PointStatusSuccess {
-			result["error"] = "已发放成功，无需重复发放"
-			skippedCount++
-			results = append(results, result)
-			continue
-		}
+	if event.Request.FormValue("commit") != "true" {
+		return event.JSON(http.StatusOK, map[string]any{
+			"committed": false,
+			"report":    report,
+		})
+	}
 
-		if pointRecord.Status() == model.PointStatusDistributing {
-			result["error"] = "正在发放中"
-			skippedCount++
-			results = append(results, result)
-			continue
+	created, err := controller.pointImport.Commit(report, activityId)
+	if err != nil {
+		if errors.Is(err, point_import.ErrInvalidRows) {
+			return event.JSON(http.StatusBadRequest, map[string]any{
+				"committed": false,
+				"message":   "存在校验失败的行，未创建任何记录",
+				"report":    report,
+			})
 		}
+		logger.Error("导入积分记录失败", slog.Any("err", err))
+		return event.InternalServerError("导入积分记录失败", err)
+	}
 
-		// 查询用户
-		user := new(model.User)
-		if err := event.App.RecordQuery(model.DbNameUsers).Where(dbx.HashExp{
-			model.CommonFieldId: pointRecord.UserId(),
-		}).One(user); err != nil {
-			logger.Error("用户不存在", slog.String("user_id", pointRecord.UserId()))
-			pointRecord.SetStatus(model.PointStatusFailed)
-			pointRecord.SetMemo(fmt.Sprintf("用户不存在: %v", err))
-			_ = event.App.Save(pointRecord)
-			result["error"] = "用户不存在"
-			failedCount++
-			results = append(results, result)
-			continue
-		}
+	logger.Info("导入积分记录完成",
+		slog.String("file", header.Filename),
+		slog.Int("total", report.Total),
+		slog.Int("created", created),
+		slog.Int("skipped", report.Skipped),
+	)
 
-		// 更新状态为发放中
-		pointRecord.SetStatus(model.PointStatusDistributing)
-		if err := event.App.Save(pointRecord); err != nil {
-			logger.Error("更新状态失败", slog.Any("err", err))
-			result["error"] = "更新状态失败"
-			failedCount++
-			results = append(results, result)
-			continue
-		}
+	return event.JSON(http.StatusOK, map[string]any{
+		"committed": true,
+		"created":   created,
+		"report":    report,
+	})
+}
 
-		// 构建发送给用户的memo（包含交易单号）
-		userMemo := pointRecord.Memo()
-		if userMemo != "" {
-			userMemo = fmt.Sprintf("%s 交易单号：%s", userMemo, pointRecord.Id)
-		} else {
-			userMemo = fmt.Sprintf("积分发放 交易单号：%s", pointRecord.Id)
-		}
+// BatchDistribute 批量发放积分
+func (controller *PointController) BatchDistribute(event *core.RequestEvent) error {
+	logger := controller.makeActionLogger("batch_distribute")
 
-		// 发放积分
-		if isDev {
-			logger.Warn("[DEV] 开发模式，跳过实际积分发放",
-				slog.String("user_name", user.Name()),
-				slog.Int("point", pointRecord.Point()),
-				slog.String("memo", userMemo),
-			)
-		} else {
-			resp, err := controller.fishPiSdk.PostUserEditPoints(user.Name(), pointRecord.Point(), userMemo)
-			if err != nil {
-				logger.Error("发放积分失败", slog.Any("err", err))
-				pointRecord.SetStatus(model.PointStatusFailed)
-				pointRecord.SetMemo(fmt.Sprintf("%s | 发放失败: %v", pointRecord.Memo(), err))
-				_ = event.App.Save(pointRecord)
-				result["error"] = "调用接口失败"
-				failedCount++
-				results = append(results, result)
-				continue
-			}
-			if resp.Code != 0 {
-				logger.Error("发放积分失败", slog.Any("resp", resp))
-				pointRecord.SetStatus(model.PointStatusFailed)
-				pointRecord.SetMemo(fmt.Sprintf("%s | 发放失败: %s", pointRecord.Memo(), resp.Msg))
-				_ = event.App.Save(pointRecord)
-				result["error"] = resp.Msg
-				failedCount++
-				results = append(results, result)
-				continue
-			}
+	var req struct {
+		Ids []string `json:"ids"` // 积分记录ID列表
+	}
 
-			// 增加时间间隔，防止请求过于频繁
-			time.Sleep(500 * time.Millisecond)
-		}
+	if err := event.BindBody(&req); err != nil {
+		return event.BadRequestError("请求参数错误", err)
+	}
 
-		// 发放成功
-		pointRecord.SetStatus(model.PointStatusSuccess)
-		if err := event.App.Save(pointRecord); err != nil {
-			logger.Error("更新成功状态失败", slog.Any("err", err))
-		}
+	if len(req.Ids) == 0 {
+		return event.BadRequestError("记录ID列表不能为空", nil)
+	}
 
-		result["success"] = true
-		successCount++
-		results = append(results, result)
+	if err := controller.checkPendingApproval(event, req.Ids); err != nil {
+		return err
 	}
 
-	logger.Info("批量发放积分完成",
-		slog.Int("total", len(req.Ids)),
-		slog.Int("success", successCount),
-		slog.Int("failed", failedCount),
-		slog.Int("skipped", skippedCount),
-		slog.Bool("dev_mode", isDev),
-	)
+	// 超过审批阈值的发放需要另一位管理员审批后执行
+	approvalItems, err := controller.approvalItems(req.Ids)
+	if err != nil {
+		logger.Error("查询积分记录失败", slog.Any("err", err))
+		return event.InternalServerError("查询积分记录失败", err)
+	}
+	totalPoint := 0
+	for _, item := range approvalItems {
+		totalPoint += item.Point
+	}
+	// 该管理员已设置定时、尚未发放的记录一并计入，避免拆分成定时与手动发放绕过审批
+	scheduledPoint, scheduledRecipients, err := controller.pointLedger.ScheduledTotal(event.Auth.Id, req.Ids)
+	if err != nil {
+		logger.Error("统计定时发放失败", slog.Any("err", err))
+		return event.InternalServerError("统计定时发放失败", err)
+	}
+	if controller.pointLedger.NeedsApproval(event.Auth.Id, totalPoint+scheduledPoint, len(approvalItems)+scheduledRecipients) {
+		approval, err := controller.pointLedger.RequestApproval(model.PayoutKindPoint, "", "", approvalItems, event.Auth.Id)
+		if err != nil {
+			logger.Error("提交发放审批失败", slog.Any("err", err))
+			return event.InternalServerError("提交发放审批失败", err)
+		}
+		return event.JSON(http.StatusAccepted, map[string]any{
+			"message":    "发放超过审批阈值，已提交审批，需由另一位管理员审批后执行",
+			"approvalId": approval.Id,
+			"totalPoint": approval.TotalPoint(),
+			"recipients": approval.Recipients(),
+		})
+	}
 
-	return event.JSON(http.StatusOK, map[string]any{
-		"total":    len(req.Ids),
-		"success":  successCount,
-		"failed":   failedCount,
-		"skipped":  skippedCount,
-		"dev_mode": isDev,
-		"results":  results,
-	})
+	return event.JSON(http.StatusOK, controller.pointLedger.Distribute(req.Ids, event.Auth.Id))
 }
 
 // BatchRetry 批量重试发放失败的记录
@@ -499,142 +508,166 @@ func (controller *PointController) BatchRetry(event *core.RequestEvent) error {
 		})
 	}
 
+	// 重试同样受审批阈值约束
+	approvalItems, err := controller.approvalItems(failedIds)
+	if err != nil {
+		logger.Error("查询积分记录失败", slog.Any("err", err))
+		return event.InternalServerError("查询积分记录失败", err)
+	}
+	totalPoint := 0
+	for _, item := range approvalItems {
+		totalPoint += item.Point
+	}
+	// 该管理员已设置定时、尚未发放的记录一并计入，避免拆分成定时与手动发放绕过审批
+	scheduledPoint, scheduledRecipients, err := controller.pointLedger.ScheduledTotal(event.Auth.Id, failedIds)
+	if err != nil {
+		logger.Error("统计定时发放失败", slog.Any("err", err))
+		return event.InternalServerError("统计定时发放失败", err)
+	}
+	if controller.pointLedger.NeedsApproval(event.Auth.Id, totalPoint+scheduledPoint, len(approvalItems)+scheduledRecipients) {
+		approval, err := controller.pointLedger.RequestApproval(model.PayoutKindPoint, "", "", approvalItems, event.Auth.Id)
+		if err != nil {
+			logger.Error("提交发放审批失败", slog.Any("err", err))
+			return event.InternalServerError("提交发放审批失败", err)
+		}
+		return event.JSON(http.StatusAccepted, map[string]any{
+			"message":    "重试发放超过审批阈值，已提交审批，需由另一位管理员审批后执行",
+			"approvalId": approval.Id,
+			"totalPoint": approval.TotalPoint(),
+			"recipients": approval.Recipients(),
+		})
+	}
+
 	logger.Info("开始重试发放", slog.Int("count", len(failedIds)))
 
-	// 复用 BatchDistribute 逻辑
-	return controller.batchDistributeInternal(event, failedIds)
+	// 与 BatchDistribute 一样逐条占用后发放
+	return event.JSON(http.StatusOK, controller.pointLedger.Distribute(failedIds, event.Auth.Id))
 }
 
-func (controller *PointController) batchDistributeInternal(event *core.RequestEvent, ids []string) error {
-	logger := controller.makeActionLogger("batch_distribute_internal")
+// approvalItems 冻结待发放的积分记录，已发放成功和发放中的记录不计入
+func (controller *PointController) approvalItems(ids []string) ([]model.PayoutApprovalItem, error) {
+	recordIds := make([]any, 0, len(ids))
+	for _, id := range ids {
+		recordIds = append(recordIds, id)
+	}
 
-	isDev := controller.app.IsDev()
+	var points []*model.Point
+	if err := controller.app.RecordQuery(model.DbNamePoints).
+		Where(dbx.In(model.CommonFieldId, recordIds...)).
+		AndWhere(dbx.NotIn(model.PointsFieldStatus, model.PointStatusSuccess, model.PointStatusDistributing)).
+		All(&points); err != nil {
+		return nil, err
+	}
 
-	var (
-		successCount int
-		failedCount  int
-		results      []map[string]any
-	)
+	items := make([]model.PayoutApprovalItem, 0, len(points))
+	for _, point := range points {
+		items = append(items, model.PayoutApprovalItem{
+			Id:     point.Id,
+			UserId: point.UserId(),
+			Point:  point.Point(),
+			Memo:   point.Memo(),
+		})
+	}
+	return items, nil
+}
 
+// checkPendingApproval 记录已在待审批的发放清单中时拒绝再次发放或定时，需等待审批完成
+func (controller *PointController) checkPendingApproval(event *core.RequestEvent, ids []string) error {
+	pending, err := controller.pointLedger.PendingPointApprovals()
+	if err != nil {
+		controller.logger.Error("查询待审批发放失败", slog.Any("err", err))
+		return event.InternalServerError("查询待审批发放失败", err)
+	}
 	for _, id := range ids {
-		result := map[string]any{
-			"id":      id,
-			"success": false,
+		if approvalId, ok := pending[id]; ok {
+			return event.BadRequestError(fmt.Sprintf("记录 %s 在待审批的发放 %s 中，请等待审批完成", id, approvalId), nil)
 		}
+	}
+	return nil
+}
 
-		// 查询积分记录
-		pointRecord := new(model.Point)
-		if err := event.App.RecordQuery(model.DbNamePoints).Where(dbx.HashExp{
-			model.CommonFieldId: id,
-		}).One(pointRecord); err != nil {
-			result["error"] = "记录不存在"
-			failedCount++
-			results = append(results, result)
-			continue
-		}
+// ListApprovals 积分发放审批列表
+func (controller *PointController) ListApprovals(event *core.RequestEvent) error {
+	approvals, err := controller.pointLedger.Approvals(model.PayoutKindPoint, event.Request.URL.Query().Get("status"))
+	if err != nil {
+		return event.InternalServerError("获取发放审批列表失败", err)
+	}
+	return event.JSON(http.StatusOK, map[string]any{
+		"items": approvals,
+	})
+}
 
-		// 查询用户
-		user := new(model.User)
-		if err := event.App.RecordQuery(model.DbNameUsers).Where(dbx.HashExp{
-			model.CommonFieldId: pointRecord.UserId(),
-		}).One(user); err != nil {
-			pointRecord.SetStatus(model.PointStatusFailed)
-			pointRecord.SetMemo(fmt.Sprintf("用户不存在: %v", err))
-			_ = event.App.Save(pointRecord)
-			result["error"] = "用户不存在"
-			failedCount++
-			results = append(results, result)
-			continue
-		}
+// ApproveDistribution 审批通过并发放冻结的积分记录，审批人不能是发起人
+// 审批期间被修改（用户或积分变化）或已发放的记录不会发放
+func (controller *PointController) ApproveDistribution(event *core.RequestEvent) error {
+	logger := controller.makeActionLogger("approve_distribution")
 
-		// 更新状态为发放中
-		pointRecord.SetStatus(model.PointStatusDistributing)
-		if err := event.App.Save(pointRecord); err != nil {
-			result["error"] = "更新状态失败"
-			failedCount++
-			results = append(results, result)
-			continue
-		}
+	req := new(ApprovalRequest)
+	if err := event.BindBody(req); err != nil {
+		return event.BadRequestError("请求参数错误", err)
+	}
 
-		// 构建发送给用户的memo
-		userMemo := pointRecord.Memo()
-		// 移除之前的错误信息
-		if idx := len(userMemo); idx > 0 {
-			if sepIdx := findLastIndex(userMemo, " | "); sepIdx > 0 {
-				userMemo = userMemo[:sepIdx]
-			}
-		}
-		if userMemo != "" {
-			userMemo = fmt.Sprintf("%s 交易单号：%s", userMemo, pointRecord.Id)
-		} else {
-			userMemo = fmt.Sprintf("积分发放 交易单号：%s", pointRecord.Id)
-		}
+	approval, err := controller.pointLedger.Approval(model.PayoutKindPoint, req.ApprovalId)
+	if err != nil {
+		return approvalError(event, err)
+	}
 
-		// 发放积分
-		if isDev {
-			logger.Warn("[DEV] 开发模式，跳过实际积分发放",
-				slog.String("user_name", user.Name()),
-				slog.Int("point", pointRecord.Point()),
-			)
-		} else {
-			resp, err := controller.fishPiSdk.PostUserEditPoints(user.Name(), pointRecord.Point(), userMemo)
-			if err != nil || resp.Code != 0 {
-				errMsg := ""
-				if err != nil {
-					errMsg = err.Error()
-				} else {
-					errMsg = resp.Msg
-				}
-				pointRecord.SetStatus(model.PointStatusFailed)
-				pointRecord.SetMemo(fmt.Sprintf("%s | 发放失败: %s", pointRecord.Memo(), errMsg))
-				_ = event.App.Save(pointRecord)
-				result["error"] = errMsg
-				failedCount++
-				results = append(results, result)
-				continue
-			}
+	var frozen []model.PayoutApprovalItem
+	if err = json.Unmarshal([]byte(approval.Items()), &frozen); err != nil {
+		return event.InternalServerError("解析发放清单失败", err)
+	}
 
-			time.Sleep(500 * time.Millisecond)
-		}
+	frozenIds := make([]string, 0, len(frozen))
+	for _, item := range frozen {
+		frozenIds = append(frozenIds, item.Id)
+	}
+	current, err := controller.approvalItems(frozenIds)
+	if err != nil {
+		return event.InternalServerError("查询积分记录失败", err)
+	}
+	currentMap := make(map[string]model.PayoutApprovalItem, len(current))
+	for _, item := range current {
+		currentMap[item.Id] = item
+	}
 
-		// 发放成功
-		pointRecord.SetStatus(model.PointStatusSuccess)
-		// 清理memo中的错误信息
-		originalMemo := pointRecord.Memo()
-		if sepIdx := findLastIndex(originalMemo, " | "); sepIdx > 0 {
-			pointRecord.SetMemo(originalMemo[:sepIdx])
+	var ids, changed []string
+	for _, item := range frozen {
+		if now, ok := currentMap[item.Id]; ok && now.UserId == item.UserId && now.Point == item.Point {
+			ids = append(ids, item.Id)
+		} else {
+			changed = append(changed, item.Id)
 		}
-		_ = event.App.Save(pointRecord)
+	}
 
-		result["success"] = true
-		successCount++
-		results = append(results, result)
+	if approval, err = controller.pointLedger.Approve(model.PayoutKindPoint, approval.Id, event.Auth.Id, req.Note); err != nil {
+		return approvalError(event, err)
 	}
 
-	logger.Info("批量发放完成",
-		slog.Int("total", len(ids)),
-		slog.Int("success", successCount),
-		slog.Int("failed", failedCount),
-		slog.Bool("dev_mode", isDev),
+	logger.Info("审批通过积分发放",
+		slog.String("approval_id", approval.Id),
+		slog.Int("count", len(ids)),
+		slog.Int("changed", len(changed)),
 	)
 
-	return event.JSON(http.StatusOK, map[string]any{
-		"total":    len(ids),
-		"success":  successCount,
-		"failed":   failedCount,
-		"dev_mode": isDev,
-		"results":  results,
-	})
+	result := controller.pointLedger.Distribute(ids, "")
+	result["changed"] = changed
+	controller.pointLedger.SaveApprovalResult(approval, result)
+
+	return event.JSON(http.StatusOK, result)
 }
 
-// findLastIndex 查找最后一个子串的位置
-func findLastIndex(s, substr string) int {
-	for i := len(s) - len(substr); i >= 0; i-- {
-		if s[i:i+len(substr)] == substr {
-			return i
-		}
+// RejectDistribution 驳回待审批的积分发放
+func (controller *PointController) RejectDistribution(event *core.RequestEvent) error {
+	req := new(ApprovalRequest)
+	if err := event.BindBody(req); err != nil {
+		return event.BadRequestError("请求参数错误", err)
 	}
-	return -1
+
+	approval, err := controller.pointLedger.Reject(model.PayoutKindPoint, req.ApprovalId, event.Auth.Id, req.Note)
+	if err != nil {
+		return approvalError(event, err)
+	}
+	return event.JSON(http.StatusOK, approval)
 }
 
 // Delete 删除积分记录
@@ -716,3 +749,175 @@ func (controller *PointController) BatchDelete(event *core.RequestEvent) error {
 		"skipped": skippedCount,
 	})
 }
+
+// ScheduleRequest 定时发放请求参数，ids 与 group 至少填写一个，只作用于待发放的记录
+type ScheduleRequest struct {
+	Ids             []string `json:"ids"`             // 积分记录ID列表
+	Group           string   `json:"group"`           // 分组标识，按整个分组设置
+	ScheduledAt     string   `json:"scheduledAt"`     // 发放时间
+	Recurrence      string   `json:"recurrence"`      // 重复周期，为空表示不重复
+	RecurrenceUntil string   `json:"recurrenceUntil"` // 重复截止时间，为空表示一直重复
+}
+
+// scheduleTargets 查询请求涉及的待发放记录
+func (controller *PointController) scheduleTargets(ids []string, group string) ([]*model.Point, error) {
+	query := controller.app.RecordQuery(model.DbNamePoints).
+		Where(dbx.HashExp{model.PointsFieldStatus: string(model.PointStatusPending)})
+	if len(ids) > 0 {
+		anyIds := make([]any, 0, len(ids))
+		for _, id := range ids {
+			anyIds = append(anyIds, id)
+		}
+		query = query.AndWhere(dbx.In(model.CommonFieldId, anyIds...))
+	}
+	if group != "" {
+		query = query.AndWhere(dbx.HashExp{model.PointsFieldGroup: group})
+	}
+
+	var points []*model.Point
+	if err := query.All(&points); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// Schedule 为待发放的记录或整个分组设置发放时间与重复周期，已设置的会被重新安排
+// 超过审批阈值的发放需要审批，不能定时执行；待审批清单中的记录不能定时，同一管理员未发放的定时记录累计计算
+func (controller *PointController) Schedule(event *core.RequestEvent) error {
+	logger := controller.makeActionLogger("schedule")
+
+	req := new(ScheduleRequest)
+	if err := event.BindBody(req); err != nil {
+		return event.BadRequestError("请求参数错误", err)
+	}
+	if len(req.Ids) == 0 && req.Group == "" {
+		return event.BadRequestError("记录ID列表与分组不能同时为空", nil)
+	}
+
+	scheduledAt, err := types.ParseDateTime(req.ScheduledAt)
+	if err != nil || scheduledAt.IsZero() {
+		return event.BadRequestError("发放时间格式错误", err)
+	}
+
+	recurrence := model.PointRecurrenceNone
+	if req.Recurrence != "" {
+		if recurrence, err = model.ParsePointRecurrence(req.Recurrence); err != nil {
+			return event.BadRequestError("无效的重复周期", err)
+		}
+	}
+
+	var until types.DateTime
+	if req.RecurrenceUntil != "" {
+		if until, err = types.ParseDateTime(req.RecurrenceUntil); err != nil {
+			return event.BadRequestError("重复截止时间格式错误", err)
+		}
+		if until.Time().Before(scheduledAt.Time()) {
+			return event.BadRequestError("重复截止时间不能早于发放时间", nil)
+		}
+	}
+
+	points, err := controller.scheduleTargets(req.Ids, req.Group)
+	if err != nil {
+		logger.Error("查询积分记录失败", slog.Any("err", err))
+		return event.InternalServerError("查询积分记录失败", err)
+	}
+	if len(points) == 0 {
+		return event.BadRequestError("没有可定时发放的待发放记录", nil)
+	}
+
+	ids := make([]string, 0, len(points))
+	totalPoint := 0
+	for _, p := range points {
+		ids = append(ids, p.Id)
+		totalPoint += p.Point()
+	}
+	if err = controller.checkPendingApproval(event, ids); err != nil {
+		return err
+	}
+
+	// 该管理员其余已设置定时、尚未发放的记录一并计入，避免拆分成多次定时绕过审批
+	scheduledPoint, scheduledRecipients, err := controller.pointLedger.ScheduledTotal(event.Auth.Id, ids)
+	if err != nil {
+		logger.Error("统计定时发放失败", slog.Any("err", err))
+		return event.InternalServerError("统计定时发放失败", err)
+	}
+	if controller.pointLedger.NeedsApproval(event.Auth.Id, totalPoint+scheduledPoint, len(points)+scheduledRecipients) {
+		return event.BadRequestError("发放超过审批阈值，不能定时执行，请通过审批发放", nil)
+	}
+
+	if err = controller.app.RunInTransaction(func(txApp core.App) error {
+		for _, p := range points {
+			p.SetScheduledAt(scheduledAt)
+			p.SetRecurrence(recurrence)
+			p.SetRecurrenceUntil(until)
+			p.SetScheduledBy(event.Auth.Id)
+			if err := txApp.Save(p); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		logger.Error("设置定时发放失败", slog.Any("err", err))
+		return event.InternalServerError("设置定时发放失败", err)
+	}
+
+	logger.Info("设置定时发放",
+		slog.String("group", req.Group),
+		slog.Int("count", len(points)),
+		slog.String("scheduledAt", scheduledAt.String()),
+		slog.String("recurrence", string(recurrence)),
+		slog.String("adminId", event.Auth.Id),
+	)
+
+	return event.JSON(http.StatusOK, map[string]any{
+		"scheduled":   len(points),
+		"scheduledAt": scheduledAt.String(),
+		"recurrence":  recurrence,
+	})
+}
+
+// CancelSchedule 取消待发放记录或整个分组的定时发放，记录保留为待发放，可手动发放
+func (controller *PointController) CancelSchedule(event *core.RequestEvent) error {
+	logger := controller.makeActionLogger("cancel_schedule")
+
+	req := new(ScheduleRequest)
+	if err := event.BindBody(req); err != nil {
+		return event.BadRequestError("请求参数错误", err)
+	}
+	if len(req.Ids) == 0 && req.Group == "" {
+		return event.BadRequestError("记录ID列表与分组不能同时为空", nil)
+	}
+
+	points, err := controller.scheduleTargets(req.Ids, req.Group)
+	if err != nil {
+		logger.Error("查询积分记录失败", slog.Any("err", err))
+		return event.InternalServerError("查询积分记录失败", err)
+	}
+
+	cancelled := 0
+	if err = controller.app.RunInTransaction(func(txApp core.App) error {
+		for _, p := range points {
+			if p.ScheduledAt().IsZero() {
+				continue
+			}
+			p.SetScheduledAt(types.DateTime{})
+			p.SetRecurrence(model.PointRecurrenceNone)
+			p.SetRecurrenceUntil(types.DateTime{})
+			p.SetScheduledBy("")
+			if err := txApp.Save(p); err != nil {
+				return err
+			}
+			cancelled++
+		}
+		return nil
+	}); err != nil {
+		logger.Error("取消定时发放失败", slog.Any("err", err))
+		return event.InternalServerError("取消定时发放失败", err)
+	}
+
+	logger.Info("取消定时发放", slog.String("group", req.Group), slog.Int("count", cancelled), slog.String("adminId", event.Auth.Id))
+
+	return event.JSON(http.StatusOK, map[string]any{
+		"cancelled": cancelled,
+	})
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"bless-activity/model"
+	"bless-activity/service/point_ledger"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// seedPoints 设置发放审批阈值并为 count 个用户各创建一条 point 积分的待发放记录
+func seedPoints(t *testing.T, server *testServer, count int, point int) []*core.Record {
+	server.createRecord(t, model.DbNameConfigs, map[string]any{
+		model.ConfigsFieldKey:   model.ConfigKeyPayoutApproval.String(),
+		model.ConfigsFieldValue: `{"maxPoints":100,"maxRecipients":10}`,
+	})
+
+	points := make([]*core.Record, 0, count)
+	for i := range count {
+		user := server.createUser(t, fmt.Sprintf("user-%d", i), "")
+		points = append(points, server.createRecord(t, model.DbNamePoints, map[string]any{
+			model.PointsFieldUserId: user.Id,
+			model.PointsFieldPoint:  point,
+			model.PointsFieldStatus: model.PointStatusPending.String(),
+		}))
+	}
+	return points
+}
+
+// scheduleBody 一小时后定时发放单条记录的请求体
+func scheduleBody(id string) string {
+	scheduledAt := time.Now().Add(time.Hour).UTC().Format(time.DateTime)
+	return fmt.Sprintf(`{"ids":[%q],"scheduledAt":%q}`, id, scheduledAt)
+}
+
+func TestScheduleRejectsPendingApproval(t *testing.T) {
+	server := newTestServer(t)
+	points := seedPoints(t, server, 1, 10)
+	admin := server.createUser(t, "admin", model.UserRoleAdmin)
+
+	server.createRecord(t, model.DbNamePayoutApprovals, map[string]any{
+		model.PayoutApprovalsFieldKind:   model.PayoutKindPoint.String(),
+		model.PayoutApprovalsFieldItems:  fmt.Sprintf(`[{"id":%q,"userId":"u","point":10}]`, points[0].Id),
+		model.PayoutApprovalsFieldStatus: model.PayoutApprovalStatusPending.String(),
+	})
+
+	rec := server.request(t, http.MethodPost, "/backend/admin/point/schedule", scheduleBody(points[0].Id), admin)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a point in a pending approval, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestScheduleCountsOutstandingSchedules(t *testing.T) {
+	server := newTestServer(t)
+	points := seedPoints(t, server, 2, 60)
+	admin := server.createUser(t, "admin", model.UserRoleAdmin)
+	other := server.createUser(t, "other", model.UserRoleAdmin)
+
+	if rec := server.request(t, http.MethodPost, "/backend/admin/point/schedule", scheduleBody(points[0].Id), admin); rec.Code != http.StatusOK {
+		t.Fatalf("first schedule: got %d: %s", rec.Code, rec.Body.String())
+	}
+	// 重新安排同一条记录不重复计入
+	if rec := server.request(t, http.MethodPost, "/backend/admin/point/schedule", scheduleBody(points[0].Id), admin); rec.Code != http.StatusOK {
+		t.Fatalf("reschedule: got %d: %s", rec.Code, rec.Body.String())
+	}
+	// 同一管理员拆分定时，累计超过阈值
+	if rec := server.request(t, http.MethodPost, "/backend/admin/point/schedule", scheduleBody(points[1].Id), admin); rec.Code != http.StatusBadRequest {
+		t.Fatalf("split schedule: expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := server.request(t, http.MethodPost, "/backend/admin/point/schedule", scheduleBody(points[1].Id), other); rec.Code != http.StatusOK {
+		t.Fatalf("other admin: got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestBatchDistributeClaimsOnce(t *testing.T) {
+	server := newTestServerWithConfig(t, core.BaseAppConfig{IsDev: true})
+	points := seedPoints(t, server, 1, 10)
+	admin := server.createUser(t, "admin", model.UserRoleAdmin)
+
+	// 并发的发放请求都读到待发放状态，只有先占用记录的一个能发放
+	body := fmt.Sprintf(`{"ids":[%q]}`, points[0].Id)
+	var wg sync.WaitGroup
+	var paid atomic.Int32
+	for range 8 {
+		wg.Go(func() {
+			rec := server.request(t, http.MethodPost, "/backend/admin/point/batch/distribute", body, admin)
+			var result struct {
+				Success int `json:"success"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+				t.Errorf("decode %d: %s", rec.Code, rec.Body.String())
+			}
+			paid.Add(int32(result.Success))
+		})
+	}
+	wg.Wait()
+
+	ledgers, err := server.app.CountRecords(model.DbNamePointLedgers, dbx.HashExp{model.PointLedgersFieldSourceId: points[0].Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paid.Load() != 1 || ledgers != 1 {
+		t.Errorf("expected a single payout, got %d successes and %d ledger rows", paid.Load(), ledgers)
+	}
+}
+
+func TestBatchDistributeCountsOutstandingSchedules(t *testing.T) {
+	server := newTestServer(t)
+	points := seedPoints(t, server, 2, 60)
+	admin := server.createUser(t, "admin", model.UserRoleAdmin)
+
+	if rec := server.request(t, http.MethodPost, "/backend/admin/point/schedule", scheduleBody(points[1].Id), admin); rec.Code != http.StatusOK {
+		t.Fatalf("schedule: got %d: %s", rec.Code, rec.Body.String())
+	}
+	// 手动发放与已设置的定时合计超过阈值
+	rec := server.request(t, http.MethodPost, "/backend/admin/point/batch/distribute", fmt.Sprintf(`{"ids":[%q]}`, points[0].Id), admin)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 approval request, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestScheduledDistributionHoldsForApproval(t *testing.T) {
+	server := newTestServer(t)
+	points := seedPoints(t, server, 2, 60)
+	admin := server.createUser(t, "admin", model.UserRoleAdmin)
+
+	// 第一条已到期，第二条稍后发放，合计超过阈值
+	for i, at := range []types.DateTime{types.NowDateTime().Add(-time.Minute), types.NowDateTime().Add(time.Hour)} {
+		points[i].Set(model.PointsFieldScheduledAt, at)
+		points[i].Set(model.PointsFieldRecurrence, model.PointRecurrenceDaily.String())
+		points[i].Set(model.PointsFieldScheduledBy, admin.Id)
+		if err := server.app.Save(points[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ledger := point_ledger.NewService(server.app, nil)
+	if err := ledger.Run(); err != nil {
+		t.Fatal(err)
+	}
+	runCron := func() {
+		for _, job := range server.app.Cron().Jobs() {
+			if job.Id() == model.CronKeyPointSchedule.String() {
+				job.Run()
+			}
+		}
+	}
+	runCron()
+	runCron()
+
+	approvals, err := ledger.Approvals(model.PayoutKindPoint, model.PayoutApprovalStatusPending.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(approvals) != 1 || approvals[0].Recipients() != 1 || approvals[0].RequesterId() != admin.Id {
+		t.Fatalf("expected one pending approval for the due record, got %d", len(approvals))
+	}
+
+	record, err := server.app.FindRecordById(model.DbNamePoints, points[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if point := model.NewPoint(record); point.Status() != model.PointStatusPending || !point.ScheduledAt().IsZero() || point.Recurrence() != model.PointRecurrenceNone {
+		t.Errorf("expected the held record to stay pending without a schedule, got status %s scheduledAt %s", point.Status(), point.ScheduledAt())
+	}
+	if ledgers, err := server.app.CountRecords(model.DbNamePointLedgers); err != nil || ledgers != 0 {
+		t.Errorf("expected nothing paid, got %d ledger rows (%v)", ledgers, err)
+	}
+}
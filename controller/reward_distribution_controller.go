@@ -2,23 +2,35 @@ package controller
 
 import (
 	"bless-activity/model"
+	"bless-activity/service/point_ledger"
+	"bless-activity/service/vote_jury"
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/FishPiOffical/golang-sdk/sdk"
-	"github.com/FishPiOffical/golang-sdk/types"
 	"github.com/pocketbase/dbx"
-	"github.com/pocketbase/pocketbase/apis"
 	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
 )
 
+// errPlanExecuted 发放计划已被其他请求执行
+var errPlanExecuted = errors.New("Reward plan has already been executed")
+
 type RewardDistributionController struct {
 	*BaseController
-	fishpiSdk *sdk.FishPiSDK
-	event     *core.ServeEvent
+	event *core.ServeEvent
 }
 
 func NewRewardDistributionController(event *core.ServeEvent, base *BaseController) *RewardDistributionController {
@@ -33,115 +45,248 @@ func NewRewardDistributionController(event *core.ServeEvent, base *BaseControlle
 
 func (c *RewardDistributionController) registerRoutes() {
 	// todo 发放完成后没有更改活动表中奖励发放状态 需要检查
-	rewardGroup := c.event.Router.Group("/activity-api/reward").Bind(
-		apis.RequireSuperuserAuth(),
-	)
-	rewardGroup.POST("/distribute", c.DistributeRewards)
-	rewardGroup.POST("/retry", c.RetryFailedDistributions)
+	rewardGroup := c.event.Router.Group("/activity-api/reward")
+	rewardGroup.POST("/preview", c.PreviewRewards).Bind(c.RequirePermission(model.PermissionRewardDistribute, ActivityScope))
+	rewardGroup.POST("/distribute", c.DistributeRewards).Bind(c.RequirePermission(model.PermissionRewardDistribute, ActivityScope))
+	rewardGroup.POST("/retry", c.RetryFailedDistributions).Bind(c.RequirePermission(model.PermissionRewardDistribute, ActivityScope))
+	rewardGroup.GET("/report", c.Report).Bind(c.RequirePermission(model.PermissionRewardDistribute, ActivityScope))
+	rewardGroup.GET("/approvals", c.ListApprovals).Bind(c.RequirePermission(model.PermissionRewardDistribute, nil))
+	rewardGroup.POST("/approval/approve", c.ApproveDistribution).Bind(c.RequirePermission(model.PermissionRewardDistribute, c.approvalScope))
+	rewardGroup.POST("/approval/reject", c.RejectDistribution).Bind(c.RequirePermission(model.PermissionRewardDistribute, c.approvalScope))
+}
+
+// approvalScope 审批接口按审批单关联的活动授权
+func (c *RewardDistributionController) approvalScope(event *core.RequestEvent) ([]string, error) {
+	approval, err := c.pointLedger.Approval(model.PayoutKindReward, requestValue(event, "approvalId"))
+	if err != nil {
+		// 审批单不存在等错误交由接口本身返回
+		return nil, nil
+	}
+	return []string{approval.ActivityId()}, nil
 }
 
 // DistributeRequest 发放请求参数
 type DistributeRequest struct {
 	ActivityId string `json:"activityId"`
+	PlanId     string `json:"planId"` // 预览生成的发放计划ID，为空时按当前数据重新生成计划
 }
 
 // UserRewardDistribution 用户奖励发放信息（内部使用）
 type UserRewardDistribution struct {
+	ActivityId string
+	UserId     string
+	Rank       int
+	Point      int
+	Source     model.RewardRankSource // 名次来源
+	Score      float64                // 确定名次时的得票数
+	Round      int                    // 评审团确定名次的轮次
+	Memo       string                 // 发放备注，为空时按名次生成
+	Medals     []model.RewardMedal    // 随奖励发放的勋章，仅在首次发放时生成明细
+	OperatorId string                 // 发起发放的管理员，审批通过后执行时为空
+}
+
+// rankedUser 参与排名的用户
+type rankedUser struct {
 	UserId string
 	Rank   int
-	Point  int
+	Tied   int // 并列名次的人数，未并列为1
+	Score  float64
+	Round  int
 }
 
-// DistributeRewards 发放奖励接口
-func (c *RewardDistributionController) DistributeRewards(event *core.RequestEvent) error {
-	req := new(DistributeRequest)
-	if err := event.BindBody(req); err != nil {
-		return event.BadRequestError("Invalid request body", err)
+// rankUsers 获取投票的最终名次
+// 评审团投票按最后一轮算票结果中累积的名次（含加赛轮次），同票已通过加赛决出；
+// 普通投票按有效票加权票数排序，同票时按奖励组配置的同票处理方式决定名次
+func (c *RewardDistributionController) rankUsers(vote *model.Vote, rewardGroup *model.RewardGroup) ([]rankedUser, model.RewardRankSource, error) {
+	if vote.Type() == model.VoteTypeJury {
+		ranking, _, err := c.voteJury.FinalRanking(vote.Id)
+		if err != nil {
+			return nil, model.RewardRankSourceJury, err
+		}
+		rankedUsers := make([]rankedUser, 0, len(ranking.Settled))
+		for _, entry := range ranking.Settled {
+			rankedUsers = append(rankedUsers, rankedUser{
+				UserId: entry.UserId,
+				Rank:   entry.Rank,
+				Tied:   1,
+				Score:  entry.Votes,
+				Round:  entry.Round,
+			})
+		}
+		return rankedUsers, model.RewardRankSourceJury, nil
 	}
 
-	// 验证活动是否存在
+	// 从VoteLog中统计加权得票数和最后一张票的时间 (只统计有效票)
+	voteStats, err := c.voteResult.Scores(vote.Id)
+	if err != nil {
+		return nil, model.RewardRankSourceVoteLogs, err
+	}
+
+	userIds := make([]string, 0, len(voteStats))
+	for userId := range voteStats {
+		userIds = append(userIds, userId)
+	}
+	// 得票数从高到低，同票的用户按ID排序，保证抽签的输入稳定
+	slices.SortFunc(userIds, func(a, b string) int {
+		if diff := cmp.Compare(voteStats[b].Votes, voteStats[a].Votes); diff != 0 {
+			return diff
+		}
+		return cmp.Compare(a, b)
+	})
+
+	policy := rewardGroup.TiePolicy()
+	random := tieRandom(rewardGroup.TieSeed(), vote.Id)
+
+	rankedUsers := make([]rankedUser, 0, len(userIds))
+	for start := 0; start < len(userIds); {
+		end := start + 1
+		for end < len(userIds) && voteStats[userIds[end]].Votes == voteStats[userIds[start]].Votes {
+			end++
+		}
+		tied := userIds[start:end]
+		position := start + 1 // 排名从1开始
+
+		switch policy {
+		case model.RewardTiePolicyLastVoteTime:
+			// 票数相同时按最后一张票的时间从早到晚
+			slices.SortStableFunc(tied, func(a, b string) int {
+				return voteStats[a].LastVoteTime.Compare(voteStats[b].LastVoteTime)
+			})
+		case model.RewardTiePolicyRandom:
+			random.Shuffle(len(tied), func(i, j int) {
+				tied[i], tied[j] = tied[j], tied[i]
+			})
+		}
+
+		for i, userId := range tied {
+			ranked := rankedUser{
+				UserId: userId,
+				Rank:   position + i,
+				Tied:   1,
+				Score:  voteStats[userId].Votes,
+			}
+			if policy == model.RewardTiePolicyShared || policy == model.RewardTiePolicySplit {
+				ranked.Rank = position
+				ranked.Tied = len(tied)
+			}
+			rankedUsers = append(rankedUsers, ranked)
+		}
+
+		start = end
+	}
+	return rankedUsers, model.RewardRankSourceVoteLogs, nil
+}
+
+// tieRandom 根据奖励组的公开种子和投票ID生成确定的随机数，相同输入的抽签结果可复现
+func tieRandom(seed string, voteId string) *rand.Rand {
+	sum := sha256.Sum256([]byte(seed + "|" + voteId))
+	return rand.New(rand.NewPCG(binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:16])))
+}
+
+// rankReward 获取名次对应的奖励配置，参与奖配置不参与名次匹配
+func rankReward(rewards []*model.Reward, rank int) *model.Reward {
+	for _, reward := range rewards {
+		if !reward.HasPrize() || reward.Max() == 0 {
+			continue // 跳过无奖励配置和参与奖配置
+		}
+		if rank >= reward.Min() && rank <= reward.Max() {
+			return reward
+		}
+	}
+	return nil
+}
+
+// rewardMemo 生成发放备注，发放时再附加交易单号
+func rewardMemo(voteName string, rank int) string {
+	// 参与奖（rank=0）显示"感谢参与"
+	if rank == 0 {
+		return fmt.Sprintf("感谢参与活动《%s》", voteName)
+	}
+	return fmt.Sprintf("您在活动《%s》中取得第%d名", voteName, rank)
+}
+
+// findActivity 查询活动，失败时返回可直接响应的错误
+func (c *RewardDistributionController) findActivity(event *core.RequestEvent, activityId string) (*model.Activity, error) {
 	activity := model.NewActivity(nil)
 	if err := c.app.RecordQuery(model.DbNameActivities).
-		AndWhere(dbx.HashExp{model.CommonFieldId: req.ActivityId}).
+		AndWhere(dbx.HashExp{model.CommonFieldId: activityId}).
 		One(activity); err != nil {
-		return event.NotFoundError("Activity not found", err)
+		return nil, event.NotFoundError("Activity not found", err)
 	}
+	return activity, nil
+}
 
-	logger := c.app.Logger().With(
-		slog.String("controller", "RewardDistribution"),
-		slog.String("action", "DistributeRewards"),
-		slog.String("activityId", req.ActivityId),
-	)
+// findPlan 查询尚未执行的发放计划，失败时返回可直接响应的错误
+func (c *RewardDistributionController) findPlan(event *core.RequestEvent, activityId string, planId string) (*model.RewardPlan, []model.RewardPlanItem, error) {
+	plan := model.NewRewardPlan(nil)
+	if err := c.app.RecordQuery(model.DbNameRewardPlans).
+		AndWhere(dbx.HashExp{
+			model.CommonFieldId:              planId,
+			model.RewardPlansFieldActivityId: activityId,
+		}).
+		One(plan); err != nil {
+		return nil, nil, event.NotFoundError("Reward plan not found", err)
+	}
+	if plan.Status() != model.RewardPlanStatusPreview {
+		return nil, nil, event.BadRequestError(errPlanExecuted.Error(), nil)
+	}
 
+	var items []model.RewardPlanItem
+	if err := json.Unmarshal([]byte(plan.Items()), &items); err != nil {
+		return nil, nil, event.InternalServerError("Failed to parse reward plan", err)
+	}
+	return plan, items, nil
+}
+
+// createPlan 按名次、奖励配置和参与奖规则生成发放计划并保存，不调用摸鱼派接口
+// 返回的错误可直接作为接口响应
+func (c *RewardDistributionController) createPlan(event *core.RequestEvent, activity *model.Activity, logger *slog.Logger) (*model.RewardPlan, []model.RewardPlanItem, error) {
 	// 获取活动关联的投票
 	voteId := activity.GetVoteId()
 	if voteId == "" {
-		return event.BadRequestError("Activity has no vote associated", nil)
+		return nil, nil, event.BadRequestError("Activity has no vote associated", nil)
 	}
 
 	vote := model.NewVote(nil)
 	if err := c.app.RecordQuery(model.DbNameVotes).
 		AndWhere(dbx.HashExp{model.CommonFieldId: voteId}).
 		One(vote); err != nil {
-		return event.NotFoundError("Vote not found", err)
+		return nil, nil, event.NotFoundError("Vote not found", err)
 	}
 
 	// 获取活动关联的奖励组
 	rewardGroupId := activity.GetRewardGroupId()
 	if rewardGroupId == "" {
-		return event.BadRequestError("Activity has no reward group associated", nil)
+		return nil, nil, event.BadRequestError("Activity has no reward group associated", nil)
+	}
+
+	rewardGroup := model.NewRewardGroup(nil)
+	if err := c.app.RecordQuery(model.DbNameRewardGroups).
+		AndWhere(dbx.HashExp{model.CommonFieldId: rewardGroupId}).
+		One(rewardGroup); err != nil {
+		return nil, nil, event.NotFoundError("Reward group not found", err)
 	}
 
 	logger = logger.With(slog.String("voteId", voteId), slog.String("rewardGroupId", rewardGroupId))
 
-	// 从VoteLog中统计得票数 (只统计有效票)
-	records, err := c.app.FindRecordsByFilter(
-		model.DbNameVoteLogs,
-		"voteId = {:voteId} && valid = {:valid}",
-		"",
-		0,
-		0,
-		map[string]any{
-			"voteId": voteId,
-			"valid":  model.VoteLogValidValid,
-		},
-	)
+	// 按投票类型获取名次：评审团投票使用算票结果，普通投票统计有效票
+	rankedUsers, source, err := c.rankUsers(vote, rewardGroup)
 	if err != nil {
-		logger.Error("Failed to fetch vote logs", slog.Any("error", err))
-		return event.InternalServerError("Failed to fetch vote logs", err)
-	}
-
-	// 统计每个用户获得的有效票数和最后一张票的时间
-	type voteInfo struct {
-		count        int
-		lastVoteTime time.Time
-	}
-	voteStats := make(map[string]*voteInfo)
-	for _, record := range records {
-		voteLog := model.NewVoteLog(record)
-		toUserId := voteLog.ToUserId()
-		created := voteLog.Created().Time()
-
-		if info, exists := voteStats[toUserId]; exists {
-			info.count++
-			// 更新最后一张票的时间（取最晚的时间）
-			if created.After(info.lastVoteTime) {
-				info.lastVoteTime = created
-			}
-		} else {
-			voteStats[toUserId] = &voteInfo{
-				count:        1,
-				lastVoteTime: created,
-			}
+		if errors.Is(err, vote_jury.ErrJuryNoResult) || errors.Is(err, vote_jury.ErrJuryNotFinished) {
+			return nil, nil, event.BadRequestError(err.Error(), err)
 		}
+		logger.Error("Failed to rank users", slog.Any("error", err))
+		return nil, nil, event.InternalServerError("Failed to rank users", err)
 	}
 
-	if len(voteStats) == 0 {
-		return event.BadRequestError("No votes found for this vote", nil)
+	if len(rankedUsers) == 0 {
+		return nil, nil, event.BadRequestError("No votes found for this vote", nil)
 	}
 
+	logger = logger.With(slog.String("source", source.String()))
+
 	// 从数据库获取奖励配置
-	var rewardRecords []*core.Record
+	var rewardRecords []*model.Reward
 	err = c.app.RecordQuery(model.DbNameRewards).
 		AndWhere(dbx.HashExp{model.RewardsFieldRewardGroupId: rewardGroupId}).
 		OrderBy("min ASC").
@@ -149,157 +294,308 @@ func (c *RewardDistributionController) DistributeRewards(event *core.RequestEven
 
 	if err != nil {
 		logger.Error("Failed to fetch reward config", slog.Any("error", err))
-		return event.InternalServerError("Failed to fetch reward config", err)
+		return nil, nil, event.InternalServerError("Failed to fetch reward config", err)
 	}
 
 	if len(rewardRecords) == 0 {
-		return event.BadRequestError("No reward configuration found for this vote", nil)
+		return nil, nil, event.BadRequestError("No reward configuration found for this vote", nil)
 	}
 
-	// 按得票数排序，票数相同时按最后一张票的时间排序
-	type userVote struct {
-		userId       string
-		votes        int
-		lastVoteTime time.Time
+	// 查找参与奖配置（min > 0 且 max = 0）
+	var participationReward *model.Reward
+	for _, reward := range rewardRecords {
+		if reward.Min() > 0 && reward.Max() == 0 && reward.HasPrize() {
+			participationReward = reward
+			break
+		}
 	}
-	var userVotes []userVote
-	for userId, info := range voteStats {
-		userVotes = append(userVotes, userVote{
-			userId:       userId,
-			votes:        info.count,
-			lastVoteTime: info.lastVoteTime,
+
+	// 构建用户奖励列表，根据排名范围匹配奖励
+	var items []model.RewardPlanItem
+	rankedUserIds := make(map[string]bool) // 记录已获得名次奖励的用户
+
+	for _, ranked := range rankedUsers {
+		// 查找匹配的名次奖励配置（min <= rank <= max，且 max > 0）
+		reward := rankReward(rewardRecords, ranked.Rank)
+		if reward == nil {
+			continue
+		}
+		point := reward.Point()
+		if rewardGroup.TiePolicy() == model.RewardTiePolicySplit && ranked.Tied > 1 {
+			// 平分并列用户所占名次的奖励积分，向下取整；勋章无法平分，按并列名次发放
+			point = 0
+			for rank := ranked.Rank; rank < ranked.Rank+ranked.Tied; rank++ {
+				if tiedReward := rankReward(rewardRecords, rank); tiedReward != nil {
+					point += tiedReward.Point()
+				}
+			}
+			point /= ranked.Tied
+		}
+
+		items = append(items, model.RewardPlanItem{
+			UserId: ranked.UserId,
+			Rank:   ranked.Rank,
+			Tied:   ranked.Tied,
+			Point:  point,
+			Medals: reward.Medals(),
+			Score:  ranked.Score,
+			Round:  ranked.Round,
+			Source: source,
 		})
+		rankedUserIds[ranked.UserId] = true
 	}
 
-	// 排序：得票数从高到低，票数相同时按最后一张票的时间从早到晚
-	for i := 0; i < len(userVotes); i++ {
-		for j := i + 1; j < len(userVotes); j++ {
-			// 如果票数不同，按票数降序
-			if userVotes[j].votes > userVotes[i].votes {
-				userVotes[i], userVotes[j] = userVotes[j], userVotes[i]
-			} else if userVotes[j].votes == userVotes[i].votes {
-				// 票数相同时，按最后一张票的时间升序（越早越靠前）
-				if userVotes[j].lastVoteTime.Before(userVotes[i].lastVoteTime) {
-					userVotes[i], userVotes[j] = userVotes[j], userVotes[i]
-				}
+	// 参与奖：从articles表获取所有参与活动的用户，只给未获得名次奖励的用户发放
+	if participationReward != nil {
+		var articles []*model.Article
+		if err = c.app.RecordQuery(model.DbNameArticles).
+			AndWhere(dbx.HashExp{model.ArticlesFieldActivityId: activity.Id}).
+			All(&articles); err != nil {
+			logger.Error("Failed to fetch articles for participation reward", slog.Any("error", err))
+		}
+		for _, article := range articles {
+			if rankedUserIds[article.UserId()] {
+				continue
 			}
+			rankedUserIds[article.UserId()] = true
+			items = append(items, model.RewardPlanItem{
+				UserId: article.UserId(),
+				Rank:   0, // 参与奖名次为0
+				Tied:   1,
+				Point:  participationReward.Point(),
+				Medals: participationReward.Medals(),
+				Source: source,
+			})
 		}
 	}
 
-	// 查找参与奖配置（min > 0 且 max = 0）
-	var participationReward *model.Reward
-	for _, rec := range rewardRecords {
-		reward := model.NewReward(rec)
-		logger.Debug("Checking reward config",
-			slog.Int("min", reward.Min()),
-			slog.Int("max", reward.Max()),
-			slog.Int("point", reward.Point()))
-
-		if reward.Min() > 0 && reward.Max() == 0 && reward.Point() > 0 {
-			participationReward = reward
-			logger.Info("Found participation reward config",
-				slog.Int("min", reward.Min()),
-				slog.Int("max", reward.Max()),
-				slog.Int("point", reward.Point()))
-			break
+	if len(items) == 0 {
+		return nil, nil, event.BadRequestError("No users eligible for rewards based on config", nil)
+	}
+
+	// 已成功发放的用户
+	var paidRecords []*model.RewardDistribution
+	if err = c.app.RecordQuery(model.DbNameRewardDistributions).
+		AndWhere(dbx.HashExp{
+			model.RewardDistributionsFieldVoteId: voteId,
+			model.RewardDistributionsFieldStatus: model.DistributionStatusSuccess,
+		}).
+		All(&paidRecords); err != nil {
+		logger.Error("Failed to fetch paid distributions", slog.Any("error", err))
+		return nil, nil, event.InternalServerError("Failed to fetch paid distributions", err)
+	}
+	paid := make(map[string]bool, len(paidRecords))
+	for _, record := range paidRecords {
+		paid[record.UserId()] = true
+	}
+
+	users := c.Users(event)
+	for _, item := range items {
+		users.Add(item.UserId)
+	}
+
+	totalPoint := 0
+	for i := range items {
+		item := &items[i]
+		item.Memo = rewardMemo(vote.Name(), item.Rank)
+		item.Paid = paid[item.UserId]
+		if user := users.Get(item.UserId); user != nil {
+			item.Name = user.Name()
+			item.Nickname = user.Nickname()
+		}
+		if !item.Paid {
+			totalPoint += item.Point
 		}
 	}
 
-	// 构建用户奖励列表，根据排名范围匹配奖励
-	var usersToReward []UserRewardDistribution
-	rankedUserIds := make(map[string]bool) // 记录已获得名次奖励的用户
+	collection, err := c.app.FindCollectionByNameOrId(model.DbNameRewardPlans)
+	if err != nil {
+		return nil, nil, event.InternalServerError("Failed to find reward plan collection", err)
+	}
+	itemsJson, _ := json.Marshal(items)
+
+	plan := model.NewRewardPlanFromCollection(collection)
+	plan.SetActivityId(activity.Id)
+	plan.SetVoteId(voteId)
+	plan.SetSource(source)
+	plan.SetTiePolicy(rewardGroup.TiePolicy())
+	plan.SetItems(string(itemsJson))
+	plan.SetTotalPoint(totalPoint)
+	plan.SetStatus(model.RewardPlanStatusPreview)
+	if err = c.app.Save(plan); err != nil {
+		logger.Error("Failed to save reward plan", slog.Any("error", err))
+		return nil, nil, event.InternalServerError("Failed to save reward plan", err)
+	}
 
-	for rank, uv := range userVotes {
-		rankNum := rank + 1 // 排名从1开始
-		matched := false
+	return plan, items, nil
+}
 
-		// 查找匹配的名次奖励配置（min <= rankNum <= max，且 max > 0）
-		for _, rec := range rewardRecords {
-			reward := model.NewReward(rec)
-			if reward.Point() == 0 || reward.Max() == 0 {
-				continue // 跳过无奖励配置和参与奖配置
-			}
-			if rankNum >= reward.Min() && rankNum <= reward.Max() {
-				usersToReward = append(usersToReward, UserRewardDistribution{
-					UserId: uv.userId,
-					Rank:   rankNum,
-					Point:  reward.Point(),
-				})
-				rankedUserIds[uv.userId] = true
-				matched = true
-				break
-			}
+// PreviewRewards 预览奖励发放计划，不发放积分
+// 生成的计划会被保存，发放接口传入 planId 时按该计划发放
+func (c *RewardDistributionController) PreviewRewards(event *core.RequestEvent) error {
+	req := new(DistributeRequest)
+	if err := event.BindBody(req); err != nil {
+		return event.BadRequestError("Invalid request body", err)
+	}
+
+	activity, err := c.findActivity(event, req.ActivityId)
+	if err != nil {
+		return err
+	}
+
+	logger := c.app.Logger().With(
+		slog.String("controller", "RewardDistribution"),
+		slog.String("action", "PreviewRewards"),
+		slog.String("activityId", req.ActivityId),
+	)
+
+	plan, items, err := c.createPlan(event, activity, logger)
+	if err != nil {
+		return err
+	}
+
+	return event.JSON(http.StatusOK, map[string]any{
+		"planId":        plan.Id,
+		"source":        plan.Source(),
+		"tiePolicy":     plan.TiePolicy(),
+		"tiePolicyDesc": plan.TiePolicy().Description(),
+		"items":         items,
+		"totalUsers":    len(items),
+		"totalPoint":    plan.TotalPoint(),
+	})
+}
+
+// DistributeRewards 发放奖励接口
+func (c *RewardDistributionController) DistributeRewards(event *core.RequestEvent) error {
+	req := new(DistributeRequest)
+	if err := event.BindBody(req); err != nil {
+		return event.BadRequestError("Invalid request body", err)
+	}
+
+	// 验证活动是否存在
+	activity, err := c.findActivity(event, req.ActivityId)
+	if err != nil {
+		return err
+	}
+
+	logger := c.app.Logger().With(
+		slog.String("controller", "RewardDistribution"),
+		slog.String("action", "DistributeRewards"),
+		slog.String("activityId", req.ActivityId),
+	)
+
+	// 按预览的计划发放，未指定计划时按当前数据生成计划
+	var plan *model.RewardPlan
+	var items []model.RewardPlanItem
+	if req.PlanId != "" {
+		if plan, items, err = c.findPlan(event, activity.Id, req.PlanId); err != nil {
+			return err
 		}
+	} else if plan, items, err = c.createPlan(event, activity, logger); err != nil {
+		return err
+	}
 
-		// 如果没有匹配到名次奖励，且存在参与奖配置，则标记为参与奖候选
-		if !matched && participationReward != nil {
-			// 暂不添加，稍后统一处理参与奖
+	logger = logger.With(slog.String("voteId", plan.VoteId()), slog.String("planId", plan.Id))
+
+	// 整个计划超出活动积分预算时拒绝发放
+	if err = c.pointLedger.CheckBudget(activity.Id, plan.TotalPoint()); err != nil {
+		if errors.Is(err, point_ledger.ErrBudgetExceeded) {
+			return event.BadRequestError(err.Error(), nil)
 		}
+		return event.InternalServerError("Failed to check point budget", err)
 	}
 
-	// 发放参与奖：从articles表获取所有参与活动的用户
-	if participationReward != nil {
-		// 获取所有提交文章的用户（参与活动的用户）
-		articleRecords, err := c.app.FindRecordsByFilter(
-			model.DbNameArticles,
-			"activityId = {:activityId}",
-			"",
-			0,
-			0,
-			map[string]any{
-				"activityId": req.ActivityId,
-			},
-		)
+	// 超过审批阈值的发放需要另一位管理员审批后执行
+	approvalItems := make([]model.PayoutApprovalItem, 0, len(items))
+	for _, item := range items {
+		if !item.Paid {
+			approvalItems = append(approvalItems, model.PayoutApprovalItem{
+				UserId: item.UserId,
+				Point:  item.Point,
+				Memo:   item.Memo,
+			})
+		}
+	}
+	if c.pointLedger.NeedsApproval(event.Auth.Id, plan.TotalPoint(), len(approvalItems)) {
+		approval, err := c.pointLedger.RequestApproval(model.PayoutKindReward, activity.Id, plan.Id, approvalItems, event.Auth.Id)
 		if err != nil {
-			logger.Error("Failed to fetch articles for participation reward", slog.Any("error", err))
-		} else {
-			logger.Info("Found articles for participation reward",
-				slog.Int("articleCount", len(articleRecords)))
-
-			// 遍历所有提交文章的用户
-			for _, articleRec := range articleRecords {
-				article := model.NewArticle(articleRec)
-				userId := article.UserId()
-
-				// 只给未获得名次奖励的用户发放参与奖
-				if !rankedUserIds[userId] {
-					usersToReward = append(usersToReward, UserRewardDistribution{
-						UserId: userId,
-						Rank:   0, // 参与奖名次为0
-						Point:  participationReward.Point(),
-					})
-					logger.Info("Added user for participation reward",
-						slog.String("userId", userId),
-						slog.Int("point", participationReward.Point()))
-				} else {
-					logger.Debug("User already has ranked reward, skipping participation reward",
-						slog.String("userId", userId))
-				}
-			}
+			logger.Error("Failed to request payout approval", slog.Any("error", err))
+			return event.InternalServerError("Failed to request payout approval", err)
+		}
+		return event.JSON(http.StatusAccepted, map[string]any{
+			"message":    "发放超过审批阈值，已提交审批，需由另一位管理员审批后执行",
+			"approvalId": approval.Id,
+			"planId":     plan.Id,
+			"totalPoint": approval.TotalPoint(),
+			"recipients": approval.Recipients(),
+		})
+	}
+
+	result, err := c.executePlan(activity, plan, items, event.Auth.Id, logger)
+	if err != nil {
+		if errors.Is(err, errPlanExecuted) {
+			return event.BadRequestError(err.Error(), nil)
 		}
+		return event.InternalServerError("Failed to execute reward plan", err)
 	}
+	return event.JSON(http.StatusOK, result)
+}
 
-	if len(usersToReward) == 0 {
-		return event.BadRequestError("No users eligible for rewards based on config", nil)
+// executePlan 按发放计划逐个发放奖励，operatorId 为发起发放的管理员，审批通过后执行时为空
+func (c *RewardDistributionController) executePlan(activity *model.Activity, plan *model.RewardPlan, items []model.RewardPlanItem, operatorId string, logger *slog.Logger) (map[string]any, error) {
+	voteId := plan.VoteId()
+
+	// 先以条件更新占用计划，并发请求中只有一个能把预览中的计划标记为已执行，避免同一计划被重复发放
+	executedAt := types.NowDateTime()
+	res, err := c.app.DB().Update(model.DbNameRewardPlans, dbx.Params{
+		model.RewardPlansFieldStatus:     model.RewardPlanStatusExecuted.String(),
+		model.RewardPlansFieldExecutedAt: executedAt.String(),
+	}, dbx.HashExp{
+		model.CommonFieldId:          plan.Id,
+		model.RewardPlansFieldStatus: model.RewardPlanStatusPreview.String(),
+	}).Execute()
+	if err != nil {
+		logger.Error("Failed to update reward plan status", slog.Any("error", err))
+		return nil, err
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return nil, err
+	} else if affected != 1 {
+		logger.Warn("Reward plan already claimed by another request")
+		return nil, errPlanExecuted
 	}
+	plan.SetStatus(model.RewardPlanStatusExecuted)
+	plan.SetExecutedAt(executedAt)
 
 	logger.Info("Starting reward distribution",
-		slog.Int("totalVoters", len(voteStats)),
-		slog.Int("rewardRecipients", len(usersToReward)))
+		slog.Int("rewardRecipients", len(items)),
+		slog.Int("totalPoint", plan.TotalPoint()))
 
 	// 更新活动状态为发放中
 	activity.SetRewardDistributionStatus(model.DistributionStatusDistributing)
-	if err = c.app.Save(activity); err != nil {
+	if err := c.app.Save(activity); err != nil {
 		logger.Error("Failed to update activity status", slog.Any("error", err))
-		return event.InternalServerError("Failed to update activity status", err)
+		return nil, err
 	}
 
 	// 开始发放流程
 	successCount := 0
 	failedCount := 0
 
-	for _, user := range usersToReward {
-		if err = c.distributeToUser(voteId, user, logger); err != nil {
+	for _, item := range items {
+		user := UserRewardDistribution{
+			ActivityId: activity.Id,
+			UserId:     item.UserId,
+			Rank:       item.Rank,
+			Point:      item.Point,
+			Source:     item.Source,
+			Score:      item.Score,
+			Round:      item.Round,
+			Memo:       item.Memo,
+			Medals:     item.Medals,
+			OperatorId: operatorId,
+		}
+		if err := c.distributeToUser(voteId, user, logger); err != nil {
 			logger.Error("Failed to distribute to user",
 				slog.String("userId", user.UserId),
 				slog.Any("error", err))
@@ -320,7 +616,7 @@ func (c *RewardDistributionController) DistributeRewards(event *core.RequestEven
 		activity.SetRewardDistributionStatus(model.DistributionStatusDistributing)
 	}
 
-	if err = c.app.Save(activity); err != nil {
+	if err := c.app.Save(activity); err != nil {
 		logger.Error("Failed to update final activity status", slog.Any("error", err))
 	}
 
@@ -328,12 +624,13 @@ func (c *RewardDistributionController) DistributeRewards(event *core.RequestEven
 		slog.Int("success", successCount),
 		slog.Int("failed", failedCount))
 
-	return event.JSON(http.StatusOK, map[string]any{
+	return map[string]any{
+		"planId":         plan.Id,
 		"success":        successCount,
 		"failed":         failedCount,
-		"totalUsers":     len(usersToReward),
+		"totalUsers":     len(items),
 		"activityStatus": activity.GetRewardDistributionStatus(),
-	})
+	}, nil
 }
 
 // distributeToUser 为单个用户发放奖励(幂等性处理)
@@ -371,6 +668,10 @@ func (c *RewardDistributionController) distributeToUser(voteId string, userRewar
 		record.SetUserId(userReward.UserId)
 		record.SetRank(userReward.Rank)
 		record.SetPoint(userReward.Point)
+		record.SetSource(userReward.Source)
+		record.SetScore(userReward.Score)
+		record.SetRound(userReward.Round)
+		record.SetMedals(userReward.Medals)
 	} else {
 		// 记录存在,使用已有记录
 		record = existingRecord
@@ -409,31 +710,50 @@ func (c *RewardDistributionController) distributeToUser(voteId string, userRewar
 	}
 
 	// 构建memo：您在活动《{votes.name}》中取得第x名 交易单号：{RewardDistributions.id}
-	// 参与奖（rank=0）显示"感谢参与"
-	var memo string
-	if userReward.Rank == 0 {
-		memo = fmt.Sprintf("感谢参与活动《%s》 交易单号：%s", vote.Name(), record.Id)
-	} else {
-		memo = fmt.Sprintf("您在活动《%s》中取得第%d名 交易单号：%s", vote.Name(), userReward.Rank, record.Id)
+	memo := userReward.Memo
+	if memo == "" {
+		memo = rewardMemo(vote.Name(), userReward.Rank)
 	}
+	memo = fmt.Sprintf("%s 交易单号：%s", memo, record.Id)
 
-	// 调用摸鱼派接口发放积分
-	if !c.app.IsDev() {
-		var resp *types.SimpleResponse
-		resp, err = c.fishpiSdk.PostUserEditPoints(user.Name(), userReward.Point, memo)
-		if err == nil && resp.Code != 0 {
-			err = errors.New(resp.Msg)
+	// 逐项发放积分和勋章，已成功的明细不会重复发放
+	items, err := c.distributionItems(record, userReward)
+	if err != nil {
+		record.SetStatus(model.DistributionStatusFailed)
+		record.SetMemo(fmt.Sprintf("Failed to prepare items: %v", err))
+		if err1 := c.app.Save(record); err1 != nil {
+			logger.Error("Failed to save failed status", slog.Any("error", err1))
 		}
+		return fmt.Errorf("failed to prepare items: %w", err)
 	}
 
-	if err != nil {
-		// 发放失败
+	var failures []string
+	for _, item := range items {
+		if item.Status() == model.DistributionStatusSuccess {
+			continue
+		}
+
+		if err = c.distributeItem(user, record, item, userReward, memo, logger); err != nil {
+			item.SetStatus(model.DistributionStatusFailed)
+			item.SetError(err.Error())
+			failures = append(failures, fmt.Sprintf("%s %s: %v", item.Kind(), item.MedalId(), err))
+		} else {
+			item.SetStatus(model.DistributionStatusSuccess)
+			item.SetError("")
+		}
+		if err1 := c.app.Save(item); err1 != nil {
+			logger.Error("Failed to save item status", slog.String("itemId", item.Id), slog.Any("error", err1))
+		}
+	}
+
+	if len(failures) > 0 {
+		// 发放失败，重试时只发放失败的明细
 		record.SetStatus(model.DistributionStatusFailed)
-		record.SetMemo(fmt.Sprintf("Distribution failed: %v", err))
+		record.SetMemo(fmt.Sprintf("Distribution failed: %s", strings.Join(failures, "; ")))
 		if err1 := c.app.Save(record); err1 != nil {
 			logger.Error("Failed to save failed status", slog.Any("error", err1))
 		}
-		return fmt.Errorf("fishpi distribute failed: %w", err)
+		return fmt.Errorf("fishpi distribute failed: %s", strings.Join(failures, "; "))
 	}
 
 	// 发放成功
@@ -453,6 +773,135 @@ func (c *RewardDistributionController) distributeToUser(voteId string, userRewar
 	return nil
 }
 
+// distributionItems 获取发放记录的明细，尚未生成明细时按记录中的积分和勋章在一个事务中生成
+func (c *RewardDistributionController) distributionItems(record *model.RewardDistribution, userReward UserRewardDistribution) ([]*model.RewardDistributionItem, error) {
+	var items []*model.RewardDistributionItem
+	if err := c.app.RecordQuery(model.DbNameRewardDistributionItems).
+		AndWhere(dbx.HashExp{model.RewardDistributionItemsFieldDistributionId: record.Id}).
+		All(&items); err != nil {
+		return nil, err
+	}
+	if len(items) > 0 {
+		return items, nil
+	}
+
+	collection, err := c.app.FindCollectionByNameOrId(model.DbNameRewardDistributionItems)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.Point() > 0 {
+		item := model.NewRewardDistributionItemFromCollection(collection)
+		item.SetDistributionId(record.Id)
+		item.SetKind(model.RewardItemKindPoint)
+		item.SetPoint(record.Point())
+		items = append(items, item)
+	}
+	for _, medal := range userReward.Medals {
+		item := model.NewRewardDistributionItemFromCollection(collection)
+		item.SetDistributionId(record.Id)
+		item.SetKind(model.RewardItemKindMedal)
+		item.SetMedalId(medal.MedalId)
+		item.SetData(medal.Data)
+		if medal.ExpireDays > 0 {
+			item.SetExpireTime(time.Now().AddDate(0, 0, medal.ExpireDays).UnixMilli())
+		}
+		items = append(items, item)
+	}
+
+	if err = c.app.RunInTransaction(func(txApp core.App) error {
+		for _, item := range items {
+			item.SetStatus(model.DistributionStatusPending)
+			if err := txApp.Save(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// distributeItem 调用摸鱼派接口发放单项奖励，积分通过积分流水发放并计入活动预算
+func (c *RewardDistributionController) distributeItem(user *model.User, record *model.RewardDistribution, item *model.RewardDistributionItem, userReward UserRewardDistribution, memo string, logger *slog.Logger) error {
+	if item.Kind() == model.RewardItemKindPoint {
+		err := c.pointLedger.Pay(point_ledger.Entry{
+			ActivityId:    userReward.ActivityId,
+			User:          user,
+			Point:         item.Point(),
+			Memo:          memo,
+			TransactionNo: record.Id,
+			Source:        model.PointLedgerSourceReward,
+			SourceId:      item.Id,
+			OperatorId:    userReward.OperatorId,
+		})
+		// 积分已发出但流水未结算，按成功处理，避免重试时重复发放
+		if errors.Is(err, point_ledger.ErrLedgerUnsettled) {
+			logger.Error("Point ledger not settled",
+				slog.String("userId", user.Id),
+				slog.String("itemId", item.Id),
+				slog.Any("error", err))
+			return nil
+		}
+		return err
+	}
+
+	if c.app.IsDev() {
+		return nil
+	}
+
+	resp, err := c.fishPiSdk.PostMedalAdminGrant(user.OId(), item.MedalId(), item.ExpireTime(), item.Data())
+	if err == nil && resp.Code != 0 {
+		err = errors.New(resp.Msg)
+	}
+	if err != nil {
+		return err
+	}
+
+	c.saveMedalOwner(user, item, logger)
+	return nil
+}
+
+// saveMedalOwner 勋章发放成功后同步本地勋章拥有者记录，失败时仅记录日志
+func (c *RewardDistributionController) saveMedalOwner(user *model.User, item *model.RewardDistributionItem, logger *slog.Logger) {
+	localMedal := new(model.Medal)
+	if err := c.app.RecordQuery(model.DbNameMedals).
+		AndWhere(dbx.HashExp{model.MedalsFieldMedalId: item.MedalId()}).
+		One(localMedal); err != nil {
+		logger.Warn("Local medal not found", slog.String("medalId", item.MedalId()), slog.Any("error", err))
+		return
+	}
+
+	owner := new(model.MedalOwner)
+	if err := c.app.RecordQuery(model.DbNameMedalOwners).
+		AndWhere(dbx.HashExp{
+			model.MedalOwnersFieldMedalId: localMedal.Id,
+			model.MedalOwnersFieldUserId:  user.Id,
+		}).
+		One(owner); err != nil {
+		collection, err := c.app.FindCollectionByNameOrId(model.DbNameMedalOwners)
+		if err != nil {
+			logger.Warn("Medal owner collection not found", slog.Any("error", err))
+			return
+		}
+		owner = model.NewMedalOwnerFromCollection(collection)
+		owner.SetMedalId(localMedal.Id)
+		owner.SetUserId(user.Id)
+	}
+
+	owner.SetData(item.Data())
+	owner.SetDisplay(true)
+	if item.ExpireTime() > 0 {
+		if expired, err := types.ParseDateTime(time.UnixMilli(item.ExpireTime())); err == nil {
+			owner.SetExpired(expired)
+		}
+	}
+	if err := c.app.Save(owner); err != nil {
+		logger.Warn("Failed to save medal owner", slog.String("userId", user.Id), slog.Any("error", err))
+	}
+}
+
 // RetryFailedDistributions 重试失败的发放记录
 func (c *RewardDistributionController) RetryFailedDistributions(event *core.RequestEvent) error {
 	activityId := event.Request.URL.Query().Get("activityId")
@@ -507,9 +956,15 @@ func (c *RewardDistributionController) RetryFailedDistributions(event *core.Requ
 	for _, rec := range failedRecords {
 		record := model.NewRewardDistribution(rec)
 		userReward := UserRewardDistribution{
-			UserId: record.UserId(),
-			Rank:   record.Rank(),
-			Point:  record.Point(),
+			ActivityId: activityId,
+			UserId:     record.UserId(),
+			Rank:       record.Rank(),
+			Point:      record.Point(),
+			Source:     record.Source(),
+			Score:      record.Score(),
+			Round:      record.Round(),
+			Medals:     record.Medals(),
+			OperatorId: event.Auth.Id,
 		}
 
 		if err = c.distributeToUser(voteId, userReward, logger); err != nil {
@@ -531,3 +986,146 @@ func (c *RewardDistributionController) RetryFailedDistributions(event *core.Requ
 		"stillFailed":  stillFailedCount,
 	})
 }
+
+// ApprovalRequest 发放审批请求参数
+type ApprovalRequest struct {
+	ApprovalId string `json:"approvalId"`
+	Note       string `json:"note"`
+}
+
+// ListApprovals 奖励发放审批列表
+func (c *RewardDistributionController) ListApprovals(event *core.RequestEvent) error {
+	approvals, err := c.pointLedger.Approvals(model.PayoutKindReward, event.Request.URL.Query().Get("status"))
+	if err != nil {
+		return event.InternalServerError("Failed to list approvals", err)
+	}
+	return event.JSON(http.StatusOK, map[string]any{
+		"items": approvals,
+	})
+}
+
+// ApproveDistribution 审批通过并执行冻结的发放计划，审批人不能是发起人
+func (c *RewardDistributionController) ApproveDistribution(event *core.RequestEvent) error {
+	req := new(ApprovalRequest)
+	if err := event.BindBody(req); err != nil {
+		return event.BadRequestError("Invalid request body", err)
+	}
+
+	approval, err := c.pointLedger.Approval(model.PayoutKindReward, req.ApprovalId)
+	if err != nil {
+		return approvalError(event, err)
+	}
+	if approval.Status() != model.PayoutApprovalStatusPending {
+		return event.BadRequestError(point_ledger.ErrApprovalHandled.Error(), nil)
+	}
+
+	activity, err := c.findActivity(event, approval.ActivityId())
+	if err != nil {
+		return err
+	}
+	plan, items, err := c.findPlan(event, activity.Id, approval.PlanId())
+	if err != nil {
+		return err
+	}
+
+	logger := c.app.Logger().With(
+		slog.String("controller", "RewardDistribution"),
+		slog.String("action", "ApproveDistribution"),
+		slog.String("activityId", activity.Id),
+		slog.String("approvalId", approval.Id),
+		slog.String("planId", plan.Id),
+	)
+
+	if err = c.pointLedger.CheckBudget(activity.Id, plan.TotalPoint()); err != nil {
+		if errors.Is(err, point_ledger.ErrBudgetExceeded) {
+			return event.BadRequestError(err.Error(), nil)
+		}
+		return event.InternalServerError("Failed to check point budget", err)
+	}
+
+	if approval, err = c.pointLedger.Approve(model.PayoutKindReward, approval.Id, event.Auth.Id, req.Note); err != nil {
+		return approvalError(event, err)
+	}
+
+	result, err := c.executePlan(activity, plan, items, "", logger)
+	if err != nil {
+		c.pointLedger.SaveApprovalResult(approval, map[string]any{"error": err.Error()})
+		if errors.Is(err, errPlanExecuted) {
+			return event.BadRequestError(err.Error(), nil)
+		}
+		return event.InternalServerError("Failed to execute reward plan", err)
+	}
+	c.pointLedger.SaveApprovalResult(approval, result)
+
+	return event.JSON(http.StatusOK, result)
+}
+
+// RejectDistribution 驳回待审批的奖励发放
+func (c *RewardDistributionController) RejectDistribution(event *core.RequestEvent) error {
+	req := new(ApprovalRequest)
+	if err := event.BindBody(req); err != nil {
+		return event.BadRequestError("Invalid request body", err)
+	}
+
+	approval, err := c.pointLedger.Reject(model.PayoutKindReward, req.ApprovalId, event.Auth.Id, req.Note)
+	if err != nil {
+		return approvalError(event, err)
+	}
+	return event.JSON(http.StatusOK, approval)
+}
+
+// approvalError 将发放审批的错误转换为接口响应
+func approvalError(event *core.RequestEvent, err error) error {
+	switch {
+	case errors.Is(err, point_ledger.ErrApprovalNotFound):
+		return event.NotFoundError(err.Error(), nil)
+	case errors.Is(err, point_ledger.ErrApprovalHandled), errors.Is(err, point_ledger.ErrSelfApproval):
+		return event.BadRequestError(err.Error(), nil)
+	}
+	return event.InternalServerError("发放审批处理失败", err)
+}
+
+// Report 积分对账报表，按活动对比计划、已发放与失败的积分，format=csv 时导出CSV
+func (c *RewardDistributionController) Report(event *core.RequestEvent) error {
+	query := event.Request.URL.Query()
+
+	var activityIds []string
+	if activityId := query.Get("activityId"); activityId != "" {
+		activityIds = append(activityIds, activityId)
+	}
+
+	rows, err := c.pointLedger.Report(activityIds...)
+	if err != nil {
+		return event.InternalServerError("Failed to build point report", err)
+	}
+
+	if query.Get("format") != "csv" {
+		return event.JSON(http.StatusOK, map[string]any{
+			"items": rows,
+		})
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("\uFEFF") // BOM，避免 Excel 打开中文乱码
+	writer := csv.NewWriter(buf)
+	_ = writer.Write([]string{"活动ID", "活动名称", "预算", "计划发放", "已发放", "发放失败", "未发放", "剩余预算"})
+	for _, row := range rows {
+		_ = writer.Write([]string{
+			row.ActivityId,
+			row.Name,
+			strconv.Itoa(row.Budget),
+			strconv.Itoa(row.Planned),
+			strconv.Itoa(row.Paid),
+			strconv.Itoa(row.Failed),
+			strconv.Itoa(row.Unpaid),
+			strconv.Itoa(row.Remaining),
+		})
+	}
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		return event.InternalServerError("Failed to write csv", err)
+	}
+
+	event.Response.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=point-report-%s.csv", time.Now().Format("20060102150405")))
+	return event.Blob(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"bless-activity/model"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestExecutePlanClaimsOnce(t *testing.T) {
+	server := newTestServer(t)
+
+	activityRecord := server.createRecord(t, model.DbNameActivities, map[string]any{
+		model.ActivitiesFieldName: "年终征文",
+	})
+	planRecord := server.createRecord(t, model.DbNameRewardPlans, map[string]any{
+		model.RewardPlansFieldActivityId: activityRecord.Id,
+		model.RewardPlansFieldItems:      "[]",
+		model.RewardPlansFieldStatus:     model.RewardPlanStatusPreview.String(),
+	})
+
+	// 两个请求各自读到了预览中的计划，只有先执行的一个能占用计划
+	first := model.NewRewardPlan(planRecord.Fresh())
+	second := model.NewRewardPlan(planRecord.Fresh())
+	activity := model.NewActivity(activityRecord)
+	logger := server.app.Logger()
+
+	if _, err := server.rewardDistribution.executePlan(activity, first, nil, "", logger); err != nil {
+		t.Fatalf("first execution: %v", err)
+	}
+	if _, err := server.rewardDistribution.executePlan(activity, second, nil, "", logger); !errors.Is(err, errPlanExecuted) {
+		t.Fatalf("second execution: expected errPlanExecuted, got %v", err)
+	}
+
+	record, err := server.app.FindRecordById(model.DbNameRewardPlans, planRecord.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan := model.NewRewardPlan(record); plan.Status() != model.RewardPlanStatusExecuted || plan.ExecutedAt().IsZero() {
+		t.Errorf("expected executed plan with executedAt, got status %s executedAt %s", plan.Status(), plan.ExecutedAt())
+	}
+}
+
+func TestRetryFailedDistributionGrantsMedals(t *testing.T) {
+	server := newTestServerWithConfig(t, core.BaseAppConfig{IsDev: true})
+
+	vote := server.createRecord(t, model.DbNameVotes, map[string]any{
+		model.VotesFieldName: "年终投票",
+	})
+	activity := server.createRecord(t, model.DbNameActivities, map[string]any{
+		model.ActivitiesFieldName:   "年终征文",
+		model.ActivitiesFieldVoteId: vote.Id,
+	})
+	user := server.createUser(t, "winner", "")
+	// 首次发放在生成明细前失败，重试时只能从记录中取回勋章
+	distribution := server.createRecord(t, model.DbNameRewardDistributions, map[string]any{
+		model.RewardDistributionsFieldVoteId: vote.Id,
+		model.RewardDistributionsFieldUserId: user.Id,
+		model.RewardDistributionsFieldRank:   1,
+		model.RewardDistributionsFieldPoint:  10,
+		model.RewardDistributionsFieldStatus: model.DistributionStatusFailed,
+		model.RewardDistributionsFieldMedals: `[{"medalId":"m-1","expireDays":0,"data":""}]`,
+	})
+
+	rec := server.request(t, http.MethodPost, "/activity-api/reward/retry?activityId="+activity.Id, "", server.createSuperuser(t))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("retry: got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var items []*model.RewardDistributionItem
+	if err := server.app.RecordQuery(model.DbNameRewardDistributionItems).
+		Where(dbx.HashExp{model.RewardDistributionItemsFieldDistributionId: distribution.Id}).
+		All(&items); err != nil {
+		t.Fatal(err)
+	}
+	medals := 0
+	for _, item := range items {
+		if item.Kind() == model.RewardItemKindMedal && item.MedalId() == "m-1" {
+			medals++
+		}
+	}
+	if len(items) != 2 || medals != 1 {
+		t.Errorf("expected a point item and the m-1 medal item, got %d items with %d medals", len(items), medals)
+	}
+}
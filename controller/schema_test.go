@@ -0,0 +1,439 @@
+package controller
+
+import (
+	"bless-activity/model"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// testCollections 测试使用的集合结构，与线上集合的字段一一对应
+var testCollections = map[string][]core.Field{
+	model.DbNameActivities: {
+		&core.TextField{Name: model.ActivitiesFieldName},
+		&core.TextField{Name: model.ActivitiesFieldTemplate},
+		&core.TextField{Name: model.ActivitiesFieldSlug},
+		&core.TextField{Name: model.ActivitiesFieldArticleUrl},
+		&core.TextField{Name: model.ActivitiesFieldExternalUrl},
+		&core.TextField{Name: model.ActivitiesFieldDesc},
+		&core.TextField{Name: model.ActivitiesFieldTag},
+		&core.DateField{Name: model.ActivitiesFieldStart},
+		&core.DateField{Name: model.ActivitiesFieldEnd},
+		&core.TextField{Name: model.ActivitiesFieldVoteId},
+		&core.TextField{Name: model.ActivitiesFieldRewardGroupId},
+		&core.TextField{Name: model.ActivitiesFieldRewardDistributionStatus},
+		&core.BoolField{Name: model.ActivitiesFieldHideInList},
+		&core.JSONField{Name: model.ActivitiesFieldChildActivityIds},
+		&core.TextField{Name: model.ActivitiesFieldImage},
+		&core.JSONField{Name: model.ActivitiesFieldImages},
+		&core.JSONField{Name: model.ActivitiesFieldMetadata},
+		&core.NumberField{Name: model.ActivitiesFieldPointBudget},
+		&core.AutodateField{Name: model.ActivitiesFieldCreated, OnCreate: true},
+		&core.AutodateField{Name: model.ActivitiesFieldUpdated, OnCreate: true, OnUpdate: true},
+	},
+	model.DbNameArticles: {
+		&core.TextField{Name: model.ArticlesFieldActivityId},
+		&core.TextField{Name: model.ArticlesFieldUserId},
+		&core.TextField{Name: model.ArticlesFieldTitle},
+		&core.TextField{Name: model.ArticlesFieldContent},
+		&core.TextField{Name: model.ArticlesFieldShieldId},
+		&core.TextField{Name: model.ArticlesFieldImage},
+		&core.AutodateField{Name: model.ArticlesFieldCreated, OnCreate: true},
+		&core.AutodateField{Name: model.ArticlesFieldUpdated, OnCreate: true, OnUpdate: true},
+	},
+	model.DbNameConfigs: {
+		&core.TextField{Name: model.ConfigsFieldKey},
+		&core.TextField{Name: model.ConfigsFieldValue},
+	},
+	model.DbNameMedals: {
+		&core.TextField{Name: model.MedalsFieldOId},
+		&core.TextField{Name: model.MedalsFieldMedalId},
+		&core.TextField{Name: model.MedalsFieldType},
+		&core.TextField{Name: model.MedalsFieldName},
+		&core.TextField{Name: model.MedalsFieldDescription},
+		&core.TextField{Name: model.MedalsFieldAttr},
+	},
+	model.DbNameMedalOwners: {
+		&core.TextField{Name: model.MedalOwnersFieldMedalId},
+		&core.TextField{Name: model.MedalOwnersFieldUserId},
+		&core.BoolField{Name: model.MedalOwnersFieldDisplay},
+		&core.NumberField{Name: model.MedalOwnersFieldDisplayOrder},
+		&core.TextField{Name: model.MedalOwnersFieldData},
+		&core.DateField{Name: model.MedalOwnersFieldExpired},
+		&core.AutodateField{Name: model.MedalOwnersFieldCreated, OnCreate: true},
+		&core.AutodateField{Name: model.MedalOwnersFieldUpdated, OnCreate: true, OnUpdate: true},
+	},
+	model.DbNamePayoutApprovals: {
+		&core.TextField{Name: model.PayoutApprovalsFieldKind},
+		&core.TextField{Name: model.PayoutApprovalsFieldActivityId},
+		&core.TextField{Name: model.PayoutApprovalsFieldPlanId},
+		&core.TextField{Name: model.PayoutApprovalsFieldItems},
+		&core.NumberField{Name: model.PayoutApprovalsFieldTotalPoint},
+		&core.NumberField{Name: model.PayoutApprovalsFieldRecipients},
+		&core.TextField{Name: model.PayoutApprovalsFieldStatus},
+		&core.TextField{Name: model.PayoutApprovalsFieldRequesterId},
+		&core.TextField{Name: model.PayoutApprovalsFieldApproverId},
+		&core.TextField{Name: model.PayoutApprovalsFieldNote},
+		&core.DateField{Name: model.PayoutApprovalsFieldApprovedAt},
+		&core.DateField{Name: model.PayoutApprovalsFieldExecutedAt},
+		&core.TextField{Name: model.PayoutApprovalsFieldResult},
+		&core.AutodateField{Name: model.PayoutApprovalsFieldCreated, OnCreate: true},
+		&core.AutodateField{Name: model.PayoutApprovalsFieldUpdated, OnCreate: true, OnUpdate: true},
+	},
+	model.DbNamePermissions: {
+		&core.TextField{Name: model.PermissionsFieldUserId},
+		&core.TextField{Name: model.PermissionsFieldPermission},
+		&core.TextField{Name: model.PermissionsFieldActivityId},
+		&core.TextField{Name: model.PermissionsFieldGrantedBy},
+		&core.AutodateField{Name: model.PermissionsFieldCreated, OnCreate: true},
+	},
+	model.DbNamePoints: {
+		&core.TextField{Name: model.PointsFieldGroup},
+		&core.TextField{Name: model.PointsFieldActivityId},
+		&core.TextField{Name: model.PointsFieldUserId},
+		&core.NumberField{Name: model.PointsFieldPoint},
+		&core.TextField{Name: model.PointsFieldStatus},
+		&core.TextField{Name: model.PointsFieldMemo},
+		&core.DateField{Name: model.PointsFieldScheduledAt},
+		&core.TextField{Name: model.PointsFieldRecurrence},
+		&core.DateField{Name: model.PointsFieldRecurrenceUntil},
+		&core.TextField{Name: model.PointsFieldScheduledBy},
+		&core.AutodateField{Name: model.PointsFieldCreated, OnCreate: true},
+		&core.AutodateField{Name: model.PointsFieldUpdated, OnCreate: true, OnUpdate: true},
+	},
+	model.DbNamePointLedgers: {
+		&core.TextField{Name: model.PointLedgersFieldActivityId},
+		&core.TextField{Name: model.PointLedgersFieldGroup},
+		&core.TextField{Name: model.PointLedgersFieldUserId},
+		&core.NumberField{Name: model.PointLedgersFieldPoint},
+		&core.TextField{Name: model.PointLedgersFieldMemo},
+		&core.TextField{Name: model.PointLedgersFieldTransactionNo},
+		&core.TextField{Name: model.PointLedgersFieldSource},
+		&core.TextField{Name: model.PointLedgersFieldSourceId},
+		&core.TextField{Name: model.PointLedgersFieldStatus},
+		&core.TextField{Name: model.PointLedgersFieldError},
+		&core.TextField{Name: model.PointLedgersFieldOperatorId},
+		&core.AutodateField{Name: model.PointLedgersFieldCreated, OnCreate: true},
+	},
+	model.DbNameRelArticles: {
+		&core.TextField{Name: model.RelArticlesFieldUserId},
+		&core.TextField{Name: model.RelArticlesFieldActivityId},
+		&core.TextField{Name: model.RelArticlesFieldOId},
+		&core.TextField{Name: model.RelArticlesFieldTitle},
+		&core.TextField{Name: model.RelArticlesFieldPreviewContent},
+		&core.NumberField{Name: model.RelArticlesFieldViewCount},
+		&core.NumberField{Name: model.RelArticlesFieldGoodCnt},
+		&core.NumberField{Name: model.RelArticlesFieldCommentCount},
+		&core.NumberField{Name: model.RelArticlesFieldCollectCnt},
+		&core.NumberField{Name: model.RelArticlesFieldThankCnt},
+		&core.DateField{Name: model.RelArticlesFieldCreatedAt},
+		&core.DateField{Name: model.RelArticlesFieldUpdatedAt},
+		&core.AutodateField{Name: model.RelArticlesFieldCreated, OnCreate: true},
+		&core.AutodateField{Name: model.RelArticlesFieldUpdated, OnCreate: true, OnUpdate: true},
+	},
+	model.DbNameRewards: {
+		&core.TextField{Name: model.RewardsFieldRewardGroupId},
+		&core.TextField{Name: model.RewardsFieldName},
+		&core.NumberField{Name: model.RewardsFieldMin},
+		&core.NumberField{Name: model.RewardsFieldMax},
+		&core.NumberField{Name: model.RewardsFieldPoint},
+		&core.JSONField{Name: model.RewardsFieldShieldIds},
+		&core.TextField{Name: model.RewardsFieldMedals},
+		&core.TextField{Name: model.RewardsFieldMore},
+	},
+	model.DbNameRewardDistributions: {
+		&core.TextField{Name: model.RewardDistributionsFieldVoteId},
+		&core.TextField{Name: model.RewardDistributionsFieldUserId},
+		&core.NumberField{Name: model.RewardDistributionsFieldRank},
+		&core.NumberField{Name: model.RewardDistributionsFieldPoint},
+		&core.TextField{Name: model.RewardDistributionsFieldStatus},
+		&core.TextField{Name: model.RewardDistributionsFieldMemo},
+		&core.TextField{Name: model.RewardDistributionsFieldSource},
+		&core.NumberField{Name: model.RewardDistributionsFieldScore},
+		&core.NumberField{Name: model.RewardDistributionsFieldRound},
+		&core.JSONField{Name: model.RewardDistributionsFieldMedals},
+		&core.AutodateField{Name: model.RewardDistributionsFieldCreated, OnCreate: true},
+		&core.AutodateField{Name: model.RewardDistributionsFieldUpdated, OnCreate: true, OnUpdate: true},
+	},
+	model.DbNameRewardDistributionItems: {
+		&core.TextField{Name: model.RewardDistributionItemsFieldDistributionId},
+		&core.TextField{Name: model.RewardDistributionItemsFieldKind},
+		&core.NumberField{Name: model.RewardDistributionItemsFieldPoint},
+		&core.TextField{Name: model.RewardDistributionItemsFieldMedalId},
+		&core.NumberField{Name: model.RewardDistributionItemsFieldExpireTime},
+		&core.TextField{Name: model.RewardDistributionItemsFieldData},
+		&core.TextField{Name: model.RewardDistributionItemsFieldStatus},
+		&core.TextField{Name: model.RewardDistributionItemsFieldError},
+		&core.AutodateField{Name: model.RewardDistributionItemsFieldCreated, OnCreate: true},
+		&core.AutodateField{Name: model.RewardDistributionItemsFieldUpdated, OnCreate: true, OnUpdate: true},
+	},
+	model.DbNameRewardGroups: {
+		&core.TextField{Name: model.RewardGroupsFieldName},
+		&core.TextField{Name: model.RewardGroupsFieldTiePolicy},
+		&core.TextField{Name: model.RewardGroupsFieldTieSeed},
+		&core.AutodateField{Name: model.RewardGroupsFieldCreated, OnCreate: true},
+		&core.AutodateField{Name: model.RewardGroupsFieldUpdated, OnCreate: true, OnUpdate: true},
+	},
+	model.DbNameRewardPlans: {
+		&core.TextField{Name: model.RewardPlansFieldActivityId},
+		&core.TextField{Name: model.RewardPlansFieldVoteId},
+		&core.TextField{Name: model.RewardPlansFieldSource},
+		&core.TextField{Name: model.RewardPlansFieldTiePolicy},
+		&core.TextField{Name: model.RewardPlansFieldItems},
+		&core.NumberField{Name: model.RewardPlansFieldTotalPoint},
+		&core.TextField{Name: model.RewardPlansFieldStatus},
+		&core.DateField{Name: model.RewardPlansFieldExecutedAt},
+		&core.AutodateField{Name: model.RewardPlansFieldCreated, OnCreate: true},
+		&core.AutodateField{Name: model.RewardPlansFieldUpdated, OnCreate: true, OnUpdate: true},
+	},
+	model.DbNameShields: {
+		&core.TextField{Name: model.ShieldsFieldText},
+		&core.TextField{Name: model.ShieldsFieldImg},
+		&core.TextField{Name: model.ShieldsFieldUrl},
+		&core.TextField{Name: model.ShieldsFieldBackcolor},
+		&core.TextField{Name: model.ShieldsFieldFontcolor},
+		&core.TextField{Name: model.ShieldsFieldVer},
+		&core.TextField{Name: model.ShieldsFieldScale},
+		&core.TextField{Name: model.ShieldsFieldSize},
+		&core.TextField{Name: model.ShieldsFieldBorder},
+		&core.TextField{Name: model.ShieldsFieldBarLen},
+		&core.TextField{Name: model.ShieldsFieldFontsize},
+		&core.TextField{Name: model.ShieldsFieldBarRadius},
+		&core.TextField{Name: model.ShieldsFieldShadow},
+		&core.TextField{Name: model.ShieldsFieldAnime},
+		&core.AutodateField{Name: model.ShieldsFieldCreated, OnCreate: true},
+		&core.AutodateField{Name: model.ShieldsFieldUpdated, OnCreate: true, OnUpdate: true},
+	},
+	model.DbNameUsers: {
+		&core.TextField{Name: model.UsersFieldName},
+		&core.TextField{Name: model.UsersFieldNickname},
+		&core.TextField{Name: model.UsersFieldAvatar},
+		&core.TextField{Name: model.UsersFieldOId},
+		&core.TextField{Name: model.UsersFieldRole},
+	},
+	model.DbNameUserTokens: {
+		&core.TextField{Name: model.UserTokensFieldUserId},
+		&core.TextField{Name: model.UserTokensFieldToken},
+		&core.TextField{Name: model.UserTokensFieldState},
+		&core.DateField{Name: model.UserTokenFieldExpired},
+		&core.AutodateField{Name: model.UserTokensFieldCreated, OnCreate: true},
+		&core.AutodateField{Name: model.UserTokensFieldUpdated, OnCreate: true, OnUpdate: true},
+	},
+	model.DbNameVotes: {
+		&core.TextField{Name: model.VotesFieldName},
+		&core.TextField{Name: model.VotesFieldDesc},
+		&core.TextField{Name: model.VotesFieldType},
+		&core.NumberField{Name: model.VotesFieldTimes},
+		&core.BoolField{Name: model.VotesFieldRepeat},
+		&core.NumberField{Name: model.VotesFieldUserRegisterDays},
+		&core.DateField{Name: model.VotesFieldStart},
+		&core.DateField{Name: model.VotesFieldEnd},
+		&core.TextField{Name: model.VotesFieldVisibility},
+		&core.BoolField{Name: model.VotesFieldPublished},
+		&core.DateField{Name: model.VotesFieldPublishedAt},
+		&core.TextField{Name: model.VotesFieldResultSnapshot},
+		&core.TextField{Name: model.VotesFieldWeightPolicy},
+		&core.TextField{Name: model.VotesFieldWeightConfig},
+	},
+	model.DbNameVoteChains: {
+		&core.TextField{Name: model.VoteChainsFieldVoteId},
+		&core.NumberField{Name: model.VoteChainsFieldSeq},
+		&core.TextField{Name: model.VoteChainsFieldKind},
+		&core.TextField{Name: model.VoteChainsFieldSource},
+		&core.TextField{Name: model.VoteChainsFieldLogId},
+		&core.TextField{Name: model.VoteChainsFieldReceipt},
+		&core.TextField{Name: model.VoteChainsFieldDigest},
+		&core.TextField{Name: model.VoteChainsFieldPrevHash},
+		&core.TextField{Name: model.VoteChainsFieldHash},
+		&core.AutodateField{Name: model.VoteChainsFieldCreated, OnCreate: true},
+	},
+	model.DbNameVoteJuryApplyLogs: {
+		&core.TextField{Name: model.VoteJuryApplyLogFieldVoteId},
+		&core.TextField{Name: model.VoteJuryApplyLogFieldUserId},
+		&core.TextField{Name: model.VoteJuryApplyLogFieldReason},
+		&core.TextField{Name: model.VoteJuryApplyLogFieldStatus},
+		&core.TextField{Name: model.VoteJuryApplyLogFieldAdminId},
+		&core.AutodateField{Name: model.VoteJuryApplyLogFieldCreated, OnCreate: true},
+		&core.AutodateField{Name: model.VoteJuryApplyLogFieldUpdated, OnCreate: true, OnUpdate: true},
+	},
+	model.DbNameVoteJuryCommits: {
+		&core.TextField{Name: model.VoteJuryCommitFieldVoteId},
+		&core.NumberField{Name: model.VoteJuryCommitFieldRound},
+		&core.TextField{Name: model.VoteJuryCommitFieldFromUserId},
+		&core.TextField{Name: model.VoteJuryCommitFieldCommitment},
+		&core.BoolField{Name: model.VoteJuryCommitFieldRevealed},
+		&core.TextField{Name: model.VoteJuryCommitFieldBallot},
+		&core.TextField{Name: model.VoteJuryCommitFieldNonce},
+		&core.DateField{Name: model.VoteJuryCommitFieldRevealedAt},
+		&core.AutodateField{Name: model.VoteJuryCommitFieldCreated, OnCreate: true},
+		&core.AutodateField{Name: model.VoteJuryCommitFieldUpdated, OnCreate: true, OnUpdate: true},
+	},
+	model.DbNameVoteJuryConflicts: {
+		&core.TextField{Name: model.VoteJuryConflictFieldVoteId},
+		&core.TextField{Name: model.VoteJuryConflictFieldJurorId},
+		&core.TextField{Name: model.VoteJuryConflictFieldCandidateId},
+		&core.TextField{Name: model.VoteJuryConflictFieldReason},
+		&core.TextField{Name: model.VoteJuryConflictFieldAdminId},
+		&core.AutodateField{Name: model.VoteJuryConflictFieldCreated, OnCreate: true},
+	},
+	model.DbNameVoteJuryLogs: {
+		&core.TextField{Name: model.VoteJuryLogFieldVoteId},
+		&core.TextField{Name: model.VoteJuryLogFieldFromUserId},
+		&core.TextField{Name: model.VoteJuryLogFieldToUserId},
+		&core.NumberField{Name: model.VoteJuryLogFieldTimes},
+		&core.NumberField{Name: model.VoteJuryLogFieldRound},
+		&core.TextField{Name: model.VoteJuryLogFieldComment},
+		&core.TextField{Name: model.VoteJuryLogFieldReceipt},
+	},
+	model.DbNameVoteJuryObjections: {
+		&core.TextField{Name: model.VoteJuryObjectionFieldVoteId},
+		&core.TextField{Name: model.VoteJuryObjectionFieldJurorId},
+		&core.TextField{Name: model.VoteJuryObjectionFieldUserId},
+		&core.TextField{Name: model.VoteJuryObjectionFieldReason},
+		&core.TextField{Name: model.VoteJuryObjectionFieldStatus},
+		&core.TextField{Name: model.VoteJuryObjectionFieldNote},
+		&core.TextField{Name: model.VoteJuryObjectionFieldAdminId},
+		&core.BoolField{Name: model.VoteJuryObjectionFieldRemoved},
+		&core.DateField{Name: model.VoteJuryObjectionFieldResolvedAt},
+		&core.AutodateField{Name: model.VoteJuryObjectionFieldCreated, OnCreate: true},
+		&core.AutodateField{Name: model.VoteJuryObjectionFieldUpdated, OnCreate: true, OnUpdate: true},
+	},
+	model.DbNameVoteJuryReminders: {
+		&core.TextField{Name: model.VoteJuryReminderFieldVoteId},
+		&core.NumberField{Name: model.VoteJuryReminderFieldRound},
+		&core.TextField{Name: model.VoteJuryReminderFieldPhase},
+		&core.NumberField{Name: model.VoteJuryReminderFieldOffset},
+		&core.TextField{Name: model.VoteJuryReminderFieldUserId},
+		&core.TextField{Name: model.VoteJuryReminderFieldTrigger},
+		&core.TextField{Name: model.VoteJuryReminderFieldActorId},
+		&core.TextField{Name: model.VoteJuryReminderFieldContent},
+		&core.TextField{Name: model.VoteJuryReminderFieldStatus},
+		&core.TextField{Name: model.VoteJuryReminderFieldError},
+		&core.AutodateField{Name: model.VoteJuryReminderFieldCreated, OnCreate: true},
+	},
+	model.DbNameVoteJuryResults: {
+		&core.TextField{Name: model.VoteJuryResultFieldVoteId},
+		&core.NumberField{Name: model.VoteJuryResultFieldRound},
+		&core.TextField{Name: model.VoteJuryResultFieldResults},
+		&core.BoolField{Name: model.VoteJuryResultFieldContinue},
+		&core.JSONField{Name: model.VoteJuryResultFieldUserIds},
+		&core.TextField{Name: model.VoteJuryResultFieldBreakdown},
+		&core.TextField{Name: model.VoteJuryResultFieldRanking},
+		&core.AutodateField{Name: model.VoteJuryResultFieldCreated, OnCreate: true},
+	},
+	model.DbNameVoteJuryRules: {
+		&core.TextField{Name: model.VoteJuryRuleFieldVoteId},
+		&core.NumberField{Name: model.VoteJuryRuleFieldCount},
+		&core.JSONField{Name: model.VoteJuryRuleFieldAdmins},
+		&core.JSONField{Name: model.VoteJuryRuleFieldDecisions},
+		&core.TextField{Name: model.VoteJuryRuleFieldStatus},
+		&core.NumberField{Name: model.VoteJuryRuleFieldCurrentRound},
+		&core.DateField{Name: model.VoteJuryRuleFieldApplyTime},
+		&core.DateField{Name: model.VoteJuryRuleFieldPublicityTime},
+		&core.BoolField{Name: model.VoteJuryRuleFieldAutoSchedule},
+		&core.BoolField{Name: model.VoteJuryRuleFieldAutoCalculate},
+		&core.TextField{Name: model.VoteJuryRuleFieldSelectMode},
+		&core.TextField{Name: model.VoteJuryRuleFieldSelectSeed},
+		&core.TextField{Name: model.VoteJuryRuleFieldSelectSeedHash},
+		&core.NumberField{Name: model.VoteJuryRuleFieldReturningQuota},
+		&core.TextField{Name: model.VoteJuryRuleFieldSelection},
+		&core.NumberField{Name: model.VoteJuryRuleFieldWinners},
+		&core.TextField{Name: model.VoteJuryRuleFieldScoring},
+		&core.TextField{Name: model.VoteJuryRuleFieldCriteria},
+		&core.TextField{Name: model.VoteJuryRuleFieldAggregation},
+		&core.NumberField{Name: model.VoteJuryRuleFieldTrimRatio},
+		&core.NumberField{Name: model.VoteJuryRuleFieldOutlierThreshold},
+		&core.BoolField{Name: model.VoteJuryRuleFieldSecretBallot},
+		&core.BoolField{Name: model.VoteJuryRuleFieldRevealing},
+		&core.DateField{Name: model.VoteJuryRuleFieldRevealStart},
+		&core.NumberField{Name: model.VoteJuryRuleFieldRevealMinutes},
+		&core.TextField{Name: model.VoteJuryRuleFieldReminderOffsets},
+		&core.TextField{Name: model.VoteJuryRuleFieldReminderTemplate},
+		&core.TextField{Name: model.VoteJuryRuleFieldRevealTemplate},
+		&core.NumberField{Name: model.VoteJuryRuleFieldMinParticipationRate},
+		&core.AutodateField{Name: model.VoteJuryRuleFieldCreated, OnCreate: true},
+		&core.AutodateField{Name: model.VoteJuryRuleFieldUpdated, OnCreate: true, OnUpdate: true},
+	},
+	model.DbNameVoteJuryScores: {
+		&core.TextField{Name: model.VoteJuryScoreFieldVoteId},
+		&core.NumberField{Name: model.VoteJuryScoreFieldRound},
+		&core.TextField{Name: model.VoteJuryScoreFieldFromUserId},
+		&core.TextField{Name: model.VoteJuryScoreFieldToUserId},
+		&core.TextField{Name: model.VoteJuryScoreFieldScores},
+		&core.TextField{Name: model.VoteJuryScoreFieldComment},
+		&core.AutodateField{Name: model.VoteJuryScoreFieldCreated, OnCreate: true},
+		&core.AutodateField{Name: model.VoteJuryScoreFieldUpdated, OnCreate: true, OnUpdate: true},
+	},
+	model.DbNameVoteJuryStatusLogs: {
+		&core.TextField{Name: model.VoteJuryStatusLogFieldVoteId},
+		&core.TextField{Name: model.VoteJuryStatusLogFieldFromStatus},
+		&core.TextField{Name: model.VoteJuryStatusLogFieldToStatus},
+		&core.NumberField{Name: model.VoteJuryStatusLogFieldRound},
+		&core.TextField{Name: model.VoteJuryStatusLogFieldTrigger},
+		&core.TextField{Name: model.VoteJuryStatusLogFieldActorId},
+		&core.TextField{Name: model.VoteJuryStatusLogFieldReason},
+		&core.TextField{Name: model.VoteJuryStatusLogFieldSnapshot},
+		&core.AutodateField{Name: model.VoteJuryStatusLogFieldCreated, OnCreate: true},
+	},
+	model.DbNameVoteJuryUsers: {
+		&core.TextField{Name: model.VoteJuryUserFieldVoteId},
+		&core.TextField{Name: model.VoteJuryUserFieldUserId},
+		&core.TextField{Name: model.VoteJuryUserFieldStatus},
+		&core.AutodateField{Name: model.VoteJuryUserFieldCreated, OnCreate: true},
+		&core.AutodateField{Name: model.VoteJuryUserFieldUpdated, OnCreate: true, OnUpdate: true},
+	},
+	model.DbNameVoteLogs: {
+		&core.TextField{Name: model.VoteLogsFieldVoteId},
+		&core.TextField{Name: model.VoteLogsFieldFromUserId},
+		&core.TextField{Name: model.VoteLogsFieldToUserId},
+		&core.TextField{Name: model.VoteLogsFieldComment},
+		&core.TextField{Name: model.VoteLogsFieldValid},
+		&core.TextField{Name: model.VoteLogsFieldIpHash},
+		&core.TextField{Name: model.VoteLogsFieldUaHash},
+		&core.TextField{Name: model.VoteLogsFieldInvalidReason},
+		&core.TextField{Name: model.VoteLogsFieldInvalidatedBy},
+		&core.DateField{Name: model.VoteLogsFieldInvalidatedAt},
+		&core.NumberField{Name: model.VoteLogsFieldWeight},
+		&core.TextField{Name: model.VoteLogsFieldReceipt},
+		&core.AutodateField{Name: model.VoteLogsFieldCreated, OnCreate: true},
+		&core.AutodateField{Name: model.VoteLogsFieldUpdated, OnCreate: true, OnUpdate: true},
+	},
+	model.DbNameYearlyHistories: {
+		&core.NumberField{Name: model.YearlyHistoriesFieldYear},
+		&core.TextField{Name: model.YearlyHistoriesFieldKeyword},
+		&core.TextField{Name: model.YearlyHistoriesFieldArticleShieldId},
+		&core.TextField{Name: model.YearlyHistoriesFieldAgeShieldId},
+		&core.TextField{Name: model.YearlyHistoriesFieldArticleUrl},
+		&core.TextField{Name: model.YearlyHistoriesFieldPostArticleUrl},
+		&core.TextField{Name: model.YearlyHistoriesFieldCollectArticleUrl},
+		&core.TextField{Name: model.YearlyHistoriesFieldActivityId},
+		&core.DateField{Name: model.YearlyHistoriesFieldStart},
+		&core.DateField{Name: model.YearlyHistoriesFieldEnd},
+	},
+}
+
+// createTestCollections 在测试应用中创建业务集合，users 沿用内置的认证集合并补充业务字段
+func createTestCollections(app core.App) error {
+	for name, fields := range testCollections {
+		var collection *core.Collection
+		if name == model.DbNameUsers {
+			var err error
+			if collection, err = app.FindCollectionByNameOrId(model.DbNameUsers); err != nil {
+				return err
+			}
+			for _, field := range fields {
+				collection.Fields.RemoveByName(field.GetName())
+			}
+			// 用户通过摸鱼派登录创建，没有邮箱
+			if email, ok := collection.Fields.GetByName(core.FieldNameEmail).(*core.EmailField); ok {
+				email.Required = false
+			}
+		} else {
+			collection = core.NewBaseCollection(name)
+		}
+		collection.Fields.Add(fields...)
+		if err := app.Save(collection); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,6 +2,9 @@ package controller
 
 import (
 	"bless-activity/model"
+	"bless-activity/service/vote_chain"
+	"bless-activity/service/vote_fraud"
+	"bless-activity/service/vote_result"
 	"errors"
 	"log/slog"
 	"net/http"
@@ -13,20 +16,27 @@ import (
 )
 
 type ShieldFiveYearController struct {
+	*BaseController
+
 	event  *core.ServeEvent
 	app    core.App
 	logger *slog.Logger
+
+	voteFraud *vote_fraud.Service
 }
 
-func NewShieldFiveYearController(event *core.ServeEvent) *ShieldFiveYearController {
+func NewShieldFiveYearController(event *core.ServeEvent, base *BaseController) *ShieldFiveYearController {
 	logger := event.App.Logger().With(
 		slog.String("controller", "shield_five_year"),
 	)
 
 	controller := &ShieldFiveYearController{
-		event:  event,
-		app:    event.App,
-		logger: logger,
+		BaseController: base,
+		event:          event,
+		app:            event.App,
+		logger:         logger,
+
+		voteFraud: vote_fraud.NewService(event.App, base.voteChain),
 	}
 
 	controller.registerRoutes()
@@ -95,6 +105,50 @@ func (controller *ShieldFiveYearController) CheckActivityTime(activityId string)
 	return nil
 }
 
+// checkResultVisible 判断当前请求能否查看投票结果，管理员始终可见
+func (controller *ShieldFiveYearController) checkResultVisible(e *core.RequestEvent, voteId string) (*model.Vote, bool, error) {
+	vote := new(model.Vote)
+	if err := controller.app.RecordQuery(model.DbNameVotes).Where(dbx.HashExp{model.CommonFieldId: voteId}).One(vote); err != nil {
+		return nil, false, err
+	}
+
+	if HasAdminAuth(e) {
+		return vote, true, nil
+	}
+
+	return vote, vote.ResultVisible(time.Now()), nil
+}
+
+// resultVoteLogs 查询投票记录，已公布的投票对非管理员只返回快照中计入结果的记录，保证与公布的统计一致
+func (controller *ShieldFiveYearController) resultVoteLogs(e *core.RequestEvent, vote *model.Vote, where dbx.HashExp) ([]*core.Record, error) {
+	query := controller.app.RecordQuery(model.DbNameVoteLogs).
+		Where(where).
+		OrderBy(model.VoteLogsFieldCreated + " DESC")
+
+	if !HasAdminAuth(e) {
+		snapshot, err := vote_result.LoadSnapshot(vote)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot != nil {
+			if snapshot.VoteLogIds == nil {
+				return nil, vote_result.ErrSnapshotNoLogs
+			}
+			ids := make([]any, 0, len(snapshot.VoteLogIds))
+			for _, id := range snapshot.VoteLogIds {
+				ids = append(ids, id)
+			}
+			query = query.AndWhere(dbx.In(model.CommonFieldId, ids...))
+		}
+	}
+
+	var records []*core.Record
+	if err := query.All(&records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
 // CreateShield 创建徽章
 func (controller *ShieldFiveYearController) CreateShield(e *core.RequestEvent) error {
 	// 获取表单参数
@@ -279,24 +333,13 @@ func (controller *ShieldFiveYearController) GetShieldsByActivity(e *core.Request
 	}
 
 	// 扩展用户信息
-	result := make([]map[string]any, 0, len(shields))
+	users := controller.Users(e)
 	for _, shield := range shields {
-		userId := shield.GetString("userId")
-
-		// 使用 recordproxy 方式获取用户信息
-		var user *model.User
-		var userData map[string]any
-		if err := controller.app.RecordQuery(model.DbNameUsers).
-			Where(dbx.HashExp{model.CommonFieldId: userId}).
-			One(&user); err == nil && user != nil {
-			userData = map[string]any{
-				"id":       user.Id,
-				"name":     user.Name(),
-				"nickname": user.Nickname(),
-				"avatar":   user.Avatar(),
-			}
-		}
+		users.Add(shield.GetString("userId"))
+	}
 
+	result := make([]map[string]any, 0, len(shields))
+	for _, shield := range shields {
 		result = append(result, map[string]any{
 			"id":        shield.Id,
 			"text":      shield.Text(),
@@ -307,7 +350,7 @@ func (controller *ShieldFiveYearController) GetShieldsByActivity(e *core.Request
 			"title":     shield.GetString("title"),
 			"note":      shield.GetString("note"),
 			"created":   shield.GetDateTime(model.ShieldsFieldCreated).String(),
-			"user":      userData,
+			"user":      users.Brief(shield.GetString("userId")),
 		})
 	}
 
@@ -384,6 +427,29 @@ func (controller *ShieldFiveYearController) CreateArticle(e *core.RequestEvent)
 	})
 }
 
+// articleShields 批量获取文章关联的徽章，按徽章ID索引
+func (controller *ShieldFiveYearController) articleShields(articles []*core.Record) (map[string]*core.Record, error) {
+	shieldIds := make([]string, 0, len(articles))
+	for _, article := range articles {
+		if shieldId := article.GetString(model.ArticlesFieldShieldId); shieldId != "" {
+			shieldIds = append(shieldIds, shieldId)
+		}
+	}
+	if len(shieldIds) == 0 {
+		return nil, nil
+	}
+
+	records, err := controller.app.FindRecordsByIds(model.DbNameShields, shieldIds)
+	if err != nil {
+		return nil, err
+	}
+	shields := make(map[string]*core.Record, len(records))
+	for _, record := range records {
+		shields[record.Id] = record
+	}
+	return shields, nil
+}
+
 // GetArticlesByActivity 获取活动的所有文章
 func (controller *ShieldFiveYearController) GetArticlesByActivity(e *core.RequestEvent) error {
 	activityId := e.Request.PathValue("activityId")
@@ -404,29 +470,25 @@ func (controller *ShieldFiveYearController) GetArticlesByActivity(e *core.Reques
 	}
 
 	// 扩展用户信息和徽章信息
+	users := controller.Users(e)
+	for _, record := range records {
+		users.Add(record.GetString(model.ArticlesFieldUserId))
+	}
+	shields, err := controller.articleShields(records)
+	if err != nil {
+		return e.InternalServerError("获取徽章信息失败", err)
+	}
+
 	result := make([]map[string]any, 0, len(records))
 	for _, record := range records {
 		article := model.NewArticle(record)
-		userId := article.UserId()
-
-		userRecord, _ := controller.app.FindRecordById(model.DbNameUsers, userId)
-		var userData map[string]any
-		if userRecord != nil {
-			user := model.NewUser(userRecord)
-			userData = map[string]any{
-				"id":       user.Id,
-				"name":     user.Name(),
-				"nickname": user.Nickname(),
-				"avatar":   user.Avatar(),
-			}
-		}
+		userData := users.Brief(article.UserId())
 
 		// 如果文章关联了徽章，获取徽章信息
 		var shieldData map[string]any
 		shieldId := article.ShieldId()
 		if shieldId != "" {
-			shieldRecord, err := controller.app.FindRecordById(model.DbNameShields, shieldId)
-			if err == nil && shieldRecord != nil {
+			if shieldRecord := shields[shieldId]; shieldRecord != nil {
 				shield := model.NewShield(shieldRecord)
 				shieldData = map[string]any{
 					"id":        shield.Id,
@@ -562,13 +624,35 @@ func (controller *ShieldFiveYearController) Vote(e *core.RequestEvent) error {
 	voteLog.SetToUserId(data.ToUserId)
 	voteLog.SetComment(data.Comment)
 	voteLog.SetValid(valid)
+	ipHash, err := controller.voteFraud.HashIp(voteId, e.RealIP())
+	if err != nil {
+		return e.InternalServerError("计算投票来源指纹失败", err)
+	}
+	voteLog.SetIpHash(ipHash)
+	voteLog.SetUaHash(vote_fraud.HashUserAgent(e.Request.UserAgent()))
+
+	// 按投票的权重策略记录本票权重，保证结果可复现
+	weight, err := controller.voteResult.Weight(vote, user)
+	if err != nil {
+		return e.InternalServerError("计算投票权重失败", err)
+	}
+	voteLog.SetWeight(weight)
+	voteLog.SetReceipt(vote_chain.NewReceipt())
 
-	if err := controller.app.Save(voteLog); err != nil {
+	// 投票记录与哈希链同事务写入
+	if err := controller.app.RunInTransaction(func(txApp core.App) error {
+		if err := txApp.Save(voteLog); err != nil {
+			return err
+		}
+		return controller.voteChain.AppendVoteLog(txApp, model.VoteChainKindCast, voteLog)
+	}); err != nil {
 		return e.InternalServerError("保存投票失败", err)
 	}
 
 	return e.JSON(http.StatusOK, map[string]any{
 		"message": "投票成功",
+		"weight":  weight,
+		"receipt": voteLog.Receipt(),
 	})
 }
 
@@ -591,52 +675,36 @@ func (controller *ShieldFiveYearController) GetVotesByActivity(e *core.RequestEv
 		})
 	}
 
-	records, err := controller.app.FindRecordsByFilter(
-		model.DbNameVoteLogs,
-		"voteId = {:voteId}",
-		"-created",
-		0,
-		0,
-		map[string]any{
-			"voteId": voteId,
-		},
-	)
+	vote, visible, err := controller.checkResultVisible(e, voteId)
+	if err != nil {
+		return e.BadRequestError("投票不存在", err)
+	}
+	if !visible {
+		return e.JSON(http.StatusOK, map[string]any{
+			"items":      []any{},
+			"hidden":     true,
+			"visibility": vote.Visibility(),
+		})
+	}
 
+	records, err := controller.resultVoteLogs(e, vote, dbx.HashExp{model.VoteLogsFieldVoteId: voteId})
 	if err != nil {
 		return e.InternalServerError("获取投票记录失败", err)
 	}
 
+	users := controller.Users(e)
+	for _, record := range records {
+		users.Add(record.GetString(model.VoteLogsFieldFromUserId), record.GetString(model.VoteLogsFieldToUserId))
+	}
+
 	result := make([]map[string]any, 0, len(records))
 	for _, record := range records {
 		voteLog := model.NewVoteLog(record)
 
-		fromUser, _ := controller.app.FindRecordById(model.DbNameUsers, voteLog.FromUserId())
-		toUser, _ := controller.app.FindRecordById(model.DbNameUsers, voteLog.ToUserId())
-
-		var fromUserData, toUserData map[string]any
-		if fromUser != nil {
-			user := model.NewUser(fromUser)
-			fromUserData = map[string]any{
-				"id":       user.Id,
-				"name":     user.Name(),
-				"nickname": user.Nickname(),
-				"avatar":   user.Avatar(),
-			}
-		}
-		if toUser != nil {
-			user := model.NewUser(toUser)
-			toUserData = map[string]any{
-				"id":       user.Id,
-				"name":     user.Name(),
-				"nickname": user.Nickname(),
-				"avatar":   user.Avatar(),
-			}
-		}
-
 		result = append(result, map[string]any{
 			"id":       voteLog.Id,
-			"fromUser": fromUserData,
-			"toUser":   toUserData,
+			"fromUser": users.Brief(voteLog.FromUserId()),
+			"toUser":   users.Brief(voteLog.ToUserId()),
 			"comment":  voteLog.Comment(),
 			"created":  voteLog.GetDateTime(model.VoteLogsFieldCreated).String(),
 		})
@@ -666,29 +734,36 @@ func (controller *ShieldFiveYearController) GetVoteStats(e *core.RequestEvent) e
 		})
 	}
 
-	// 获取所有有效投票记录
-	records, err := controller.app.FindRecordsByFilter(
-		model.DbNameVoteLogs,
-		"voteId = {:voteId} && valid = {:valid}",
-		"",
-		0,
-		0,
-		map[string]any{
-			"voteId": voteId,
-			"valid":  model.VoteLogValidValid,
-		},
-	)
-
+	vote, visible, err := controller.checkResultVisible(e, voteId)
 	if err != nil {
-		return e.InternalServerError("获取投票统计失败", err)
+		return e.BadRequestError("投票不存在", err)
+	}
+	if !visible {
+		return e.JSON(http.StatusOK, map[string]any{
+			"stats":      map[string]int{},
+			"hidden":     true,
+			"visibility": vote.Visibility(),
+		})
+	}
+
+	// 已公布的投票，非管理员只能看到冻结的快照
+	if !HasAdminAuth(e) {
+		snapshot, err := vote_result.LoadSnapshot(vote)
+		if err != nil {
+			return e.InternalServerError("读取结果快照失败", err)
+		}
+		if snapshot != nil {
+			return e.JSON(http.StatusOK, map[string]any{
+				"stats":       snapshot.Stats,
+				"publishedAt": snapshot.PublishedAt,
+			})
+		}
 	}
 
 	// 统计每个用户获得的有效票数
-	stats := make(map[string]int)
-	for _, record := range records {
-		voteLog := model.NewVoteLog(record)
-		toUserId := voteLog.ToUserId()
-		stats[toUserId]++
+	stats, _, err := controller.voteResult.Tally(voteId)
+	if err != nil {
+		return e.InternalServerError("获取投票统计失败", err)
 	}
 
 	return e.JSON(http.StatusOK, map[string]any{
@@ -907,27 +982,28 @@ func (controller *ShieldFiveYearController) GetMyArticles(e *core.RequestEvent)
 	}
 
 	// 扩展用户和徽章信息
+	users := controller.Users(e)
+	for _, record := range records {
+		users.Add(record.GetString(model.ArticlesFieldUserId))
+	}
+	shields, err := controller.articleShields(records)
+	if err != nil {
+		return e.InternalServerError("获取徽章信息失败", err)
+	}
+
 	result := make([]map[string]any, 0, len(records))
 	for _, record := range records {
 		article := model.NewArticle(record)
 		data := record.PublicExport()
 
 		// 获取用户信息
-		if userRecord, err := controller.app.FindRecordById(model.DbNameUsers, article.UserId()); err == nil {
-			user := model.NewUser(userRecord)
-			data["user"] = map[string]any{
-				"id":       user.Id,
-				"name":     user.Name(),
-				"nickname": user.Nickname(),
-				"avatar":   user.Avatar(),
-			}
+		if userData := users.Brief(article.UserId()); userData != nil {
+			data["user"] = userData
 		}
 
 		// 获取徽章信息
-		if article.ShieldId() != "" {
-			if shieldRecord, err := controller.app.FindRecordById(model.DbNameShields, article.ShieldId()); err == nil {
-				data["shield"] = shieldRecord.PublicExport()
-			}
+		if shieldRecord := shields[article.ShieldId()]; shieldRecord != nil {
+			data["shield"] = shieldRecord.PublicExport()
 		}
 
 		result = append(result, data)
@@ -957,28 +1033,33 @@ func (controller *ShieldFiveYearController) GetMyVotes(e *core.RequestEvent) err
 		return e.InternalServerError("获取投票记录失败", err)
 	}
 
-	// 扩展信息：获取每个投票对应的活动ID
+	// 扩展信息：一次查询出各投票对应的活动ID
+	voteIds := make([]any, 0, len(records))
+	for _, record := range records {
+		voteIds = append(voteIds, record.GetString(model.VoteLogsFieldVoteId))
+	}
+	activityIds := make(map[string]string)
+	if len(voteIds) > 0 {
+		var activities []*model.Activity
+		if err = controller.app.RecordQuery(model.DbNameActivities).
+			Where(dbx.In(model.ActivitiesFieldVoteId, voteIds...)).
+			All(&activities); err != nil {
+			return e.InternalServerError("获取活动失败", err)
+		}
+		for _, activity := range activities {
+			if _, ok := activityIds[activity.GetVoteId()]; !ok {
+				activityIds[activity.GetVoteId()] = activity.Id
+			}
+		}
+	}
+
 	result := make([]map[string]any, 0, len(records))
 	for _, record := range records {
 		voteLog := model.NewVoteLog(record)
 		data := record.PublicExport()
-
-		// 通过voteId查找对应的活动
-		activities, err := controller.app.FindRecordsByFilter(
-			model.DbNameActivities,
-			"voteId = {:voteId}",
-			"",
-			1,
-			0,
-			map[string]any{
-				"voteId": voteLog.VoteId(),
-			},
-		)
-
-		if err == nil && len(activities) > 0 {
-			data["activityId"] = activities[0].Id
+		if activityId, ok := activityIds[voteLog.VoteId()]; ok {
+			data["activityId"] = activityId
 		}
-
 		result = append(result, data)
 	}
 
@@ -1068,38 +1149,49 @@ func (controller *ShieldFiveYearController) GetVoteDetails(e *core.RequestEvent)
 		})
 	}
 
-	// 获取给该用户投票的所有记录
-	records, err := controller.app.FindRecordsByFilter(
-		model.DbNameVoteLogs,
-		"voteId = {:voteId} && toUserId = {:toUserId}",
-		"-created",
-		0,
-		0,
-		map[string]any{
-			"voteId":   voteId,
-			"toUserId": userId,
-		},
-	)
+	vote, visible, err := controller.checkResultVisible(e, voteId)
+	if err != nil {
+		return e.BadRequestError("投票不存在", err)
+	}
+	if !visible {
+		return e.JSON(http.StatusOK, map[string]any{
+			"voters":     []any{},
+			"hidden":     true,
+			"visibility": vote.Visibility(),
+		})
+	}
 
+	// 获取给该用户投票的所有记录
+	records, err := controller.resultVoteLogs(e, vote, dbx.HashExp{
+		model.VoteLogsFieldVoteId:   voteId,
+		model.VoteLogsFieldToUserId: userId,
+	})
 	if err != nil {
 		return e.InternalServerError("获取投票详情失败", err)
 	}
 
 	// 扩展投票人信息
+	isAdmin := HasAdminAuth(e)
+	users := controller.Users(e)
+	for _, record := range records {
+		users.Add(record.GetString(model.VoteLogsFieldFromUserId))
+	}
+
 	result := make([]map[string]any, 0, len(records))
 	for _, record := range records {
 		voteLog := model.NewVoteLog(record)
 		data := record.PublicExport()
 
+		// 回执与来源指纹仅管理员可见
+		if !isAdmin {
+			delete(data, model.VoteLogsFieldReceipt)
+			delete(data, model.VoteLogsFieldIpHash)
+			delete(data, model.VoteLogsFieldUaHash)
+		}
+
 		// 获取投票人信息（始终显示真实用户名）
-		if userRecord, err := controller.app.FindRecordById(model.DbNameUsers, voteLog.FromUserId()); err == nil {
-			user := model.NewUser(userRecord)
-			data["user"] = map[string]any{
-				"id":       user.Id,
-				"name":     user.Name(),
-				"nickname": user.Nickname(),
-				"avatar":   user.Avatar(),
-			}
+		if userData := users.Brief(voteLog.FromUserId()); userData != nil {
+			data["user"] = userData
 		}
 
 		result = append(result, data)
@@ -1148,8 +1240,13 @@ func (controller *ShieldFiveYearController) DeleteVote(e *core.RequestEvent) err
 		}
 	}
 
-	// 删除投票记录
-	if err := controller.app.Delete(record); err != nil {
+	// 删除投票记录，并在哈希链上记录撤销
+	if err := controller.app.RunInTransaction(func(txApp core.App) error {
+		if err := txApp.Delete(record); err != nil {
+			return err
+		}
+		return controller.voteChain.AppendVoteLog(txApp, model.VoteChainKindRevoke, voteLog)
+	}); err != nil {
 		return e.InternalServerError("删除投票失败", err)
 	}
 
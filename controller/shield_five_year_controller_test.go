@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"bless-activity/model"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetArticlesByActivityQueryCount(t *testing.T) {
+	queries := make(map[int]int64)
+	for _, count := range []int{1, 10} {
+		server := newTestServer(t)
+		activity := server.createRecord(t, model.DbNameActivities, map[string]any{
+			model.ActivitiesFieldName: "五周年徽章",
+		})
+		for i := range count {
+			user := server.createUser(t, fmt.Sprintf("author-%d", i), "")
+			shield := server.createRecord(t, model.DbNameShields, map[string]any{
+				model.ShieldsFieldText: fmt.Sprintf("徽章%d", i),
+			})
+			server.createRecord(t, model.DbNameArticles, map[string]any{
+				model.ArticlesFieldActivityId: activity.Id,
+				model.ArticlesFieldUserId:     user.Id,
+				model.ArticlesFieldTitle:      fmt.Sprintf("投稿%d", i),
+				model.ArticlesFieldShieldId:   shield.Id,
+			})
+		}
+
+		queries[count] = server.countQueries(func() {
+			res := server.request(t, http.MethodGet, "/activity-api/shield-five-year/articles/"+activity.Id, "", nil)
+			if res.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", res.Code, res.Body.String())
+			}
+		})
+	}
+
+	// 作者与徽章批量加载，查询次数与投稿数量无关
+	if queries[1] != queries[10] {
+		t.Errorf("query count grows with articles: 1 article %d queries, 10 articles %d queries", queries[1], queries[10])
+	}
+}
+
+func TestGetMyVotesQueryCount(t *testing.T) {
+	queries := make(map[int]int64)
+	for _, count := range []int{1, 10} {
+		server := newTestServer(t)
+		voter := server.createUser(t, "voter", "")
+		for i := range count {
+			vote := server.createRecord(t, model.DbNameVotes, map[string]any{
+				model.VotesFieldName: fmt.Sprintf("投票%d", i),
+			})
+			server.createRecord(t, model.DbNameActivities, map[string]any{
+				model.ActivitiesFieldName:   fmt.Sprintf("活动%d", i),
+				model.ActivitiesFieldVoteId: vote.Id,
+			})
+			server.createRecord(t, model.DbNameVoteLogs, map[string]any{
+				model.VoteLogsFieldVoteId:     vote.Id,
+				model.VoteLogsFieldFromUserId: voter.Id,
+				model.VoteLogsFieldToUserId:   fmt.Sprintf("target-%d", i),
+			})
+		}
+
+		queries[count] = server.countQueries(func() {
+			res := server.request(t, http.MethodGet, "/activity-api/shield-five-year/my-votes", "", voter)
+			if res.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", res.Code, res.Body.String())
+			}
+		})
+	}
+
+	// 投票对应的活动批量加载，查询次数与投票数量无关
+	if queries[1] != queries[10] {
+		t.Errorf("query count grows with votes: 1 vote %d queries, 10 votes %d queries", queries[1], queries[10])
+	}
+}
@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"bless-activity/model"
+	"log/slog"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const userLoaderKey = "user_loader"
+
+// UserLoader 请求内共享的用户加载器
+// 先通过 Add 收集需要展开的用户ID，首次 Get 时用一次 IN 查询批量加载，同一请求内已加载的用户直接复用
+type UserLoader struct {
+	app core.App
+
+	users   map[string]*model.User // 已加载的用户，不存在的用户值为 nil
+	pending map[string]struct{}    // 待加载的用户ID
+}
+
+func NewUserLoader(app core.App) *UserLoader {
+	return &UserLoader{
+		app:     app,
+		users:   make(map[string]*model.User),
+		pending: make(map[string]struct{}),
+	}
+}
+
+// Users 获取当前请求的用户加载器，同一请求内多次调用返回同一个加载器
+func (controller *BaseController) Users(event *core.RequestEvent) *UserLoader {
+	if loader, ok := event.Get(userLoaderKey).(*UserLoader); ok {
+		return loader
+	}
+	loader := NewUserLoader(event.App)
+	event.Set(userLoaderKey, loader)
+	return loader
+}
+
+// Add 登记需要加载的用户ID，不会立即查询
+func (loader *UserLoader) Add(userIds ...string) *UserLoader {
+	for _, userId := range userIds {
+		if userId == "" {
+			continue
+		}
+		if _, ok := loader.users[userId]; ok {
+			continue
+		}
+		loader.pending[userId] = struct{}{}
+	}
+	return loader
+}
+
+// Load 批量加载所有待加载的用户
+func (loader *UserLoader) Load() error {
+	if len(loader.pending) == 0 {
+		return nil
+	}
+
+	userIds := make([]any, 0, len(loader.pending))
+	for userId := range loader.pending {
+		userIds = append(userIds, userId)
+	}
+
+	// 查询失败时同样视为已加载，避免在循环中逐个重试
+	for userId := range loader.pending {
+		loader.users[userId] = nil
+	}
+	clear(loader.pending)
+
+	var users []*model.User
+	if err := loader.app.RecordQuery(model.DbNameUsers).
+		Where(dbx.In(model.CommonFieldId, userIds...)).
+		All(&users); err != nil {
+		return err
+	}
+	for _, user := range users {
+		loader.users[user.Id] = user
+	}
+
+	return nil
+}
+
+// Get 获取用户，尚未加载时连同其他待加载的用户一起加载，用户不存在时返回 nil
+func (loader *UserLoader) Get(userId string) *model.User {
+	if user, ok := loader.users[userId]; ok {
+		return user
+	}
+
+	loader.Add(userId)
+	if err := loader.Load(); err != nil {
+		loader.app.Logger().Warn("批量加载用户失败", slog.Any("err", err))
+		return nil
+	}
+	return loader.users[userId]
+}
+
+// Brief 获取用户的基本展示信息，用户不存在时返回 nil
+func (loader *UserLoader) Brief(userId string) map[string]any {
+	user := loader.Get(userId)
+	if user == nil {
+		return nil
+	}
+	return map[string]any{
+		"id":       user.Id,
+		"name":     user.Name(),
+		"nickname": user.Nickname(),
+		"avatar":   user.Avatar(),
+	}
+}
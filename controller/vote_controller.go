@@ -0,0 +1,292 @@
+package controller
+
+import (
+	"bless-activity/model"
+	"bless-activity/service/vote_chain"
+	"bless-activity/service/vote_fraud"
+	"bless-activity/service/vote_result"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+type VoteController struct {
+	*BaseController
+
+	event  *core.ServeEvent
+	group  *router.RouterGroup[*core.RequestEvent]
+	app    core.App
+	logger *slog.Logger
+
+	voteFraud *vote_fraud.Service
+}
+
+func NewVoteController(event *core.ServeEvent, group *router.RouterGroup[*core.RequestEvent], base *BaseController) *VoteController {
+	logger := event.App.Logger().With(
+		slog.String("controller", "vote"),
+	)
+
+	controller := &VoteController{
+		BaseController: base,
+		event:          event,
+		group:          group,
+		app:            event.App,
+		logger:         logger,
+
+		voteFraud: vote_fraud.NewService(event.App, base.voteChain),
+	}
+
+	controller.registerRoutes()
+
+	return controller
+}
+
+func (controller *VoteController) registerRoutes() {
+	// 投票哈希链公开接口
+	chainGroup := controller.group.Group("/vote/chain")
+	// 投票哈希链链头
+	chainGroup.GET("/head/{voteId}", controller.ChainHead)
+	// 校验投票回执
+	chainGroup.GET("/verify/{receipt}", controller.VerifyReceipt)
+
+	group := controller.group.Group("/admin/vote").Bind(
+		controller.RequireVotePermission(model.PermissionVoteManage),
+	)
+
+	// 公布投票结果并冻结快照
+	group.POST("/publish/{voteId}", controller.Publish)
+	// 可疑投票列表
+	group.GET("/fraud/{voteId}", controller.ListFlagged)
+	// 批量作废可疑投票
+	group.POST("/fraud/invalidate", controller.Invalidate)
+}
+
+func (controller *VoteController) makeActionLogger(action string) *slog.Logger {
+	return controller.logger.With(
+		slog.String("action", action),
+	)
+}
+
+// Publish 公布投票结果
+func (controller *VoteController) Publish(event *core.RequestEvent) error {
+	logger := controller.makeActionLogger("publish")
+
+	voteId := event.Request.PathValue("voteId")
+	if voteId == "" {
+		return event.BadRequestError("投票ID不能为空", nil)
+	}
+
+	vote := new(model.Vote)
+	if err := controller.app.RecordQuery(model.DbNameVotes).Where(dbx.HashExp{model.CommonFieldId: voteId}).One(vote); err != nil {
+		return event.NotFoundError("投票不存在", err)
+	}
+
+	snapshot, err := controller.voteResult.Publish(vote)
+	if err != nil {
+		if errors.Is(err, vote_result.ErrAlreadyPublished) || errors.Is(err, vote_result.ErrVoteNotEnded) {
+			return event.BadRequestError(err.Error(), nil)
+		}
+		logger.Error("公布投票结果失败", slog.String("voteId", voteId), slog.Any("err", err))
+		return event.InternalServerError("公布投票结果失败", err)
+	}
+
+	logger.Info("公布投票结果", slog.String("voteId", voteId), slog.String("adminId", event.Auth.Id))
+
+	return event.JSON(http.StatusOK, map[string]any{
+		"message":  "投票结果已公布",
+		"snapshot": snapshot,
+	})
+}
+
+// ListFlagged 列出被检测为可疑的投票
+func (controller *VoteController) ListFlagged(event *core.RequestEvent) error {
+	logger := controller.makeActionLogger("list_flagged")
+
+	voteId := event.Request.PathValue("voteId")
+	if voteId == "" {
+		return event.BadRequestError("投票ID不能为空", nil)
+	}
+
+	// 检测阈值可通过查询参数覆盖
+	options := vote_fraud.DefaultOptions()
+	query := event.Request.URL.Query()
+	if value, err := strconv.Atoi(query.Get("burstMinutes")); err == nil && value > 0 {
+		options.BurstWindow = time.Duration(value) * time.Minute
+	}
+	if value, err := strconv.Atoi(query.Get("burstCount")); err == nil && value > 1 {
+		options.BurstCount = value
+	}
+	if value, err := strconv.Atoi(query.Get("sharedIpUsers")); err == nil && value > 1 {
+		options.SharedIpUsers = value
+	}
+	if value, err := strconv.Atoi(query.Get("sharedPrintUsers")); err == nil && value > 1 {
+		options.SharedPrintUsers = value
+	}
+	if value, err := strconv.Atoi(query.Get("freshUserDays")); err == nil && value >= 0 {
+		options.FreshUserDays = value
+	}
+
+	flaggedList, err := controller.voteFraud.Detect(voteId, options)
+	if err != nil {
+		logger.Error("检测可疑投票失败", slog.String("voteId", voteId), slog.Any("err", err))
+		return event.InternalServerError("检测可疑投票失败", err)
+	}
+
+	// 批量查询用户
+	userIdSet := make(map[string]bool)
+	for _, flagged := range flaggedList {
+		userIdSet[flagged.VoteLog.FromUserId()] = true
+		userIdSet[flagged.VoteLog.ToUserId()] = true
+	}
+	usersMap := make(map[string]*model.User)
+	if len(userIdSet) > 0 {
+		userIds := make([]any, 0, len(userIdSet))
+		for userId := range userIdSet {
+			userIds = append(userIds, userId)
+		}
+		var users []*model.User
+		if err := controller.app.RecordQuery(model.DbNameUsers).Where(dbx.In(model.CommonFieldId, userIds...)).All(&users); err != nil {
+			logger.Warn("批量查询用户失败", slog.Any("err", err))
+		} else {
+			for _, user := range users {
+				usersMap[user.Id] = user
+			}
+		}
+	}
+
+	userInfo := func(userId string) map[string]any {
+		user, ok := usersMap[userId]
+		if !ok {
+			return map[string]any{"id": userId}
+		}
+		return map[string]any{
+			"id":           user.Id,
+			"name":         user.Name(),
+			"nickname":     user.Nickname(),
+			"avatar":       user.Avatar(),
+			"registeredAt": user.RegisteredAt(),
+		}
+	}
+
+	summary := make(map[string]int)
+	items := make([]map[string]any, 0, len(flaggedList))
+	for _, flagged := range flaggedList {
+		for _, flag := range flagged.Flags {
+			summary[flag]++
+		}
+		items = append(items, map[string]any{
+			"id":       flagged.VoteLog.Id,
+			"fromUser": userInfo(flagged.VoteLog.FromUserId()),
+			"toUser":   userInfo(flagged.VoteLog.ToUserId()),
+			"comment":  flagged.VoteLog.Comment(),
+			"ipHash":   flagged.VoteLog.IpHash(),
+			"uaHash":   flagged.VoteLog.UaHash(),
+			"created":  flagged.VoteLog.Created(),
+			"flags":    flagged.Flags,
+		})
+	}
+
+	return event.JSON(http.StatusOK, map[string]any{
+		"items":   items,
+		"total":   len(items),
+		"summary": summary,
+	})
+}
+
+// Invalidate 批量作废投票并记录原因
+func (controller *VoteController) Invalidate(event *core.RequestEvent) error {
+	logger := controller.makeActionLogger("invalidate")
+
+	var req struct {
+		VoteId     string   `json:"voteId"`
+		VoteLogIds []string `json:"voteLogIds"`
+		Reason     string   `json:"reason"`
+	}
+	if err := event.BindBody(&req); err != nil {
+		return event.BadRequestError("参数错误", err)
+	}
+
+	if req.VoteId == "" {
+		return event.BadRequestError("投票ID不能为空", nil)
+	}
+	if len(req.VoteLogIds) == 0 {
+		return event.BadRequestError("请选择要作废的投票", nil)
+	}
+	if req.Reason == "" {
+		return event.BadRequestError("作废原因不能为空", nil)
+	}
+
+	count, err := controller.voteFraud.Invalidate(req.VoteId, req.VoteLogIds, req.Reason, event.Auth.Id)
+	if err != nil {
+		logger.Error("作废投票失败", slog.String("voteId", req.VoteId), slog.Any("err", err))
+		return event.InternalServerError("作废投票失败", err)
+	}
+
+	logger.Info("作废投票", slog.String("voteId", req.VoteId), slog.Int("count", count), slog.String("adminId", event.Auth.Id))
+
+	return event.JSON(http.StatusOK, map[string]any{
+		"message": "作废成功",
+		"count":   count,
+	})
+}
+
+// ChainHead 获取投票哈希链链头，供外部审计结果
+func (controller *VoteController) ChainHead(event *core.RequestEvent) error {
+	voteId := event.Request.PathValue("voteId")
+	if voteId == "" {
+		return event.BadRequestError("投票ID不能为空", nil)
+	}
+
+	vote := new(model.Vote)
+	if err := controller.app.RecordQuery(model.DbNameVotes).Where(dbx.HashExp{model.CommonFieldId: voteId}).One(vote); err != nil {
+		return event.NotFoundError("投票不存在", err)
+	}
+
+	head, err := controller.voteChain.Head(voteId)
+	if err != nil {
+		return event.InternalServerError("获取哈希链失败", err)
+	}
+	if head == nil {
+		return event.JSON(http.StatusOK, map[string]any{
+			"voteId": voteId,
+			"seq":    0,
+			"hash":   "",
+		})
+	}
+
+	return event.JSON(http.StatusOK, map[string]any{
+		"voteId":  voteId,
+		"seq":     head.Seq(),
+		"hash":    head.Hash(),
+		"created": head.Created(),
+	})
+}
+
+// VerifyReceipt 校验投票回执是否包含在哈希链中
+func (controller *VoteController) VerifyReceipt(event *core.RequestEvent) error {
+	receipt := event.Request.PathValue("receipt")
+	if receipt == "" {
+		return event.BadRequestError("回执不能为空", nil)
+	}
+
+	verification, err := controller.voteChain.Verify(receipt)
+	if err != nil {
+		if errors.Is(err, vote_chain.ErrReceiptNotFound) {
+			return event.JSON(http.StatusOK, map[string]any{
+				"included": false,
+			})
+		}
+		return event.InternalServerError("校验回执失败", err)
+	}
+
+	return event.JSON(http.StatusOK, map[string]any{
+		"included":     true,
+		"verification": verification,
+	})
+}
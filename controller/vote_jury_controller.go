@@ -2,17 +2,24 @@ package controller
 
 import (
 	"bless-activity/model"
+	"bless-activity/service/jury_reminder"
+	"bless-activity/service/vote_chain"
+	"bless-activity/service/vote_jury"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"slices"
+	"strings"
 
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 type VoteJuryController struct {
@@ -48,12 +55,25 @@ func (controller *VoteJuryController) registerRoutes() {
 	juryGroup.POST("/apply/audit", controller.AuditApply).BindFunc(controller.RequireAuth, controller.RequireAdmin)
 	juryGroup.POST("/status/switch", controller.SwitchStatus).BindFunc(controller.RequireAuth, controller.RequireAdmin)
 	juryGroup.POST("/calculate", controller.Calculate).BindFunc(controller.RequireAuth, controller.RequireAdmin)
+	juryGroup.POST("/round/rollback", controller.RollbackRound).BindFunc(controller.RequireAuth, controller.RequireAdmin)
+	juryGroup.POST("/select", controller.Select).BindFunc(controller.RequireAuth, controller.RequireAdmin)
+	juryGroup.POST("/reveal/open", controller.OpenReveal).BindFunc(controller.RequireAuth, controller.RequireAdmin)
+	juryGroup.POST("/remind", controller.Remind).BindFunc(controller.RequireAuth, controller.RequireAdmin)
+	juryGroup.POST("/conflict/declare", controller.DeclareConflict).BindFunc(controller.RequireAuth, controller.RequireAdmin)
+	juryGroup.POST("/conflict/remove", controller.RemoveConflict).BindFunc(controller.RequireAuth, controller.RequireAdmin)
+	juryGroup.POST("/objection/resolve", controller.ResolveObjection).BindFunc(controller.RequireAuth, controller.RequireAdmin)
 	juryGroup.GET("/vote-details/{voteId}", controller.GetVoteDetails).BindFunc(controller.RequireAuth, controller.RequireAdminByPath)
+	juryGroup.GET("/juror-history/{voteId}/{userId}", controller.GetJurorHistory).BindFunc(controller.RequireAuth, controller.RequireAdminByPath)
 
 	// 用户接口
 	juryGroup.POST("/apply", controller.Apply).BindFunc(controller.RequireAuth)
+	juryGroup.POST("/objection", controller.FileObjection).BindFunc(controller.RequireAuth)
 	juryGroup.POST("/vote", controller.Vote).BindFunc(controller.RequireAuth)
 	juryGroup.POST("/vote/cancel", controller.CancelVote).BindFunc(controller.RequireAuth)
+	juryGroup.POST("/score", controller.Score).BindFunc(controller.RequireAuth)
+	juryGroup.POST("/vote/commit", controller.CommitVote).BindFunc(controller.RequireAuth)
+	juryGroup.POST("/vote/reveal", controller.RevealVote).BindFunc(controller.RequireAuth)
+	juryGroup.GET("/my-score/{voteId}", controller.GetMyScores).BindFunc(controller.RequireAuth)
 	juryGroup.GET("/result/{voteId}", controller.GetResult)
 	juryGroup.GET("/my-apply/{voteId}", controller.GetMyApply).BindFunc(controller.RequireAuth)
 	juryGroup.GET("/candidates/{voteId}", controller.GetCandidates).BindFunc(controller.RequireAuth)
@@ -67,7 +87,7 @@ func (controller *VoteJuryController) RequireAuth(event *core.RequestEvent) erro
 	return event.Next()
 }
 
-// RequireAdmin 要求管理员权限
+// RequireAdmin 要求评审团管理权限
 func (controller *VoteJuryController) RequireAdmin(event *core.RequestEvent) error {
 	voteId := event.Request.PathValue("voteId")
 	if voteId == "" {
@@ -95,10 +115,8 @@ func (controller *VoteJuryController) RequireAdmin(event *core.RequestEvent) err
 		return event.InternalServerError("获取评审团规则失败", err)
 	}
 
-	// 检查是否是管理员
-	admins := rule.Admins()
-	userId := event.Auth.Id
-	if !slices.Contains(admins, userId) {
+	// 检查评审团管理权限，规则中的管理员或被授予 jury.manage 的用户
+	if !controller.permission.HasForVote(event.Auth, model.PermissionJuryManage, voteId) {
 		return event.ForbiddenError("无管理员权限", nil)
 	}
 
@@ -126,10 +144,8 @@ func (controller *VoteJuryController) RequireAdminByPath(event *core.RequestEven
 		return event.InternalServerError("获取评审团规则失败", err)
 	}
 
-	// 检查是否是管理员
-	admins := rule.Admins()
-	userId := event.Auth.Id
-	if !slices.Contains(admins, userId) {
+	// 检查评审团管理权限，规则中的管理员或被授予 jury.manage 的用户
+	if !controller.permission.HasForVote(event.Auth, model.PermissionJuryManage, voteId) {
 		return event.ForbiddenError("无管理员权限", nil)
 	}
 
@@ -166,11 +182,7 @@ func (controller *VoteJuryController) GetJuryInfo(event *core.RequestEvent) erro
 	}
 
 	// 检查当前用户是否是管理员
-	isAdmin := false
-	if event.Auth != nil {
-		admins := rule.Admins()
-		isAdmin = slices.Contains(admins, event.Auth.Id)
-	}
+	isAdmin := controller.permission.HasForVote(event.Auth, model.PermissionJuryManage, voteId)
 
 	// 获取已通过的评审团成员
 	var juryUsers []*model.VoteJuryUser
@@ -184,18 +196,94 @@ func (controller *VoteJuryController) GetJuryInfo(event *core.RequestEvent) erro
 	}
 
 	// 扩展用户信息
+	users := controller.Users(event)
+	for _, juryUser := range juryUsers {
+		users.Add(juryUser.UserId())
+	}
 	members := make([]map[string]any, 0, len(juryUsers))
 	for _, juryUser := range juryUsers {
-		user := new(model.User)
-		if err := controller.app.RecordQuery(model.DbNameUsers).
-			Where(dbx.HashExp{model.CommonFieldId: juryUser.UserId()}).
-			One(user); err == nil {
-			members = append(members, map[string]any{
-				"id":       user.Id,
-				"name":     user.Name(),
-				"nickname": user.Nickname(),
-				"avatar":   user.Avatar(),
-			})
+		if userData := users.Brief(juryUser.UserId()); userData != nil {
+			members = append(members, userData)
+		}
+	}
+
+	// 利益冲突声明与违规记录（仅管理员可见）
+	var conflicts []map[string]any
+	var violations []*vote_jury.Violation
+	if isAdmin {
+		if records, err := controller.voteJury.Conflicts(voteId); err == nil {
+			conflicts = make([]map[string]any, 0, len(records))
+			for _, conflict := range records {
+				conflicts = append(conflicts, map[string]any{
+					"id":          conflict.Id,
+					"jurorId":     conflict.JurorId(),
+					"candidateId": conflict.CandidateId(),
+					"reason":      conflict.Reason(),
+					"adminId":     conflict.AdminId(),
+					"created":     conflict.Created().String(),
+				})
+			}
+		} else {
+			controller.logger.Warn("获取利益冲突声明失败", slog.Any("err", err))
+		}
+		var err error
+		if violations, err = controller.voteJury.Violations(voteId); err != nil {
+			controller.logger.Warn("检查利益冲突失败", slog.Any("err", err))
+		}
+	}
+
+	// 公示异议及处理结果，提出人仅管理员可见
+	var objections []map[string]any
+	if records, err := controller.voteJury.Objections(voteId); err == nil {
+		objections = make([]map[string]any, 0, len(records))
+		for _, objection := range records {
+			item := map[string]any{
+				"id":         objection.Id,
+				"jurorId":    objection.JurorId(),
+				"reason":     objection.Reason(),
+				"status":     objection.Status(),
+				"note":       objection.Note(),
+				"removed":    objection.Removed(),
+				"resolvedAt": objection.ResolvedAt().String(),
+				"created":    objection.Created().String(),
+			}
+			if isAdmin {
+				item["userId"] = objection.UserId()
+				item["adminId"] = objection.AdminId()
+			}
+			objections = append(objections, item)
+		}
+	} else {
+		controller.logger.Warn("获取异议列表失败", slog.Any("err", err))
+	}
+
+	// 催投提醒记录（仅管理员可见）
+	var reminders []map[string]any
+	if isAdmin {
+		var records []*model.VoteJuryReminder
+		if err := controller.app.RecordQuery(model.DbNameVoteJuryReminders).
+			Where(dbx.HashExp{model.VoteJuryReminderFieldVoteId: voteId}).
+			OrderBy(model.VoteJuryReminderFieldCreated + " DESC").
+			Limit(200).
+			All(&records); err == nil {
+			reminders = make([]map[string]any, 0, len(records))
+			for _, reminder := range records {
+				reminders = append(reminders, map[string]any{
+					"id":      reminder.Id,
+					"round":   reminder.Round(),
+					"phase":   reminder.Phase(),
+					"offset":  reminder.Offset(),
+					"userId":  reminder.UserId(),
+					"trigger": reminder.Trigger(),
+					"actorId": reminder.ActorId(),
+					"content": reminder.Content(),
+					"status":  reminder.Status(),
+					"error":   reminder.Error(),
+					"created": reminder.Created().String(),
+				})
+			}
+		} else {
+			controller.logger.Warn("获取催投提醒记录失败", slog.Any("err", err))
 		}
 	}
 
@@ -207,27 +295,27 @@ func (controller *VoteJuryController) GetJuryInfo(event *core.RequestEvent) erro
 			Where(dbx.HashExp{model.VoteJuryApplyLogFieldVoteId: voteId}).
 			OrderBy(fmt.Sprintf("%s DESC", model.VoteJuryApplyLogFieldCreated)).
 			All(&logs); err == nil {
+			// 申请人往届担任评审的记录，便于审核
+			applicantIds := make([]string, 0, len(logs))
 			for _, log := range logs {
-				user := new(model.User)
-				var userData map[string]any
-				if err := controller.app.RecordQuery(model.DbNameUsers).
-					Where(dbx.HashExp{model.CommonFieldId: log.UserId()}).
-					One(user); err == nil {
-					userData = map[string]any{
-						"id":       user.Id,
-						"name":     user.Name(),
-						"nickname": user.Nickname(),
-						"avatar":   user.Avatar(),
-					}
-				}
+				applicantIds = append(applicantIds, log.UserId())
+			}
+			users.Add(applicantIds...)
+			histories, err := controller.voteJury.JurorHistories(applicantIds, voteId)
+			if err != nil {
+				controller.logger.Warn("获取评审历史失败", slog.Any("err", err))
+				histories = map[string]*vote_jury.JurorHistory{}
+			}
 
+			for _, log := range logs {
 				applyLogs = append(applyLogs, map[string]any{
 					"id":      log.Id,
 					"userId":  log.UserId(),
 					"reason":  log.Reason(),
 					"status":  log.Status(),
 					"adminId": log.AdminId(),
-					"user":    userData,
+					"user":    users.Brief(log.UserId()),
+					"history": histories[log.UserId()],
 					"created": log.GetDateTime("created").String(),
 				})
 			}
@@ -243,29 +331,51 @@ func (controller *VoteJuryController) GetJuryInfo(event *core.RequestEvent) erro
 		controller.logger.Error("获取投票结果失败", slog.Any("err", err))
 	}
 
-	// 格式化结果
-	roundResults := make([]map[string]any, 0, len(results))
+	// 一次查询所有轮次的投票记录，并收集需要展开的用户
+	var allVoteLogs []*model.VoteJuryLog
+	if len(results) > 0 {
+		if err := controller.app.RecordQuery(model.DbNameVoteJuryLogs).
+			Where(dbx.HashExp{model.VoteJuryLogFieldVoteId: voteId}).
+			All(&allVoteLogs); err != nil {
+			controller.logger.Warn("获取投票记录失败", slog.Any("err", err))
+		}
+	}
+	voteLogsByRound := make(map[int][]*model.VoteJuryLog)
+	for _, log := range allVoteLogs {
+		voteLogsByRound[log.Round()] = append(voteLogsByRound[log.Round()], log)
+		users.Add(log.FromUserId(), log.ToUserId())
+	}
+	parsedResults := make(map[string]map[string]float64, len(results))
 	for _, result := range results {
-		// 解析results JSON
-		var voteResults map[string]int
+		var voteResults map[string]float64
 		if err := json.Unmarshal([]byte(result.Results()), &voteResults); err != nil {
 			controller.logger.Error("解析投票结果失败", slog.Any("err", err))
 			continue
 		}
+		parsedResults[result.Id] = voteResults
+		for userId := range voteResults {
+			users.Add(userId)
+		}
+	}
+
+	// 格式化结果
+	roundResults := make([]map[string]any, 0, len(results))
+	for _, result := range results {
+		voteResults, ok := parsedResults[result.Id]
+		if !ok {
+			continue
+		}
 
 		// 统计该轮投票的人数
-		var roundVoteLogs []*model.VoteJuryLog
-		controller.app.RecordQuery(model.DbNameVoteJuryLogs).
-			Where(dbx.HashExp{
-				model.VoteJuryLogFieldVoteId: voteId,
-				model.VoteJuryLogFieldRound:  result.Round(),
-			}).
-			All(&roundVoteLogs)
+		roundVoteLogs := voteLogsByRound[result.Round()]
 
 		votedUserIds := make(map[string]bool)
 		for _, log := range roundVoteLogs {
 			votedUserIds[log.FromUserId()] = true
 		}
+		for _, jurorId := range controller.scoredJurors(voteId, result.Round()) {
+			votedUserIds[jurorId] = true
+		}
 		votedCount := len(votedUserIds)
 		abstainCount := len(juryUsers) - votedCount // 弃票人数
 
@@ -273,16 +383,8 @@ func (controller *VoteJuryController) GetJuryInfo(event *core.RequestEvent) erro
 		abstainUsers := make([]map[string]any, 0)
 		for _, juryUser := range juryUsers {
 			if !votedUserIds[juryUser.UserId()] {
-				user := new(model.User)
-				if err := controller.app.RecordQuery(model.DbNameUsers).
-					Where(dbx.HashExp{model.CommonFieldId: juryUser.UserId()}).
-					One(user); err == nil {
-					abstainUsers = append(abstainUsers, map[string]any{
-						"id":       user.Id,
-						"name":     user.Name(),
-						"nickname": user.Nickname(),
-						"avatar":   user.Avatar(),
-					})
+				if userData := users.Brief(juryUser.UserId()); userData != nil {
+					abstainUsers = append(abstainUsers, userData)
 				}
 			}
 		}
@@ -291,21 +393,9 @@ func (controller *VoteJuryController) GetJuryInfo(event *core.RequestEvent) erro
 		// 结构: toUserId -> [{fromUserId, fromUser, times}]
 		voteDetailsMap := make(map[string][]map[string]any)
 		for _, log := range roundVoteLogs {
-			fromUser := new(model.User)
-			var fromUserData map[string]any
-			if err := controller.app.RecordQuery(model.DbNameUsers).
-				Where(dbx.HashExp{model.CommonFieldId: log.FromUserId()}).
-				One(fromUser); err == nil {
-				fromUserData = map[string]any{
-					"id":       fromUser.Id,
-					"name":     fromUser.Name(),
-					"nickname": fromUser.Nickname(),
-					"avatar":   fromUser.Avatar(),
-				}
-			}
 			voteDetailsMap[log.ToUserId()] = append(voteDetailsMap[log.ToUserId()], map[string]any{
 				"fromUserId": log.FromUserId(),
-				"fromUser":   fromUserData,
+				"fromUser":   users.Brief(log.FromUserId()),
 				"times":      log.Times(),
 			})
 		}
@@ -313,22 +403,10 @@ func (controller *VoteJuryController) GetJuryInfo(event *core.RequestEvent) erro
 		// 扩展用户信息
 		resultWithUsers := make([]map[string]any, 0)
 		for userId, count := range voteResults {
-			user := new(model.User)
-			var userData map[string]any
-			if err := controller.app.RecordQuery(model.DbNameUsers).
-				Where(dbx.HashExp{model.CommonFieldId: userId}).
-				One(user); err == nil {
-				userData = map[string]any{
-					"id":       user.Id,
-					"name":     user.Name(),
-					"nickname": user.Nickname(),
-					"avatar":   user.Avatar(),
-				}
-			}
 			resultWithUsers = append(resultWithUsers, map[string]any{
 				"userId":      userId,
 				"count":       count,
-				"user":        userData,
+				"user":        users.Brief(userId),
 				"voteDetails": voteDetailsMap[userId], // 添加投票详情
 			})
 		}
@@ -366,30 +444,54 @@ func (controller *VoteJuryController) GetJuryInfo(event *core.RequestEvent) erro
 			for _, log := range votedLogs {
 				votedUsers[log.FromUserId()] = true
 			}
+			for _, jurorId := range controller.scoredJurors(voteId, currentRound) {
+				votedUsers[jurorId] = true
+			}
 			votingProgress = map[string]any{
 				"voted":   len(votedUsers),
 				"total":   len(juryUsers),
 				"unvoted": len(juryUsers) - len(votedUsers),
 			}
+
+			// 秘密投票的承诺与揭示进度
+			if rule.SecretBallot() {
+				var commits []*model.VoteJuryCommit
+				if err := controller.app.RecordQuery(model.DbNameVoteJuryCommits).
+					Where(dbx.HashExp{
+						model.VoteJuryCommitFieldVoteId: voteId,
+						model.VoteJuryCommitFieldRound:  currentRound,
+					}).
+					All(&commits); err == nil {
+					revealed := 0
+					for _, commit := range commits {
+						if commit.Revealed() {
+							revealed++
+						}
+					}
+					votingProgress["committed"] = len(commits)
+					votingProgress["revealed"] = revealed
+				}
+			}
 		}
 	}
 
 	// 检查是否已有最终获胜者（最后一轮结果的continue为false）
 	var finalWinner map[string]any
+	var finalRanking []map[string]any
 	isVoteCompleted := false
 	if len(results) > 0 {
 		lastResult := results[len(results)-1]
-		if !lastResult.Continue() && len(lastResult.UserIds()) == 1 {
+		if !lastResult.Continue() && len(lastResult.UserIds()) > 0 {
 			isVoteCompleted = true
 			winnerId := lastResult.UserIds()[0]
-			user := new(model.User)
-			if err := controller.app.RecordQuery(model.DbNameUsers).
-				Where(dbx.HashExp{model.CommonFieldId: winnerId}).
-				One(user); err == nil {
-				// 从结果中获取票数
-				var voteResults map[string]int
-				json.Unmarshal([]byte(lastResult.Results()), &voteResults)
 
+			// 多名次评审时返回完整名次
+			ranking, err := vote_jury.ParseRanking(lastResult)
+			if err != nil {
+				return event.InternalServerError("解析评审名次失败", err)
+			}
+			finalRanking = controller.rankingUsers(users, ranking)
+			if user := users.Get(winnerId); user != nil {
 				// 获取获胜者的文章
 				var winnerArticles []map[string]any
 				activity := new(model.Activity)
@@ -423,13 +525,45 @@ func (controller *VoteJuryController) GetJuryInfo(event *core.RequestEvent) erro
 					"name":     user.Name(),
 					"nickname": user.Nickname(),
 					"avatar":   user.Avatar(),
-					"votes":    voteResults[winnerId],
+					"votes":    ranking.Settled[0].Votes,
 					"articles": winnerArticles,
 				}
 			}
 		}
 	}
 
+	// 状态变更记录
+	var statusLogs []*model.VoteJuryStatusLog
+	if err := controller.app.RecordQuery(model.DbNameVoteJuryStatusLogs).
+		Where(dbx.HashExp{model.VoteJuryStatusLogFieldVoteId: voteId}).
+		OrderBy(model.VoteJuryStatusLogFieldCreated + " DESC").
+		All(&statusLogs); err != nil {
+		controller.logger.Warn("获取状态变更记录失败", slog.Any("err", err))
+	}
+	// 遴选完成后公示遴选记录，包括种子与候选人哈希
+	var selection *model.VoteJurySelection
+	if rule.Selection() != "" {
+		selection = new(model.VoteJurySelection)
+		if err := json.Unmarshal([]byte(rule.Selection()), selection); err != nil {
+			controller.logger.Warn("解析遴选记录失败", slog.Any("err", err))
+			selection = nil
+		}
+	}
+
+	statusHistory := make([]map[string]any, 0, len(statusLogs))
+	for _, statusLog := range statusLogs {
+		statusHistory = append(statusHistory, map[string]any{
+			"fromStatus": statusLog.FromStatus(),
+			"toStatus":   statusLog.ToStatus(),
+			"round":      statusLog.Round(),
+			"trigger":    statusLog.Trigger(),
+			"actorId":    statusLog.ActorId(),
+			"reason":     statusLog.Reason(),
+			"snapshot":   statusLog.Snapshot(),
+			"created":    statusLog.Created().String(),
+		})
+	}
+
 	return event.JSON(http.StatusOK, map[string]any{
 		"vote": map[string]any{
 			"id":    vote.Id,
@@ -441,15 +575,37 @@ func (controller *VoteJuryController) GetJuryInfo(event *core.RequestEvent) erro
 			"end":   vote.End().String(),
 		},
 		"rule": map[string]any{
-			"id":            rule.Id,
-			"count":         rule.Count(),
-			"admins":        rule.Admins(),
-			"decisions":     rule.Decisions(),
-			"status":        rule.Status(),
-			"applyTime":     rule.ApplyTime().String(),
-			"publicityTime": rule.PublicityTime().String(),
-			"currentRound":  rule.CurrentRound(),
+			"id":                   rule.Id,
+			"count":                rule.Count(),
+			"admins":               rule.Admins(),
+			"decisions":            rule.Decisions(),
+			"status":               rule.Status(),
+			"applyTime":            rule.ApplyTime().String(),
+			"publicityTime":        rule.PublicityTime().String(),
+			"currentRound":         rule.CurrentRound(),
+			"autoSchedule":         rule.AutoSchedule(),
+			"autoCalculate":        rule.AutoCalculate(),
+			"winners":              rule.Winners(),
+			"scoring":              rule.Scoring(),
+			"criteria":             rule.Criteria(),
+			"aggregation":          rule.Aggregation(),
+			"secretBallot":         rule.SecretBallot(),
+			"revealing":            rule.Revealing(),
+			"revealStart":          rule.RevealStart().String(),
+			"revealMinutes":        rule.RevealMinutes(),
+			"selectMode":           rule.SelectMode(),
+			"selectSeedHash":       rule.SelectSeedHash(),
+			"selection":            selection,
+			"reminderOffsets":      rule.ReminderOffsets(),
+			"reminderTemplate":     rule.ReminderTemplate(),
+			"revealTemplate":       rule.RevealTemplate(),
+			"minParticipationRate": rule.MinParticipationRate(),
 		},
+		"statusLogs":      statusHistory,
+		"conflicts":       conflicts,
+		"violations":      violations,
+		"objections":      objections,
+		"reminders":       reminders,
 		"members":         members,
 		"applyLogs":       applyLogs,
 		"results":         roundResults,
@@ -457,6 +613,7 @@ func (controller *VoteJuryController) GetJuryInfo(event *core.RequestEvent) erro
 		"isAdmin":         isAdmin,
 		"isVoteCompleted": isVoteCompleted,
 		"finalWinner":     finalWinner,
+		"finalRanking":    finalRanking,
 	})
 }
 
@@ -570,6 +727,14 @@ func (controller *VoteJuryController) AddMember(event *core.RequestEvent) error
 		return event.BadRequestError("该用户已经是评审团成员", nil)
 	}
 
+	// 检查利益冲突
+	if err := controller.voteJury.CheckJuror(data.VoteId, user.Id); err != nil {
+		if errors.Is(err, vote_jury.ErrJurorIsCandidate) {
+			return event.BadRequestError(err.Error(), nil)
+		}
+		return event.InternalServerError("检查利益冲突失败", err)
+	}
+
 	// 获取评审团规则检查席位
 	rule := event.Get("jury_rule").(*model.VoteJuryRule)
 
@@ -662,8 +827,9 @@ func (controller *VoteJuryController) AuditApply(event *core.RequestEvent) error
 		return event.InternalServerError("获取申请记录失败", err)
 	}
 
-	// 检查申请状态
-	if applyLog.Status() != model.VoteJuryApplyLogStatusPending {
+	// 检查申请状态，候补申请可以由管理员补录
+	previousStatus := applyLog.Status()
+	if previousStatus != model.VoteJuryApplyLogStatusPending && previousStatus != model.VoteJuryApplyLogStatusWaitlisted {
 		return event.BadRequestError("该申请已处理", nil)
 	}
 
@@ -672,6 +838,22 @@ func (controller *VoteJuryController) AuditApply(event *core.RequestEvent) error
 	if data.Status == "rejected" {
 		newStatus = model.VoteJuryApplyLogStatusRejected
 	}
+
+	// 检查利益冲突
+	if newStatus == model.VoteJuryApplyLogStatusApproved {
+		if err := controller.voteJury.CheckJuror(data.VoteId, applyLog.UserId()); err != nil {
+			if errors.Is(err, vote_jury.ErrJurorIsCandidate) {
+				return event.BadRequestError(err.Error(), nil)
+			}
+			return event.InternalServerError("检查利益冲突失败", err)
+		}
+		if err := controller.voteJury.CheckParticipation(event.Get("jury_rule").(*model.VoteJuryRule), applyLog.UserId()); err != nil {
+			if errors.Is(err, vote_jury.ErrParticipationTooLow) {
+				return event.BadRequestError(err.Error(), nil)
+			}
+			return event.InternalServerError("检查评审参与率失败", err)
+		}
+	}
 	applyLog.SetStatus(newStatus)
 	applyLog.SetAdminId(event.Auth.Id)
 
@@ -696,8 +878,8 @@ func (controller *VoteJuryController) AuditApply(event *core.RequestEvent) error
 		}
 
 		if len(approvedMembers) >= rule.Count() {
-			// 席位已满，将申请状态改回待审核
-			applyLog.SetStatus(model.VoteJuryApplyLogStatusPending)
+			// 席位已满，将申请状态改回原状态
+			applyLog.SetStatus(previousStatus)
 			applyLog.SetAdminId("")
 			_ = controller.app.Save(applyLog)
 			return event.BadRequestError("评审团席位已满", nil)
@@ -744,6 +926,7 @@ func (controller *VoteJuryController) SwitchStatus(event *core.RequestEvent) err
 	data := struct {
 		VoteId    string `json:"voteId"`
 		NewStatus string `json:"newStatus"`
+		Reason    string `json:"reason"`
 	}{}
 
 	if err := event.BindBody(&data); err != nil {
@@ -759,28 +942,14 @@ func (controller *VoteJuryController) SwitchStatus(event *core.RequestEvent) err
 	rule := event.Get("jury_rule").(*model.VoteJuryRule)
 	currentStatus := rule.Status()
 
-	// 状态流转校验（支持前进和回退）
-	validTransitions := map[model.VoteJuryRuleStatus][]model.VoteJuryRuleStatus{
-		model.VoteJuryRuleStatusPending:   {model.VoteJuryRuleStatusApplying},
-		model.VoteJuryRuleStatusApplying:  {model.VoteJuryRuleStatusPublicity, model.VoteJuryRuleStatusPending},   // 可回退到未开启
-		model.VoteJuryRuleStatusPublicity: {model.VoteJuryRuleStatusVoting, model.VoteJuryRuleStatusApplying},     // 可回退到申请中
-		model.VoteJuryRuleStatusVoting:    {model.VoteJuryRuleStatusCompleted, model.VoteJuryRuleStatusPublicity}, // 可回退到公示中
-		model.VoteJuryRuleStatusCompleted: {model.VoteJuryRuleStatusVoting},                               // 可回退到评审中
-	}
-
-	validNextStatuses := validTransitions[currentStatus]
-	if !slices.Contains(validNextStatuses, newStatus) {
-		return event.BadRequestError(fmt.Sprintf("不能从 %s 切换到 %s", currentStatus, newStatus), nil)
-	}
-
-	// 进入评审状态时，初始化轮次
-	if newStatus == model.VoteJuryRuleStatusVoting && rule.CurrentRound() == 0 {
-		rule.SetCurrentRound(1)
-	}
-
-	rule.SetStatus(newStatus)
-
-	if err := controller.app.Save(rule); err != nil {
+	if err := controller.voteJury.SwitchStatus(rule, newStatus, model.VoteJuryStatusTriggerManual, event.Auth.Id, data.Reason); err != nil {
+		switch {
+		case errors.Is(err, vote_jury.ErrInvalidTransition):
+			return event.BadRequestError(fmt.Sprintf("不能从 %s 切换到 %s", currentStatus, newStatus), nil)
+		case errors.Is(err, vote_jury.ErrSelectionStatus),
+			errors.Is(err, vote_jury.ErrOpenObjections):
+			return event.BadRequestError(err.Error(), nil)
+		}
 		return event.InternalServerError("更新状态失败", err)
 	}
 
@@ -790,8 +959,8 @@ func (controller *VoteJuryController) SwitchStatus(event *core.RequestEvent) err
 	})
 }
 
-// Calculate 手动触发算票
-func (controller *VoteJuryController) Calculate(event *core.RequestEvent) error {
+// Select 按遴选方式从申请人中遴选评审团成员
+func (controller *VoteJuryController) Select(event *core.RequestEvent) error {
 	data := struct {
 		VoteId string `json:"voteId"`
 	}{}
@@ -802,145 +971,248 @@ func (controller *VoteJuryController) Calculate(event *core.RequestEvent) error
 
 	rule := event.Get("jury_rule").(*model.VoteJuryRule)
 
-	// 检查状态
-	if rule.Status() != model.VoteJuryRuleStatusVoting {
-		return event.BadRequestError("当前状态不是评审中", nil)
+	selection, err := controller.voteJury.Select(rule, event.Auth.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, vote_jury.ErrSelectionManual),
+			errors.Is(err, vote_jury.ErrSelectionDone),
+			errors.Is(err, vote_jury.ErrSelectionStatus):
+			return event.BadRequestError(err.Error(), nil)
+		}
+		controller.logger.Error("遴选评审团失败", slog.String("voteId", data.VoteId), slog.Any("err", err))
+		return event.InternalServerError("遴选评审团失败", err)
 	}
 
-	currentRound := rule.CurrentRound()
-	if currentRound == 0 {
-		currentRound = 1
+	return event.JSON(http.StatusOK, map[string]any{
+		"message":   "遴选完成",
+		"selection": selection,
+	})
+}
+
+// DeclareConflict 登记评审与候选人之间的利益冲突
+func (controller *VoteJuryController) DeclareConflict(event *core.RequestEvent) error {
+	data := struct {
+		VoteId      string `json:"voteId"`
+		JurorId     string `json:"jurorId"`
+		CandidateId string `json:"candidateId"`
+		Reason      string `json:"reason"`
+	}{}
+
+	if err := event.BindBody(&data); err != nil {
+		return event.BadRequestError("参数错误", err)
 	}
 
-	// 检查当前轮次是否已经算过票
-	existingResult := new(model.VoteJuryResult)
-	if err := controller.app.RecordQuery(model.DbNameVoteJuryResults).
-		Where(dbx.HashExp{
-			model.VoteJuryResultFieldVoteId: data.VoteId,
-			model.VoteJuryResultFieldRound:  currentRound,
-		}).
-		One(existingResult); err == nil {
-		// 已经算过票了
-		if !existingResult.Continue() {
-			return event.BadRequestError("投票已结束，无需再次算票", nil)
-		}
-		return event.BadRequestError("当前轮次已算票，请等待下一轮投票完成后再算票", nil)
+	if data.JurorId == "" || data.CandidateId == "" {
+		return event.BadRequestError("参数不完整", nil)
 	}
 
-	// 统计当前轮次的投票
-	var voteLogs []*model.VoteJuryLog
-	if err := controller.app.RecordQuery(model.DbNameVoteJuryLogs).
+	// 检查是否已登记
+	existing := new(model.VoteJuryConflict)
+	if err := controller.app.RecordQuery(model.DbNameVoteJuryConflicts).
 		Where(dbx.HashExp{
-			model.VoteJuryLogFieldVoteId: data.VoteId,
-			model.VoteJuryLogFieldRound:  currentRound,
+			model.VoteJuryConflictFieldVoteId:      data.VoteId,
+			model.VoteJuryConflictFieldJurorId:     data.JurorId,
+			model.VoteJuryConflictFieldCandidateId: data.CandidateId,
 		}).
-		All(&voteLogs); err != nil {
-		return event.InternalServerError("获取投票记录失败", err)
+		One(existing); err == nil {
+		return event.BadRequestError("该利益冲突已登记", nil)
 	}
 
-	if len(voteLogs) == 0 {
-		return event.BadRequestError("当前轮次没有投票记录", nil)
+	collection, err := controller.app.FindCollectionByNameOrId(model.DbNameVoteJuryConflicts)
+	if err != nil {
+		return event.InternalServerError("获取利益冲突集合失败", err)
 	}
 
-	// 统计每个候选人的得票数
-	voteCount := make(map[string]int)
-	for _, log := range voteLogs {
-		voteCount[log.ToUserId()] += log.Times()
+	conflict := model.NewVoteJuryConflictFromCollection(collection)
+	conflict.SetVoteId(data.VoteId)
+	conflict.SetJurorId(data.JurorId)
+	conflict.SetCandidateId(data.CandidateId)
+	conflict.SetReason(data.Reason)
+	conflict.SetAdminId(event.Auth.Id)
+
+	if err := controller.app.Save(conflict); err != nil {
+		return event.InternalServerError("登记利益冲突失败", err)
 	}
 
-	// 找出最高票数
-	maxVotes := 0
-	for _, count := range voteCount {
-		if count > maxVotes {
-			maxVotes = count
-		}
+	return event.JSON(http.StatusOK, map[string]any{
+		"message": "登记成功",
+		"id":      conflict.Id,
+	})
+}
+
+// RemoveConflict 移除利益冲突登记
+func (controller *VoteJuryController) RemoveConflict(event *core.RequestEvent) error {
+	data := struct {
+		VoteId     string `json:"voteId"`
+		ConflictId string `json:"conflictId"`
+	}{}
+
+	if err := event.BindBody(&data); err != nil {
+		return event.BadRequestError("参数错误", err)
 	}
 
-	// 找出所有得到最高票的用户
-	topUsers := make([]string, 0)
-	for userId, count := range voteCount {
-		if count == maxVotes {
-			topUsers = append(topUsers, userId)
+	conflict := new(model.VoteJuryConflict)
+	if err := controller.app.RecordQuery(model.DbNameVoteJuryConflicts).
+		Where(dbx.HashExp{
+			model.CommonFieldId:               data.ConflictId,
+			model.VoteJuryConflictFieldVoteId: data.VoteId,
+		}).
+		One(conflict); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return event.NotFoundError("利益冲突登记不存在", nil)
 		}
+		return event.InternalServerError("获取利益冲突登记失败", err)
+	}
+
+	if err := controller.app.Delete(conflict); err != nil {
+		return event.InternalServerError("移除利益冲突登记失败", err)
 	}
 
-	// 判断是否需要进入下一轮（有平票）
-	needNextRound := len(topUsers) > 1
+	return event.JSON(http.StatusOK, map[string]any{
+		"message": "移除成功",
+	})
+}
 
-	// 如果有平票，检查决策票能否决定
-	var winner string
-	if needNextRound {
-		decisions := rule.Decisions()
-		decisionVotes := make(map[string]int)
+// GetJurorHistory 获取用户在其他投票中担任评审的记录
+func (controller *VoteJuryController) GetJurorHistory(event *core.RequestEvent) error {
+	voteId := event.Request.PathValue("voteId")
+	userId := event.Request.PathValue("userId")
 
-		for _, log := range voteLogs {
-			if slices.Contains(decisions, log.FromUserId()) && slices.Contains(topUsers, log.ToUserId()) {
-				decisionVotes[log.ToUserId()] += log.Times()
-			}
-		}
+	history, err := controller.voteJury.JurorHistory(userId, voteId)
+	if err != nil {
+		return event.InternalServerError("获取评审历史失败", err)
+	}
 
-		// 找出决策票最高的
-		maxDecisionVotes := 0
-		for _, count := range decisionVotes {
-			if count > maxDecisionVotes {
-				maxDecisionVotes = count
-			}
-		}
+	return event.JSON(http.StatusOK, history)
+}
 
-		decisionTopUsers := make([]string, 0)
-		for userId, count := range decisionVotes {
-			if count == maxDecisionVotes {
-				decisionTopUsers = append(decisionTopUsers, userId)
-			}
+// FileObjection 公示期间对评审团成员提出异议
+func (controller *VoteJuryController) FileObjection(event *core.RequestEvent) error {
+	data := struct {
+		VoteId  string `json:"voteId"`
+		JurorId string `json:"jurorId"`
+		Reason  string `json:"reason"`
+	}{}
+
+	if err := event.BindBody(&data); err != nil {
+		return event.BadRequestError("参数错误", err)
+	}
+
+	data.Reason = strings.TrimSpace(data.Reason)
+	if data.VoteId == "" || data.JurorId == "" || data.Reason == "" {
+		return event.BadRequestError("参数不完整", nil)
+	}
+
+	rule := new(model.VoteJuryRule)
+	if err := controller.app.RecordQuery(model.DbNameVoteJuryRules).
+		Where(dbx.HashExp{model.VoteJuryRuleFieldVoteId: data.VoteId}).
+		One(rule); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return event.NotFoundError("评审团规则不存在", nil)
 		}
+		return event.InternalServerError("获取评审团规则失败", err)
+	}
 
-		// 如果决策票能决定，则不需要下一轮
-		if len(decisionTopUsers) == 1 && maxDecisionVotes > 0 {
-			needNextRound = false
-			winner = decisionTopUsers[0]
+	objection, err := controller.voteJury.FileObjection(rule, model.NewUser(event.Auth), data.JurorId, data.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, vote_jury.ErrObjectionClosed),
+			errors.Is(err, vote_jury.ErrObjectionIneligible),
+			errors.Is(err, vote_jury.ErrObjectionSelf),
+			errors.Is(err, vote_jury.ErrObjectionNotJuror),
+			errors.Is(err, vote_jury.ErrObjectionExists):
+			return event.BadRequestError(err.Error(), nil)
 		}
-	} else {
-		// 没有平票，直接确定获胜者
-		winner = topUsers[0]
+		return event.InternalServerError("提交异议失败", err)
 	}
 
-	// 保存本轮结果
-	resultsJson, _ := json.Marshal(voteCount)
+	return event.JSON(http.StatusOK, map[string]any{
+		"message": "异议已提交，请等待管理员处理",
+		"id":      objection.Id,
+	})
+}
+
+// ResolveObjection 处理异议
+func (controller *VoteJuryController) ResolveObjection(event *core.RequestEvent) error {
+	data := struct {
+		VoteId      string                        `json:"voteId"`
+		ObjectionId string                        `json:"objectionId"`
+		Status      model.VoteJuryObjectionStatus `json:"status"`
+		Note        string                        `json:"note"`
+		RemoveJuror bool                          `json:"removeJuror"`
+	}{}
 
-	resultCollection, err := controller.app.FindCollectionByNameOrId(model.DbNameVoteJuryResults)
-	if err != nil {
-		return event.InternalServerError("获取结果集合失败", err)
+	if err := event.BindBody(&data); err != nil {
+		return event.BadRequestError("参数错误", err)
 	}
 
-	result := model.NewVoteJuryResultFromCollection(resultCollection)
-	result.SetVoteId(data.VoteId)
-	result.SetRound(currentRound)
-	result.SetResults(string(resultsJson))
-	result.SetContinue(needNextRound)
-	if needNextRound {
-		result.SetUserIds(topUsers) // 平票的用户进入下一轮
-	} else if winner != "" {
-		result.SetUserIds([]string{winner}) // 最终获胜者
+	data.Note = strings.TrimSpace(data.Note)
+	if data.ObjectionId == "" || data.Note == "" {
+		return event.BadRequestError("参数不完整", nil)
 	}
 
-	if err := controller.app.Save(result); err != nil {
-		return event.InternalServerError("保存投票结果失败", err)
+	rule := event.Get("jury_rule").(*model.VoteJuryRule)
+
+	objection, err := controller.voteJury.ResolveObjection(rule, data.ObjectionId, data.Status, data.Note, data.RemoveJuror, event.Auth.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return event.NotFoundError("异议不存在", nil)
+		case errors.Is(err, vote_jury.ErrObjectionHandled),
+			errors.Is(err, vote_jury.ErrObjectionStatus):
+			return event.BadRequestError(err.Error(), nil)
+		}
+		return event.InternalServerError("处理异议失败", err)
 	}
 
-	// 如果需要下一轮投票
-	if needNextRound {
-		rule.SetCurrentRound(currentRound + 1)
-		if err := controller.app.Save(rule); err != nil {
-			return event.InternalServerError("更新轮次失败", err)
+	return event.JSON(http.StatusOK, map[string]any{
+		"message": "异议已处理",
+		"status":  objection.Status(),
+		"removed": objection.Removed(),
+	})
+}
+
+// Calculate 手动触发算票
+func (controller *VoteJuryController) Calculate(event *core.RequestEvent) error {
+	data := struct {
+		VoteId string `json:"voteId"`
+	}{}
+
+	if err := event.BindBody(&data); err != nil {
+		return event.BadRequestError("参数错误", err)
+	}
+
+	rule := event.Get("jury_rule").(*model.VoteJuryRule)
+
+	calculateResult, err := controller.voteJury.Calculate(rule, model.VoteJuryStatusTriggerManual, event.Auth.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, vote_jury.ErrNotVoting),
+			errors.Is(err, vote_jury.ErrVoteFinished),
+			errors.Is(err, vote_jury.ErrRoundCalculated),
+			errors.Is(err, vote_jury.ErrNoVotes),
+			errors.Is(err, vote_jury.ErrNoCriteria),
+			errors.Is(err, vote_jury.ErrInvalidCriteria),
+			errors.Is(err, vote_jury.ErrRevealNotOpen):
+			return event.BadRequestError(err.Error(), nil)
 		}
+		controller.logger.Error("算票失败", slog.String("voteId", data.VoteId), slog.Any("err", err))
+		return event.InternalServerError("算票失败", err)
+	}
 
+	currentRound := calculateResult.Round
+	topUsers := calculateResult.TopUsers
+	winner := calculateResult.Winner
+	voteCount := calculateResult.VoteCount
+	users := controller.Users(event).Add(topUsers...).Add(winner)
+	ranking := controller.rankingUsers(users, calculateResult.Ranking)
+
+	// 如果需要下一轮投票
+	if calculateResult.NeedNextRound {
 		// 扩展平票用户信息
 		tieUsers := make([]map[string]any, 0, len(topUsers))
 		for _, userId := range topUsers {
-			user := new(model.User)
-			if err := controller.app.RecordQuery(model.DbNameUsers).
-				Where(dbx.HashExp{model.CommonFieldId: userId}).
-				One(user); err == nil {
+			if user := users.Get(userId); user != nil {
 				tieUsers = append(tieUsers, map[string]any{
 					"id":       user.Id,
 					"name":     user.Name(),
@@ -952,30 +1224,26 @@ func (controller *VoteJuryController) Calculate(event *core.RequestEvent) error
 		}
 
 		return event.JSON(http.StatusOK, map[string]any{
-			"message":       fmt.Sprintf("第 %d 轮投票结束，有 %d 人平票（%d票），进入第 %d 轮投票", currentRound, len(topUsers), maxVotes, currentRound+1),
+			"message":       fmt.Sprintf("第 %d 轮投票结束，前 %d 名中有 %d 人平票，进入第 %d 轮加赛", currentRound, calculateResult.Ranking.Winners, len(topUsers), currentRound+1),
 			"needNextRound": true,
 			"currentRound":  currentRound,
 			"nextRound":     currentRound + 1,
 			"tieUsers":      tieUsers,
+			"tieGroups":     calculateResult.Ranking.Pending,
+			"ranking":       ranking,
 			"results":       voteCount,
 		})
 	}
 
-	// 投票结束，更新状态为计票完成
-	rule.SetStatus(model.VoteJuryRuleStatusCompleted)
-	if err := controller.app.Save(rule); err != nil {
-		return event.InternalServerError("更新状态失败", err)
-	}
-
 	// 获取获胜者信息
-	winnerUser := new(model.User)
 	var winnerInfo map[string]any
 	winnerNickname := "未知用户"
-	winnerVotes := voteCount[winner]
+	winnerVotes := 0.0
+	if len(calculateResult.Ranking.Settled) > 0 {
+		winnerVotes = calculateResult.Ranking.Settled[0].Votes
+	}
 
-	if err := controller.app.RecordQuery(model.DbNameUsers).
-		Where(dbx.HashExp{model.CommonFieldId: winner}).
-		One(winnerUser); err == nil {
+	if winnerUser := users.Get(winner); winnerUser != nil {
 		winnerNickname = winnerUser.Nickname()
 		winnerInfo = map[string]any{
 			"id":       winnerUser.Id,
@@ -985,7 +1253,7 @@ func (controller *VoteJuryController) Calculate(event *core.RequestEvent) error
 			"votes":    winnerVotes,
 		}
 	} else {
-		controller.logger.Error("获取获胜者信息失败", slog.String("winnerId", winner), slog.Any("err", err))
+		controller.logger.Error("获取获胜者信息失败", slog.String("winnerId", winner))
 		winnerInfo = map[string]any{
 			"id":    winner,
 			"votes": winnerVotes,
@@ -993,27 +1261,79 @@ func (controller *VoteJuryController) Calculate(event *core.RequestEvent) error
 	}
 
 	return event.JSON(http.StatusOK, map[string]any{
-		"message":       fmt.Sprintf("投票结束！获胜者: %s (%d票)", winnerNickname, winnerVotes),
+		"message":       fmt.Sprintf("投票结束！获胜者: %s (%g)", winnerNickname, winnerVotes),
 		"needNextRound": false,
 		"currentRound":  currentRound,
 		"winner":        winnerInfo,
+		"ranking":       ranking,
 		"results":       voteCount,
 	})
 }
 
-// Apply 用户申请加入评审团
-func (controller *VoteJuryController) Apply(event *core.RequestEvent) error {
+// scoredJurors 获取指定轮次已提交评分的评审
+func (controller *VoteJuryController) scoredJurors(voteId string, round int) []string {
+	var scores []*model.VoteJuryScore
+	if err := controller.app.RecordQuery(model.DbNameVoteJuryScores).
+		Where(dbx.HashExp{
+			model.VoteJuryScoreFieldVoteId: voteId,
+			model.VoteJuryScoreFieldRound:  round,
+		}).
+		All(&scores); err != nil {
+		return nil
+	}
+
+	jurorIds := make([]string, 0, len(scores))
+	for _, score := range scores {
+		if !slices.Contains(jurorIds, score.FromUserId()) {
+			jurorIds = append(jurorIds, score.FromUserId())
+		}
+	}
+	return jurorIds
+}
+
+// rankingUsers 扩展已确定名次的用户信息
+func (controller *VoteJuryController) rankingUsers(users *UserLoader, ranking *model.VoteJuryRanking) []map[string]any {
+	if ranking == nil {
+		return nil
+	}
+
+	for _, entry := range ranking.Settled {
+		users.Add(entry.UserId)
+	}
+
+	list := make([]map[string]any, 0, len(ranking.Settled))
+	for _, entry := range ranking.Settled {
+		item := map[string]any{
+			"id":    entry.UserId,
+			"rank":  entry.Rank,
+			"votes": entry.Votes,
+			"round": entry.Round,
+		}
+		if user := users.Get(entry.UserId); user != nil {
+			item["name"] = user.Name()
+			item["nickname"] = user.Nickname()
+			item["avatar"] = user.Avatar()
+		}
+		list = append(list, item)
+	}
+	return list
+}
+
+// Score 评审团成员按评分项为候选人打分，同一轮重复提交时覆盖之前的评分
+func (controller *VoteJuryController) Score(event *core.RequestEvent) error {
 	data := struct {
-		VoteId string `json:"voteId"`
-		Reason string `json:"reason"`
+		VoteId   string             `json:"voteId"`
+		ToUserId string             `json:"toUserId"`
+		Scores   map[string]float64 `json:"scores"`
+		Comment  string             `json:"comment"`
 	}{}
 
 	if err := event.BindBody(&data); err != nil {
 		return event.BadRequestError("参数错误", err)
 	}
 
-	if data.VoteId == "" {
-		return event.BadRequestError("投票ID不能为空", nil)
+	if data.VoteId == "" || data.ToUserId == "" {
+		return event.BadRequestError("参数不完整", nil)
 	}
 
 	// 获取评审团规则
@@ -1027,12 +1347,198 @@ func (controller *VoteJuryController) Apply(event *core.RequestEvent) error {
 		return event.InternalServerError("获取评审团规则失败", err)
 	}
 
-	// 检查状态是否允许申请
-	if rule.Status() != model.VoteJuryRuleStatusApplying {
-		return event.BadRequestError("当前不在申请阶段", nil)
+	if rule.Status() != model.VoteJuryRuleStatusVoting {
+		return event.BadRequestError("当前不在评审阶段", nil)
+	}
+
+	if rule.Scoring() != model.VoteJuryScoringRubric {
+		return event.BadRequestError(vote_jury.ErrNotRubric.Error(), nil)
+	}
+
+	criteria, err := vote_jury.Criteria(rule)
+	if err != nil {
+		return event.BadRequestError(err.Error(), nil)
+	}
+	if err := vote_jury.ValidateScores(criteria, data.Scores); err != nil {
+		return event.BadRequestError(err.Error(), nil)
 	}
 
 	userId := event.Auth.Id
+	currentRound := rule.CurrentRound()
+	if currentRound == 0 {
+		currentRound = 1
+	}
+
+	// 检查是否是评审团成员
+	juryUser := new(model.VoteJuryUser)
+	if err := controller.app.RecordQuery(model.DbNameVoteJuryUsers).
+		Where(dbx.HashExp{
+			model.VoteJuryUserFieldVoteId: data.VoteId,
+			model.VoteJuryUserFieldUserId: userId,
+			model.VoteJuryUserFieldStatus: model.VoteJuryUserStatusApproved,
+		}).
+		One(juryUser); err != nil {
+		return event.ForbiddenError("您不是评审团成员", nil)
+	}
+
+	// 检查利益冲突
+	if err := controller.voteJury.CheckVote(data.VoteId, userId, data.ToUserId); err != nil {
+		switch {
+		case errors.Is(err, vote_jury.ErrSelfVote),
+			errors.Is(err, vote_jury.ErrJurorIsCandidate),
+			errors.Is(err, vote_jury.ErrDeclaredConflict):
+			return event.BadRequestError(err.Error(), nil)
+		}
+		return event.InternalServerError("检查利益冲突失败", err)
+	}
+
+	// 加赛轮次只能为平票的候选人评分
+	if currentRound > 1 {
+		lastResult := new(model.VoteJuryResult)
+		if err := controller.app.RecordQuery(model.DbNameVoteJuryResults).
+			Where(dbx.HashExp{
+				model.VoteJuryResultFieldVoteId: data.VoteId,
+				model.VoteJuryResultFieldRound:  currentRound - 1,
+			}).
+			One(lastResult); err != nil {
+			return event.InternalServerError("获取上一轮结果失败", err)
+		}
+
+		if !slices.Contains(lastResult.UserIds(), data.ToUserId) {
+			return event.BadRequestError("该用户不在本轮候选名单中", nil)
+		}
+	}
+
+	scoresJson, _ := json.Marshal(data.Scores)
+
+	score := new(model.VoteJuryScore)
+	if err := controller.app.RecordQuery(model.DbNameVoteJuryScores).
+		Where(dbx.HashExp{
+			model.VoteJuryScoreFieldVoteId:     data.VoteId,
+			model.VoteJuryScoreFieldRound:      currentRound,
+			model.VoteJuryScoreFieldFromUserId: userId,
+			model.VoteJuryScoreFieldToUserId:   data.ToUserId,
+		}).
+		One(score); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return event.InternalServerError("获取评分记录失败", err)
+		}
+
+		collection, err := controller.app.FindCollectionByNameOrId(model.DbNameVoteJuryScores)
+		if err != nil {
+			return event.InternalServerError("获取评分集合失败", err)
+		}
+		score = model.NewVoteJuryScoreFromCollection(collection)
+		score.SetVoteId(data.VoteId)
+		score.SetRound(currentRound)
+		score.SetFromUserId(userId)
+		score.SetToUserId(data.ToUserId)
+	}
+	score.SetScores(string(scoresJson))
+	score.SetComment(data.Comment)
+
+	if err := controller.app.Save(score); err != nil {
+		return event.InternalServerError("保存评分失败", err)
+	}
+
+	return event.JSON(http.StatusOK, map[string]any{
+		"message": "评分成功",
+		"round":   currentRound,
+		"scores":  data.Scores,
+	})
+}
+
+// GetMyScores 获取当前用户在本轮提交的评分
+func (controller *VoteJuryController) GetMyScores(event *core.RequestEvent) error {
+	voteId := event.Request.PathValue("voteId")
+
+	rule := new(model.VoteJuryRule)
+	if err := controller.app.RecordQuery(model.DbNameVoteJuryRules).
+		Where(dbx.HashExp{model.VoteJuryRuleFieldVoteId: voteId}).
+		One(rule); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return event.NotFoundError("评审团规则不存在", nil)
+		}
+		return event.InternalServerError("获取评审团规则失败", err)
+	}
+
+	currentRound := rule.CurrentRound()
+	if currentRound == 0 {
+		currentRound = 1
+	}
+
+	var records []*model.VoteJuryScore
+	if err := controller.app.RecordQuery(model.DbNameVoteJuryScores).
+		Where(dbx.HashExp{
+			model.VoteJuryScoreFieldVoteId:     voteId,
+			model.VoteJuryScoreFieldRound:      currentRound,
+			model.VoteJuryScoreFieldFromUserId: event.Auth.Id,
+		}).
+		All(&records); err != nil {
+		return event.InternalServerError("获取评分记录失败", err)
+	}
+
+	list := make([]map[string]any, 0, len(records))
+	for _, record := range records {
+		var scores map[string]float64
+		_ = json.Unmarshal([]byte(record.Scores()), &scores)
+		list = append(list, map[string]any{
+			"toUserId": record.ToUserId(),
+			"scores":   scores,
+			"comment":  record.Comment(),
+			"updated":  record.Updated().String(),
+		})
+	}
+
+	criteria, _ := vote_jury.Criteria(rule)
+
+	return event.JSON(http.StatusOK, map[string]any{
+		"round":    currentRound,
+		"criteria": criteria,
+		"scores":   list,
+	})
+}
+
+// Apply 用户申请加入评审团
+func (controller *VoteJuryController) Apply(event *core.RequestEvent) error {
+	data := struct {
+		VoteId string `json:"voteId"`
+		Reason string `json:"reason"`
+	}{}
+
+	if err := event.BindBody(&data); err != nil {
+		return event.BadRequestError("参数错误", err)
+	}
+
+	if data.VoteId == "" {
+		return event.BadRequestError("投票ID不能为空", nil)
+	}
+
+	// 获取评审团规则
+	rule := new(model.VoteJuryRule)
+	if err := controller.app.RecordQuery(model.DbNameVoteJuryRules).
+		Where(dbx.HashExp{model.VoteJuryRuleFieldVoteId: data.VoteId}).
+		One(rule); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return event.NotFoundError("评审团规则不存在", nil)
+		}
+		return event.InternalServerError("获取评审团规则失败", err)
+	}
+
+	// 检查状态是否允许申请
+	if rule.Status() != model.VoteJuryRuleStatusApplying {
+		return event.BadRequestError("当前不在申请阶段", nil)
+	}
+
+	userId := event.Auth.Id
+
+	// 检查往届评审参与率
+	if err := controller.voteJury.CheckParticipation(rule, userId); err != nil {
+		if errors.Is(err, vote_jury.ErrParticipationTooLow) {
+			return event.BadRequestError(err.Error(), nil)
+		}
+		return event.InternalServerError("检查评审参与率失败", err)
+	}
 
 	// 检查是否已申请
 	existingLog := new(model.VoteJuryApplyLog)
@@ -1099,6 +1605,14 @@ func (controller *VoteJuryController) Vote(event *core.RequestEvent) error {
 		return event.BadRequestError("当前不在投票阶段", nil)
 	}
 
+	if rule.Scoring() == model.VoteJuryScoringRubric {
+		return event.BadRequestError("当前评审团为评分模式，请提交评分", nil)
+	}
+
+	if rule.SecretBallot() {
+		return event.BadRequestError(vote_jury.ErrSecretBallot.Error(), nil)
+	}
+
 	userId := event.Auth.Id
 	currentRound := rule.CurrentRound()
 	if currentRound == 0 {
@@ -1156,6 +1670,17 @@ func (controller *VoteJuryController) Vote(event *core.RequestEvent) error {
 		}
 	}
 
+	// 检查利益冲突
+	if err := controller.voteJury.CheckVote(data.VoteId, userId, data.ToUserId); err != nil {
+		switch {
+		case errors.Is(err, vote_jury.ErrSelfVote),
+			errors.Is(err, vote_jury.ErrJurorIsCandidate),
+			errors.Is(err, vote_jury.ErrDeclaredConflict):
+			return event.BadRequestError(err.Error(), nil)
+		}
+		return event.InternalServerError("检查利益冲突失败", err)
+	}
+
 	// 如果是第2轮及以后，检查被投用户是否在候选名单中
 	if currentRound > 1 {
 		// 获取上一轮结果
@@ -1187,14 +1712,386 @@ func (controller *VoteJuryController) Vote(event *core.RequestEvent) error {
 	voteLog.SetTimes(1)
 	voteLog.SetRound(currentRound)
 	voteLog.SetComment(data.Comment)
+	voteLog.SetReceipt(vote_chain.NewReceipt())
 
-	if err := controller.app.Save(voteLog); err != nil {
+	// 投票记录与哈希链同事务写入
+	if err := controller.app.RunInTransaction(func(txApp core.App) error {
+		if err := txApp.Save(voteLog); err != nil {
+			return err
+		}
+		return controller.voteChain.AppendJuryLog(txApp, model.VoteChainKindCast, voteLog)
+	}); err != nil {
 		return event.InternalServerError("保存投票记录失败", err)
 	}
 
 	return event.JSON(http.StatusOK, map[string]any{
 		"message":   "投票成功",
 		"remaining": vote.Times() - usedVotes - 1,
+		"receipt":   voteLog.Receipt(),
+	})
+}
+
+// CommitVote 秘密投票承诺阶段提交选票哈希，揭示前可重新提交覆盖
+func (controller *VoteJuryController) CommitVote(event *core.RequestEvent) error {
+	data := struct {
+		VoteId     string `json:"voteId"`
+		Commitment string `json:"commitment"`
+	}{}
+
+	if err := event.BindBody(&data); err != nil {
+		return event.BadRequestError("参数错误", err)
+	}
+
+	data.Commitment = strings.ToLower(strings.TrimSpace(data.Commitment))
+	if decoded, err := hex.DecodeString(data.Commitment); err != nil || len(decoded) != sha256.Size {
+		return event.BadRequestError("投票承诺格式错误", nil)
+	}
+
+	rule := new(model.VoteJuryRule)
+	if err := controller.app.RecordQuery(model.DbNameVoteJuryRules).
+		Where(dbx.HashExp{model.VoteJuryRuleFieldVoteId: data.VoteId}).
+		One(rule); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return event.NotFoundError("评审团规则不存在", nil)
+		}
+		return event.InternalServerError("获取评审团规则失败", err)
+	}
+
+	if !rule.SecretBallot() {
+		return event.BadRequestError(vote_jury.ErrNotSecretBallot.Error(), nil)
+	}
+	if rule.Status() != model.VoteJuryRuleStatusVoting {
+		return event.BadRequestError("当前不在投票阶段", nil)
+	}
+	if rule.Revealing() {
+		return event.BadRequestError(vote_jury.ErrCommitClosed.Error(), nil)
+	}
+
+	userId := event.Auth.Id
+	currentRound := rule.CurrentRound()
+	if currentRound == 0 {
+		currentRound = 1
+	}
+
+	// 检查是否是评审团成员
+	juryUser := new(model.VoteJuryUser)
+	if err := controller.app.RecordQuery(model.DbNameVoteJuryUsers).
+		Where(dbx.HashExp{
+			model.VoteJuryUserFieldVoteId: data.VoteId,
+			model.VoteJuryUserFieldUserId: userId,
+			model.VoteJuryUserFieldStatus: model.VoteJuryUserStatusApproved,
+		}).
+		One(juryUser); err != nil {
+		return event.ForbiddenError("您不是评审团成员", nil)
+	}
+
+	commit := new(model.VoteJuryCommit)
+	if err := controller.app.RecordQuery(model.DbNameVoteJuryCommits).
+		Where(dbx.HashExp{
+			model.VoteJuryCommitFieldVoteId:     data.VoteId,
+			model.VoteJuryCommitFieldRound:      currentRound,
+			model.VoteJuryCommitFieldFromUserId: userId,
+		}).
+		One(commit); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return event.InternalServerError("获取投票承诺失败", err)
+		}
+
+		collection, err := controller.app.FindCollectionByNameOrId(model.DbNameVoteJuryCommits)
+		if err != nil {
+			return event.InternalServerError("获取投票承诺集合失败", err)
+		}
+		commit = model.NewVoteJuryCommitFromCollection(collection)
+		commit.SetVoteId(data.VoteId)
+		commit.SetRound(currentRound)
+		commit.SetFromUserId(userId)
+	}
+	commit.SetCommitment(data.Commitment)
+
+	if err := controller.app.Save(commit); err != nil {
+		return event.InternalServerError("保存投票承诺失败", err)
+	}
+
+	return event.JSON(http.StatusOK, map[string]any{
+		"message":    "投票承诺已提交，请妥善保存选票与随机数，揭示阶段需要原样提交",
+		"round":      currentRound,
+		"commitment": data.Commitment,
+	})
+}
+
+// RevealVote 揭示阶段提交选票原文与随机数，与承诺一致时写入投票记录
+func (controller *VoteJuryController) RevealVote(event *core.RequestEvent) error {
+	data := struct {
+		VoteId    string   `json:"voteId"`
+		ToUserIds []string `json:"toUserIds"`
+		Nonce     string   `json:"nonce"`
+	}{}
+
+	if err := event.BindBody(&data); err != nil {
+		return event.BadRequestError("参数错误", err)
+	}
+
+	if data.VoteId == "" || data.Nonce == "" {
+		return event.BadRequestError("参数不完整", nil)
+	}
+
+	rule := new(model.VoteJuryRule)
+	if err := controller.app.RecordQuery(model.DbNameVoteJuryRules).
+		Where(dbx.HashExp{model.VoteJuryRuleFieldVoteId: data.VoteId}).
+		One(rule); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return event.NotFoundError("评审团规则不存在", nil)
+		}
+		return event.InternalServerError("获取评审团规则失败", err)
+	}
+
+	if !rule.SecretBallot() {
+		return event.BadRequestError(vote_jury.ErrNotSecretBallot.Error(), nil)
+	}
+	if rule.Status() != model.VoteJuryRuleStatusVoting {
+		return event.BadRequestError("当前不在投票阶段", nil)
+	}
+	if !rule.Revealing() {
+		return event.BadRequestError(vote_jury.ErrRevealNotOpen.Error(), nil)
+	}
+
+	userId := event.Auth.Id
+	currentRound := rule.CurrentRound()
+	if currentRound == 0 {
+		currentRound = 1
+	}
+
+	commit := new(model.VoteJuryCommit)
+	if err := controller.app.RecordQuery(model.DbNameVoteJuryCommits).
+		Where(dbx.HashExp{
+			model.VoteJuryCommitFieldVoteId:     data.VoteId,
+			model.VoteJuryCommitFieldRound:      currentRound,
+			model.VoteJuryCommitFieldFromUserId: userId,
+		}).
+		One(commit); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return event.BadRequestError(vote_jury.ErrCommitNotFound.Error(), nil)
+		}
+		return event.InternalServerError("获取投票承诺失败", err)
+	}
+
+	if commit.Revealed() {
+		return event.BadRequestError(vote_jury.ErrAlreadyRevealed.Error(), nil)
+	}
+
+	if vote_jury.BallotCommitment(data.VoteId, currentRound, data.ToUserIds, data.Nonce) != commit.Commitment() {
+		return event.BadRequestError(vote_jury.ErrCommitMismatch.Error(), nil)
+	}
+
+	// 与普通投票相同的规则校验选票
+	vote := new(model.Vote)
+	if err := controller.app.RecordQuery(model.DbNameVotes).
+		Where(dbx.HashExp{model.CommonFieldId: data.VoteId}).
+		One(vote); err != nil {
+		return event.InternalServerError("获取投票配置失败", err)
+	}
+
+	if len(data.ToUserIds) > vote.Times() {
+		return event.BadRequestError("选票超过可投票次数", nil)
+	}
+
+	var candidates []string
+	if currentRound > 1 {
+		lastResult := new(model.VoteJuryResult)
+		if err := controller.app.RecordQuery(model.DbNameVoteJuryResults).
+			Where(dbx.HashExp{
+				model.VoteJuryResultFieldVoteId: data.VoteId,
+				model.VoteJuryResultFieldRound:  currentRound - 1,
+			}).
+			One(lastResult); err != nil {
+			return event.InternalServerError("获取上一轮结果失败", err)
+		}
+		candidates = lastResult.UserIds()
+	}
+
+	seen := make(map[string]bool, len(data.ToUserIds))
+	for _, toUserId := range data.ToUserIds {
+		if !vote.Repeat() && seen[toUserId] {
+			return event.BadRequestError("选票中重复投给了同一用户", nil)
+		}
+		seen[toUserId] = true
+
+		if candidates != nil && !slices.Contains(candidates, toUserId) {
+			return event.BadRequestError("选票中有用户不在本轮候选名单中", nil)
+		}
+
+		if err := controller.voteJury.CheckVote(data.VoteId, userId, toUserId); err != nil {
+			switch {
+			case errors.Is(err, vote_jury.ErrSelfVote),
+				errors.Is(err, vote_jury.ErrJurorIsCandidate),
+				errors.Is(err, vote_jury.ErrDeclaredConflict):
+				return event.BadRequestError(err.Error(), nil)
+			}
+			return event.InternalServerError("检查利益冲突失败", err)
+		}
+	}
+
+	voteLogCollection, err := controller.app.FindCollectionByNameOrId(model.DbNameVoteJuryLogs)
+	if err != nil {
+		return event.InternalServerError("获取投票日志集合失败", err)
+	}
+
+	ballotJson, _ := json.Marshal(data.ToUserIds)
+	receipts := make([]string, 0, len(data.ToUserIds))
+
+	// 揭示记录、投票记录与哈希链同事务写入
+	if err := controller.app.RunInTransaction(func(txApp core.App) error {
+		// 先以条件更新占用承诺，并发的重复揭示只有一个能成功
+		res, err := txApp.DB().Update(model.DbNameVoteJuryCommits, dbx.Params{
+			model.VoteJuryCommitFieldRevealed: true,
+		}, dbx.HashExp{
+			model.CommonFieldId:               commit.Id,
+			model.VoteJuryCommitFieldRevealed: false,
+		}).Execute()
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err != nil {
+			return err
+		} else if affected != 1 {
+			return vote_jury.ErrAlreadyRevealed
+		}
+
+		for _, toUserId := range data.ToUserIds {
+			voteLog := model.NewVoteJuryLogFromCollection(voteLogCollection)
+			voteLog.SetVoteId(data.VoteId)
+			voteLog.SetFromUserId(userId)
+			voteLog.SetToUserId(toUserId)
+			voteLog.SetTimes(1)
+			voteLog.SetRound(currentRound)
+			voteLog.SetReceipt(vote_chain.NewReceipt())
+			if err := txApp.Save(voteLog); err != nil {
+				return err
+			}
+			if err := controller.voteChain.AppendJuryLog(txApp, model.VoteChainKindCast, voteLog); err != nil {
+				return err
+			}
+			receipts = append(receipts, voteLog.Receipt())
+		}
+
+		commit.SetRevealed(true)
+		commit.SetBallot(string(ballotJson))
+		commit.SetNonce(data.Nonce)
+		commit.SetRevealedAt(types.NowDateTime())
+		return txApp.Save(commit)
+	}); err != nil {
+		if errors.Is(err, vote_jury.ErrAlreadyRevealed) {
+			return event.BadRequestError(err.Error(), nil)
+		}
+		return event.InternalServerError("保存揭示结果失败", err)
+	}
+
+	return event.JSON(http.StatusOK, map[string]any{
+		"message":  "揭示成功",
+		"round":    currentRound,
+		"receipts": receipts,
+	})
+}
+
+// RollbackRound 回滚最近一轮算票
+func (controller *VoteJuryController) RollbackRound(event *core.RequestEvent) error {
+	data := struct {
+		VoteId string `json:"voteId"`
+		Reason string `json:"reason"`
+		Force  bool   `json:"force"` // 已执行奖励发放计划时仍然回滚
+	}{}
+
+	if err := event.BindBody(&data); err != nil {
+		return event.BadRequestError("参数错误", err)
+	}
+
+	data.Reason = strings.TrimSpace(data.Reason)
+	if data.Reason == "" {
+		return event.BadRequestError("请填写回滚原因", nil)
+	}
+
+	rule := event.Get("jury_rule").(*model.VoteJuryRule)
+
+	result, err := controller.voteJury.RollbackRound(rule, event.Auth.Id, data.Reason, data.Force)
+	if err != nil {
+		switch {
+		case errors.Is(err, vote_jury.ErrRollbackStatus),
+			errors.Is(err, vote_jury.ErrNoResult),
+			errors.Is(err, vote_jury.ErrNextRoundStarted),
+			errors.Is(err, vote_jury.ErrRewardExecuted):
+			return event.BadRequestError(err.Error(), nil)
+		}
+		controller.logger.Error("回滚算票失败", slog.String("voteId", data.VoteId), slog.Any("err", err))
+		return event.InternalServerError("回滚算票失败", err)
+	}
+
+	return event.JSON(http.StatusOK, map[string]any{
+		"message":       fmt.Sprintf("已回滚第%d轮算票，自动算票已关闭", result.Round()),
+		"round":         rule.CurrentRound(),
+		"status":        rule.Status(),
+		"autoCalculate": rule.AutoCalculate(),
+	})
+}
+
+// OpenReveal 管理员结束承诺阶段并开启揭示阶段
+func (controller *VoteJuryController) OpenReveal(event *core.RequestEvent) error {
+	data := struct {
+		VoteId string `json:"voteId"`
+	}{}
+
+	if err := event.BindBody(&data); err != nil {
+		return event.BadRequestError("参数错误", err)
+	}
+
+	rule := event.Get("jury_rule").(*model.VoteJuryRule)
+
+	if err := controller.voteJury.OpenReveal(rule, model.VoteJuryStatusTriggerManual, event.Auth.Id); err != nil {
+		switch {
+		case errors.Is(err, vote_jury.ErrNotSecretBallot),
+			errors.Is(err, vote_jury.ErrNotVoting),
+			errors.Is(err, vote_jury.ErrRevealOpened):
+			return event.BadRequestError(err.Error(), nil)
+		}
+		return event.InternalServerError("开启揭示阶段失败", err)
+	}
+
+	return event.JSON(http.StatusOK, map[string]any{
+		"message":     "揭示阶段已开启",
+		"round":       rule.CurrentRound(),
+		"revealStart": rule.RevealStart().String(),
+	})
+}
+
+// Remind 手动提醒尚未投票的评审团成员
+func (controller *VoteJuryController) Remind(event *core.RequestEvent) error {
+	data := struct {
+		VoteId string `json:"voteId"`
+	}{}
+
+	if err := event.BindBody(&data); err != nil {
+		return event.BadRequestError("参数错误", err)
+	}
+
+	rule := event.Get("jury_rule").(*model.VoteJuryRule)
+
+	plan, err := controller.juryReminder.Remind(rule, event.Auth.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, jury_reminder.ErrNotVoting),
+			errors.Is(err, jury_reminder.ErrNoDeadline):
+			return event.BadRequestError(err.Error(), nil)
+		case errors.Is(err, jury_reminder.ErrReminderRun):
+			return event.TooManyRequestsError(err.Error(), nil)
+		}
+		return event.InternalServerError("发送提醒失败", err)
+	}
+
+	return event.JSON(http.StatusOK, map[string]any{
+		"message":  fmt.Sprintf("已开始提醒 %d 名评审团成员", len(plan.Targets)),
+		"round":    plan.Round,
+		"phase":    plan.Phase,
+		"deadline": plan.Deadline,
+		"targets":  plan.Targets,
 	})
 }
 
@@ -1229,6 +2126,11 @@ func (controller *VoteJuryController) CancelVote(event *core.RequestEvent) error
 		return event.BadRequestError("当前不在投票阶段", nil)
 	}
 
+	// 秘密投票揭示后不能撤销
+	if rule.SecretBallot() {
+		return event.BadRequestError("秘密投票不支持撤销", nil)
+	}
+
 	currentRound := rule.CurrentRound()
 	if currentRound == 0 {
 		currentRound = 1
@@ -1283,7 +2185,12 @@ func (controller *VoteJuryController) CancelVote(event *core.RequestEvent) error
 	// 删除投票记录
 	cancelledCount := 0
 	for _, log := range voteLogs {
-		if err := controller.app.Delete(log); err != nil {
+		if err := controller.app.RunInTransaction(func(txApp core.App) error {
+			if err := txApp.Delete(log); err != nil {
+				return err
+			}
+			return controller.voteChain.AppendJuryLog(txApp, model.VoteChainKindRevoke, log)
+		}); err != nil {
 			controller.logger.Error("删除投票记录失败", slog.Any("err", err))
 		} else {
 			cancelledCount++
@@ -1320,29 +2227,55 @@ func (controller *VoteJuryController) GetResult(event *core.RequestEvent) error
 		return event.InternalServerError("获取投票结果失败", err)
 	}
 
-	// 格式化结果
-	roundResults := make([]map[string]any, 0, len(results))
+	// 一次查询所有轮次的投票记录，并收集需要展开的用户
+	users := controller.Users(event)
+	for _, juryUser := range juryUsers {
+		users.Add(juryUser.UserId())
+	}
+	var allVoteLogs []*model.VoteJuryLog
+	if len(results) > 0 {
+		if err := controller.app.RecordQuery(model.DbNameVoteJuryLogs).
+			Where(dbx.HashExp{model.VoteJuryLogFieldVoteId: voteId}).
+			All(&allVoteLogs); err != nil {
+			controller.logger.Warn("获取投票记录失败", slog.Any("err", err))
+		}
+	}
+	voteLogsByRound := make(map[int][]*model.VoteJuryLog)
+	for _, log := range allVoteLogs {
+		voteLogsByRound[log.Round()] = append(voteLogsByRound[log.Round()], log)
+		users.Add(log.FromUserId(), log.ToUserId())
+	}
+	parsedResults := make(map[string]map[string]float64, len(results))
 	for _, result := range results {
-		// 解析results JSON
-		var voteResults map[string]int
+		var voteResults map[string]float64
 		if err := json.Unmarshal([]byte(result.Results()), &voteResults); err != nil {
 			controller.logger.Error("解析投票结果失败", slog.Any("err", err))
 			continue
 		}
+		parsedResults[result.Id] = voteResults
+		for userId := range voteResults {
+			users.Add(userId)
+		}
+	}
+
+	// 格式化结果
+	roundResults := make([]map[string]any, 0, len(results))
+	for _, result := range results {
+		voteResults, ok := parsedResults[result.Id]
+		if !ok {
+			continue
+		}
 
 		// 统计该轮投票的人数
-		var roundVoteLogs []*model.VoteJuryLog
-		controller.app.RecordQuery(model.DbNameVoteJuryLogs).
-			Where(dbx.HashExp{
-				model.VoteJuryLogFieldVoteId: voteId,
-				model.VoteJuryLogFieldRound:  result.Round(),
-			}).
-			All(&roundVoteLogs)
+		roundVoteLogs := voteLogsByRound[result.Round()]
 
 		votedUserIds := make(map[string]bool)
 		for _, log := range roundVoteLogs {
 			votedUserIds[log.FromUserId()] = true
 		}
+		for _, jurorId := range controller.scoredJurors(voteId, result.Round()) {
+			votedUserIds[jurorId] = true
+		}
 		votedCount := len(votedUserIds)
 		abstainCount := len(juryUsers) - votedCount
 
@@ -1350,16 +2283,8 @@ func (controller *VoteJuryController) GetResult(event *core.RequestEvent) error
 		abstainUsers := make([]map[string]any, 0)
 		for _, juryUser := range juryUsers {
 			if !votedUserIds[juryUser.UserId()] {
-				user := new(model.User)
-				if err := controller.app.RecordQuery(model.DbNameUsers).
-					Where(dbx.HashExp{model.CommonFieldId: juryUser.UserId()}).
-					One(user); err == nil {
-					abstainUsers = append(abstainUsers, map[string]any{
-						"id":       user.Id,
-						"name":     user.Name(),
-						"nickname": user.Nickname(),
-						"avatar":   user.Avatar(),
-					})
+				if userData := users.Brief(juryUser.UserId()); userData != nil {
+					abstainUsers = append(abstainUsers, userData)
 				}
 			}
 		}
@@ -1368,21 +2293,9 @@ func (controller *VoteJuryController) GetResult(event *core.RequestEvent) error
 		// 结构: toUserId -> [{fromUserId, fromUser, times}]
 		voteDetailsMap := make(map[string][]map[string]any)
 		for _, log := range roundVoteLogs {
-			fromUser := new(model.User)
-			var fromUserData map[string]any
-			if err := controller.app.RecordQuery(model.DbNameUsers).
-				Where(dbx.HashExp{model.CommonFieldId: log.FromUserId()}).
-				One(fromUser); err == nil {
-				fromUserData = map[string]any{
-					"id":       fromUser.Id,
-					"name":     fromUser.Name(),
-					"nickname": fromUser.Nickname(),
-					"avatar":   fromUser.Avatar(),
-				}
-			}
 			voteDetailsMap[log.ToUserId()] = append(voteDetailsMap[log.ToUserId()], map[string]any{
 				"fromUserId": log.FromUserId(),
-				"fromUser":   fromUserData,
+				"fromUser":   users.Brief(log.FromUserId()),
 				"times":      log.Times(),
 			})
 		}
@@ -1390,29 +2303,26 @@ func (controller *VoteJuryController) GetResult(event *core.RequestEvent) error
 		// 扩展用户信息
 		resultWithUsers := make([]map[string]any, 0)
 		for userId, count := range voteResults {
-			user := new(model.User)
-			var userData map[string]any
-			if err := controller.app.RecordQuery(model.DbNameUsers).
-				Where(dbx.HashExp{model.CommonFieldId: userId}).
-				One(user); err == nil {
-				userData = map[string]any{
-					"id":       user.Id,
-					"name":     user.Name(),
-					"nickname": user.Nickname(),
-					"avatar":   user.Avatar(),
-				}
-			}
 			resultWithUsers = append(resultWithUsers, map[string]any{
 				"userId":      userId,
 				"count":       count,
-				"user":        userData,
+				"user":        users.Brief(userId),
 				"voteDetails": voteDetailsMap[userId], // 添加投票详情
 			})
 		}
 
+		// 评分模式下的各评分项明细
+		var breakdown map[string]*model.VoteJuryScoreBreakdown
+		if result.Breakdown() != "" {
+			if err := json.Unmarshal([]byte(result.Breakdown()), &breakdown); err != nil {
+				controller.logger.Error("解析评分明细失败", slog.Any("err", err))
+			}
+		}
+
 		roundResults = append(roundResults, map[string]any{
 			"round":        result.Round(),
 			"results":      resultWithUsers,
+			"breakdown":    breakdown,
 			"continue":     result.Continue(),
 			"userIds":      result.UserIds(),
 			"votedCount":   votedCount,
@@ -1433,34 +2343,28 @@ func (controller *VoteJuryController) GetResult(event *core.RequestEvent) error
 	// 扩展用户信息
 	members := make([]map[string]any, 0, len(juryUsers))
 	for _, juryUser := range juryUsers {
-		user := new(model.User)
-		if err := controller.app.RecordQuery(model.DbNameUsers).
-			Where(dbx.HashExp{model.CommonFieldId: juryUser.UserId()}).
-			One(user); err == nil {
-			members = append(members, map[string]any{
-				"id":       user.Id,
-				"name":     user.Name(),
-				"nickname": user.Nickname(),
-				"avatar":   user.Avatar(),
-			})
+		if userData := users.Brief(juryUser.UserId()); userData != nil {
+			members = append(members, userData)
 		}
 	}
 
 	// 检查是否已有最终获胜者
 	var finalWinner map[string]any
+	var finalRanking []map[string]any
 	isVoteCompleted := false
 	if len(results) > 0 {
 		lastResult := results[len(results)-1]
-		if !lastResult.Continue() && len(lastResult.UserIds()) == 1 {
+		if !lastResult.Continue() && len(lastResult.UserIds()) > 0 {
 			isVoteCompleted = true
 			winnerId := lastResult.UserIds()[0]
-			user := new(model.User)
-			if err := controller.app.RecordQuery(model.DbNameUsers).
-				Where(dbx.HashExp{model.CommonFieldId: winnerId}).
-				One(user); err == nil {
-				var voteResults map[string]int
-				json.Unmarshal([]byte(lastResult.Results()), &voteResults)
 
+			// 多名次评审时返回完整名次
+			ranking, err := vote_jury.ParseRanking(lastResult)
+			if err != nil {
+				return event.InternalServerError("解析评审名次失败", err)
+			}
+			finalRanking = controller.rankingUsers(users, ranking)
+			if user := users.Get(winnerId); user != nil {
 				// 获取获胜者的文章
 				var winnerArticles []map[string]any
 				activity := new(model.Activity)
@@ -1494,7 +2398,7 @@ func (controller *VoteJuryController) GetResult(event *core.RequestEvent) error
 					"name":     user.Name(),
 					"nickname": user.Nickname(),
 					"avatar":   user.Avatar(),
-					"votes":    voteResults[winnerId],
+					"votes":    ranking.Settled[0].Votes,
 					"articles": winnerArticles,
 				}
 			}
@@ -1502,20 +2406,24 @@ func (controller *VoteJuryController) GetResult(event *core.RequestEvent) error
 	}
 
 	// 检查当前用户是否是管理员
-	isAdmin := false
-	if event.Auth != nil {
-		admins := rule.Admins()
-		isAdmin = slices.Contains(admins, event.Auth.Id)
+	isAdmin := controller.permission.HasForVote(event.Auth, model.PermissionJuryManage, voteId)
+
+	var criteria []model.VoteJuryCriterion
+	if rule.Scoring() == model.VoteJuryScoringRubric {
+		criteria, _ = vote_jury.Criteria(rule)
 	}
 
 	return event.JSON(http.StatusOK, map[string]any{
 		"status":          rule.Status(),
 		"currentRound":    rule.CurrentRound(),
+		"scoring":         rule.Scoring(),
+		"criteria":        criteria,
 		"results":         roundResults,
 		"members":         members,
 		"totalMembers":    len(juryUsers),
 		"isVoteCompleted": isVoteCompleted,
 		"finalWinner":     finalWinner,
+		"finalRanking":    finalRanking,
 		"isAdmin":         isAdmin,
 	})
 }
@@ -1693,20 +2601,12 @@ func (controller *VoteJuryController) GetCandidates(event *core.RequestEvent) er
 			userArticles[article.UserId()] = append(userArticles[article.UserId()], article)
 		}
 
-		for candidateUserId, arts := range userArticles {
-			user := new(model.User)
-			var userData map[string]any
-			if err := controller.app.RecordQuery(model.DbNameUsers).
-				Where(dbx.HashExp{model.CommonFieldId: candidateUserId}).
-				One(user); err == nil {
-				userData = map[string]any{
-					"id":       user.Id,
-					"name":     user.Name(),
-					"nickname": user.Nickname(),
-					"avatar":   user.Avatar(),
-				}
-			}
+		users := controller.Users(event)
+		for candidateUserId := range userArticles {
+			users.Add(candidateUserId)
+		}
 
+		for candidateUserId, arts := range userArticles {
 			// 格式化文章信息
 			articleList := make([]map[string]any, 0, len(arts))
 			for _, art := range arts {
@@ -1724,7 +2624,7 @@ func (controller *VoteJuryController) GetCandidates(event *core.RequestEvent) er
 
 			candidates = append(candidates, map[string]any{
 				"userId":   candidateUserId,
-				"user":     userData,
+				"user":     users.Brief(candidateUserId),
 				"articles": articleList,
 			})
 		}
@@ -1740,29 +2640,29 @@ func (controller *VoteJuryController) GetCandidates(event *core.RequestEvent) er
 			return event.InternalServerError("获取上一轮结果失败", err)
 		}
 
-		for _, candidateUserId := range lastResult.UserIds() {
-			user := new(model.User)
-			var userData map[string]any
-			if err := controller.app.RecordQuery(model.DbNameUsers).
-				Where(dbx.HashExp{model.CommonFieldId: candidateUserId}).
-				One(user); err == nil {
-				userData = map[string]any{
-					"id":       user.Id,
-					"name":     user.Name(),
-					"nickname": user.Nickname(),
-					"avatar":   user.Avatar(),
-				}
-			}
+		users := controller.Users(event).Add(lastResult.UserIds()...)
 
-			// 获取该用户的文章
+		// 一次查询所有候选人的文章
+		candidateIds := make([]any, 0, len(lastResult.UserIds()))
+		for _, candidateUserId := range lastResult.UserIds() {
+			candidateIds = append(candidateIds, candidateUserId)
+		}
+		userArticles := make(map[string][]*model.RelArticle)
+		if len(candidateIds) > 0 {
 			var articles []*model.RelArticle
-			controller.app.RecordQuery(model.DbNameRelArticles).
-				Where(dbx.HashExp{
-					model.RelArticlesFieldActivityId: activity.Id,
-					model.RelArticlesFieldUserId:     candidateUserId,
-				}).
-				All(&articles)
+			if err := controller.app.RecordQuery(model.DbNameRelArticles).
+				Where(dbx.HashExp{model.RelArticlesFieldActivityId: activity.Id}).
+				AndWhere(dbx.In(model.RelArticlesFieldUserId, candidateIds...)).
+				All(&articles); err != nil {
+				return event.InternalServerError("获取文章列表失败", err)
+			}
+			for _, article := range articles {
+				userArticles[article.UserId()] = append(userArticles[article.UserId()], article)
+			}
+		}
 
+		for _, candidateUserId := range lastResult.UserIds() {
+			articles := userArticles[candidateUserId]
 			articleList := make([]map[string]any, 0, len(articles))
 			for _, art := range articles {
 				articleList = append(articleList, map[string]any{
@@ -1779,7 +2679,7 @@ func (controller *VoteJuryController) GetCandidates(event *core.RequestEvent) er
 
 			candidates = append(candidates, map[string]any{
 				"userId":   candidateUserId,
-				"user":     userData,
+				"user":     users.Brief(candidateUserId),
 				"articles": articleList,
 			})
 		}
@@ -1854,59 +2754,74 @@ func (controller *VoteJuryController) GetVoteDetails(event *core.RequestEvent) e
 		votesByUser[log.FromUserId()] = append(votesByUser[log.FromUserId()], log)
 	}
 
+	// 秘密投票在本轮算票前只展示承诺与揭示进度，不展示选票内容
+	hidden := rule.SecretBallot() && rule.Status() == model.VoteJuryRuleStatusVoting
+	commitsByUser := make(map[string]*model.VoteJuryCommit)
+	if rule.SecretBallot() {
+		var commits []*model.VoteJuryCommit
+		if err := controller.app.RecordQuery(model.DbNameVoteJuryCommits).
+			Where(dbx.HashExp{
+				model.VoteJuryCommitFieldVoteId: voteId,
+				model.VoteJuryCommitFieldRound:  currentRound,
+			}).
+			All(&commits); err != nil {
+			return event.InternalServerError("获取投票承诺失败", err)
+		}
+		for _, commit := range commits {
+			commitsByUser[commit.FromUserId()] = commit
+		}
+	}
+
 	// 构建详细的投票信息
-	memberDetails := make([]map[string]any, 0, len(juryUsers))
+	users := controller.Users(event)
 	for _, juryUser := range juryUsers {
-		user := new(model.User)
-		var userData map[string]any
-		if err := controller.app.RecordQuery(model.DbNameUsers).
-			Where(dbx.HashExp{model.CommonFieldId: juryUser.UserId()}).
-			One(user); err == nil {
-			userData = map[string]any{
-				"id":       user.Id,
-				"name":     user.Name(),
-				"nickname": user.Nickname(),
-				"avatar":   user.Avatar(),
-			}
+		users.Add(juryUser.UserId())
+		for _, v := range votesByUser[juryUser.UserId()] {
+			users.Add(v.ToUserId())
 		}
+	}
+	memberDetails := make([]map[string]any, 0, len(juryUsers))
+	for _, juryUser := range juryUsers {
 
 		// 该成员的投票记录
 		userVotes := votesByUser[juryUser.UserId()]
 		voteRecords := make([]map[string]any, 0, len(userVotes))
 		for _, v := range userVotes {
-			toUser := new(model.User)
-			var toUserData map[string]any
-			if err := controller.app.RecordQuery(model.DbNameUsers).
-				Where(dbx.HashExp{model.CommonFieldId: v.ToUserId()}).
-				One(toUser); err == nil {
-				toUserData = map[string]any{
-					"id":       toUser.Id,
-					"name":     toUser.Name(),
-					"nickname": toUser.Nickname(),
-					"avatar":   toUser.Avatar(),
-				}
+			if hidden {
+				break
 			}
-
 			voteRecords = append(voteRecords, map[string]any{
 				"toUserId": v.ToUserId(),
-				"toUser":   toUserData,
+				"toUser":   users.Brief(v.ToUserId()),
 				"times":    v.Times(),
 				"comment":  v.Comment(),
 				"created":  v.GetDateTime("created").String(),
 			})
 		}
 
-		memberDetails = append(memberDetails, map[string]any{
+		commit, committed := commitsByUser[juryUser.UserId()]
+		detail := map[string]any{
 			"userId":    juryUser.UserId(),
-			"user":      userData,
+			"user":      users.Brief(juryUser.UserId()),
 			"hasVoted":  len(userVotes) > 0,
 			"voteCount": len(userVotes),
 			"votes":     voteRecords,
-		})
+		}
+		if rule.SecretBallot() {
+			detail["hasCommitted"] = committed
+			detail["hasRevealed"] = committed && commit.Revealed()
+			if hidden {
+				delete(detail, "voteCount")
+			}
+		}
+		memberDetails = append(memberDetails, detail)
 	}
 
 	return event.JSON(http.StatusOK, map[string]any{
 		"currentRound":  currentRound,
+		"secretBallot":  rule.SecretBallot(),
+		"revealing":     rule.Revealing(),
+		"hidden":        hidden,
 		"memberDetails": memberDetails,
 	})
 }
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"bless-activity/model"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// seedJury 创建一个已完成算票的评审团投票：jurors 名评审全部投票给 candidates 名候选人中的第一位
+// 返回投票ID与评审团管理员
+func seedJury(t *testing.T, server *testServer, jurors int, candidates int) (string, *core.Record) {
+	admin := server.createUser(t, "jury-admin", "")
+
+	vote := server.createRecord(t, model.DbNameVotes, map[string]any{
+		model.VotesFieldName:  "评审团投票",
+		model.VotesFieldType:  model.VoteTypeJury.String(),
+		model.VotesFieldTimes: 1,
+	})
+	server.createRecord(t, model.DbNameVoteJuryRules, map[string]any{
+		model.VoteJuryRuleFieldVoteId:       vote.Id,
+		model.VoteJuryRuleFieldCount:        jurors,
+		model.VoteJuryRuleFieldAdmins:       []string{admin.Id},
+		model.VoteJuryRuleFieldStatus:       model.VoteJuryRuleStatusCompleted.String(),
+		model.VoteJuryRuleFieldCurrentRound: 1,
+	})
+
+	candidateIds := make([]string, 0, candidates)
+	for i := range candidates {
+		candidateIds = append(candidateIds, server.createUser(t, fmt.Sprintf("candidate-%d", i), "").Id)
+	}
+
+	for i := range jurors {
+		juror := server.createUser(t, fmt.Sprintf("juror-%d", i), "")
+		server.createRecord(t, model.DbNameVoteJuryApplyLogs, map[string]any{
+			model.VoteJuryApplyLogFieldVoteId: vote.Id,
+			model.VoteJuryApplyLogFieldUserId: juror.Id,
+			model.VoteJuryApplyLogFieldStatus: model.VoteJuryApplyLogStatusApproved.String(),
+		})
+		server.createRecord(t, model.DbNameVoteJuryUsers, map[string]any{
+			model.VoteJuryUserFieldVoteId: vote.Id,
+			model.VoteJuryUserFieldUserId: juror.Id,
+			model.VoteJuryUserFieldStatus: model.VoteJuryUserStatusApproved.String(),
+		})
+		server.createRecord(t, model.DbNameVoteJuryLogs, map[string]any{
+			model.VoteJuryLogFieldVoteId:     vote.Id,
+			model.VoteJuryLogFieldFromUserId: juror.Id,
+			model.VoteJuryLogFieldToUserId:   candidateIds[0],
+			model.VoteJuryLogFieldTimes:      1,
+			model.VoteJuryLogFieldRound:      1,
+		})
+	}
+
+	results := make(map[string]float64, candidates)
+	for i, candidateId := range candidateIds {
+		results[candidateId] = 0
+		if i == 0 {
+			results[candidateId] = float64(jurors)
+		}
+	}
+	resultsJson, _ := json.Marshal(results)
+	rankingJson, _ := json.Marshal(model.VoteJuryRanking{
+		Winners: 1,
+		Settled: []model.VoteJuryRankEntry{{UserId: candidateIds[0], Rank: 1, Votes: float64(jurors), Round: 1}},
+	})
+	server.createRecord(t, model.DbNameVoteJuryResults, map[string]any{
+		model.VoteJuryResultFieldVoteId:   vote.Id,
+		model.VoteJuryResultFieldRound:    1,
+		model.VoteJuryResultFieldResults:  string(resultsJson),
+		model.VoteJuryResultFieldRanking:  string(rankingJson),
+		model.VoteJuryResultFieldContinue: false,
+		model.VoteJuryResultFieldUserIds:  candidateIds[:1],
+	})
+
+	return vote.Id, admin
+}
+
+func TestGetJuryInfoQueryCount(t *testing.T) {
+	queries := make(map[int]int64)
+	for _, size := range []int{2, 12} {
+		server := newTestServer(t)
+		voteId, admin := seedJury(t, server, size, size)
+
+		// 以评审团管理员身份访问，覆盖仅管理员可见的申请、冲突与提醒列表
+		queries[size] = server.countQueries(func() {
+			res := server.request(t, http.MethodGet, "/backend/vote/jury/info/"+voteId, "", admin)
+			if res.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", res.Code, res.Body.String())
+			}
+		})
+	}
+
+	// 成员、申请人、投票记录与候选人的用户信息批量加载，查询次数与人数无关
+	if queries[2] != queries[12] {
+		t.Errorf("query count grows with jury size: 2 members %d queries, 12 members %d queries", queries[2], queries[12])
+	}
+}
@@ -4,7 +4,11 @@ package model
 // CronKey 定时任务key
 /*
 ENUM(
-fetch_article // 爬取文章
+fetch_article   // 爬取文章
+jury_schedule   // 评审团状态调度
+jury_reminder   // 评审团催投提醒
+point_schedule  // 积分定时发放
+point_reconcile // 积分流水超时对账
 )
 */
 type CronKey string
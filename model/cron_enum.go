@@ -15,12 +15,28 @@ const (
 	// CronKeyFetchArticle is a CronKey of type fetch_article.
 	// 爬取文章
 	CronKeyFetchArticle CronKey = "fetch_article"
+	// CronKeyJurySchedule is a CronKey of type jury_schedule.
+	// 评审团状态调度
+	CronKeyJurySchedule CronKey = "jury_schedule"
+	// CronKeyJuryReminder is a CronKey of type jury_reminder.
+	// 评审团催投提醒
+	CronKeyJuryReminder CronKey = "jury_reminder"
+	// CronKeyPointSchedule is a CronKey of type point_schedule.
+	// 积分定时发放
+	CronKeyPointSchedule CronKey = "point_schedule"
+	// CronKeyPointReconcile is a CronKey of type point_reconcile.
+	// 积分流水超时对账
+	CronKeyPointReconcile CronKey = "point_reconcile"
 )
 
 var ErrInvalidCronKey = fmt.Errorf("not a valid CronKey, try [%s]", strings.Join(_CronKeyNames, ", "))
 
 var _CronKeyNames = []string{
 	string(CronKeyFetchArticle),
+	string(CronKeyJurySchedule),
+	string(CronKeyJuryReminder),
+	string(CronKeyPointSchedule),
+	string(CronKeyPointReconcile),
 }
 
 // CronKeyNames returns a list of possible string values of CronKey.
@@ -34,6 +50,10 @@ func CronKeyNames() []string {
 func CronKeyValues() []CronKey {
 	return []CronKey{
 		CronKeyFetchArticle,
+		CronKeyJurySchedule,
+		CronKeyJuryReminder,
+		CronKeyPointSchedule,
+		CronKeyPointReconcile,
 	}
 }
 
@@ -50,7 +70,11 @@ func (x CronKey) IsValid() bool {
 }
 
 var _CronKeyValue = map[string]CronKey{
-	"fetch_article": CronKeyFetchArticle,
+	"fetch_article":   CronKeyFetchArticle,
+	"jury_schedule":   CronKeyJurySchedule,
+	"jury_reminder":   CronKeyJuryReminder,
+	"point_schedule":  CronKeyPointSchedule,
+	"point_reconcile": CronKeyPointReconcile,
 }
 
 // ParseCronKey attempts to convert a string to a CronKey.
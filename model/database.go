@@ -16,6 +16,7 @@ var (
 	_ core.RecordProxy = (*Shield)(nil)
 	_ core.RecordProxy = (*Vote)(nil)
 	_ core.RecordProxy = (*VoteLog)(nil)
+	_ core.RecordProxy = (*VoteChain)(nil)
 	_ core.RecordProxy = (*YearlyHistory)(nil)
 	_ core.RecordProxy = (*RewardGroup)(nil)
 	_ core.RecordProxy = (*Reward)(nil)
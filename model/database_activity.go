@@ -25,6 +25,7 @@ const (
 	ActivitiesFieldImage                    = "image"                    // 活动图片
 	ActivitiesFieldImages                   = "images"                   // 活动图片(多张)
 	ActivitiesFieldMetadata                 = "metadata"                 // 元数据(JSON)
+	ActivitiesFieldPointBudget              = "pointBudget"              // 积分预算上限，0表示不限制
 	ActivitiesFieldCreated                  = "created"                  // 创建时间
 	ActivitiesFieldUpdated                  = "updated"                  // 更新时间
 )
@@ -200,6 +201,14 @@ func (activity *Activity) SetImage(value string) {
 	activity.Set(ActivitiesFieldImage, value)
 }
 
+func (activity *Activity) GetPointBudget() int {
+	return activity.GetInt(ActivitiesFieldPointBudget)
+}
+
+func (activity *Activity) SetPointBudget(value int) {
+	activity.Set(ActivitiesFieldPointBudget, value)
+}
+
 func (activity *Activity) GetCreated() types.DateTime {
 	return activity.GetDateTime(ActivitiesFieldCreated)
 }
@@ -0,0 +1,173 @@
+//go:generate go-enum --marshal --names --values --ptr --mustparse
+package model
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	DbNamePayoutApprovals           = "payoutApprovals" // 大额发放审批表
+	PayoutApprovalsFieldKind        = "kind"            // 发放类型
+	PayoutApprovalsFieldActivityId  = "activityId"      // 关联活动ID
+	PayoutApprovalsFieldPlanId      = "planId"          // 奖励发放计划ID
+	PayoutApprovalsFieldItems       = "items"           // 冻结的发放清单 JSON
+	PayoutApprovalsFieldTotalPoint  = "totalPoint"      // 积分总额
+	PayoutApprovalsFieldRecipients  = "recipients"      // 发放人数
+	PayoutApprovalsFieldStatus      = "status"          // 审批状态
+	PayoutApprovalsFieldRequesterId = "requesterId"     // 发起发放的管理员ID
+	PayoutApprovalsFieldApproverId  = "approverId"      // 审批的管理员ID
+	PayoutApprovalsFieldNote        = "note"            // 审批备注
+	PayoutApprovalsFieldApprovedAt  = "approvedAt"      // 审批时间
+	PayoutApprovalsFieldExecutedAt  = "executedAt"      // 执行时间
+	PayoutApprovalsFieldResult      = "result"          // 执行结果 JSON
+	PayoutApprovalsFieldCreated     = "created"         // 创建时间
+	PayoutApprovalsFieldUpdated     = "updated"         // 更新时间
+)
+
+// PayoutKind 需要审批的发放类型
+/*
+ENUM(
+reward // 活动奖励发放
+point  // 积分管理批量发放
+)
+*/
+type PayoutKind string
+
+// PayoutApprovalStatus 大额发放审批状态
+/*
+ENUM(
+pending  // 待审批
+rejected // 已驳回
+executed // 已审批并执行
+)
+*/
+type PayoutApprovalStatus string
+
+// PayoutApproval wrapper type
+type PayoutApproval struct {
+	core.BaseRecordProxy
+}
+
+func NewPayoutApproval(record *core.Record) *PayoutApproval {
+	approval := new(PayoutApproval)
+	approval.SetProxyRecord(record)
+	return approval
+}
+
+func NewPayoutApprovalFromCollection(collection *core.Collection) *PayoutApproval {
+	record := core.NewRecord(collection)
+	return NewPayoutApproval(record)
+}
+
+func (approval *PayoutApproval) Kind() PayoutKind {
+	return MustParsePayoutKind(approval.GetString(PayoutApprovalsFieldKind))
+}
+
+func (approval *PayoutApproval) SetKind(value PayoutKind) {
+	approval.Set(PayoutApprovalsFieldKind, value)
+}
+
+func (approval *PayoutApproval) ActivityId() string {
+	return approval.GetString(PayoutApprovalsFieldActivityId)
+}
+
+func (approval *PayoutApproval) SetActivityId(value string) {
+	approval.Set(PayoutApprovalsFieldActivityId, value)
+}
+
+func (approval *PayoutApproval) PlanId() string {
+	return approval.GetString(PayoutApprovalsFieldPlanId)
+}
+
+func (approval *PayoutApproval) SetPlanId(value string) {
+	approval.Set(PayoutApprovalsFieldPlanId, value)
+}
+
+func (approval *PayoutApproval) Items() string {
+	return approval.GetString(PayoutApprovalsFieldItems)
+}
+
+func (approval *PayoutApproval) SetItems(value string) {
+	approval.Set(PayoutApprovalsFieldItems, value)
+}
+
+func (approval *PayoutApproval) TotalPoint() int {
+	return approval.GetInt(PayoutApprovalsFieldTotalPoint)
+}
+
+func (approval *PayoutApproval) SetTotalPoint(value int) {
+	approval.Set(PayoutApprovalsFieldTotalPoint, value)
+}
+
+func (approval *PayoutApproval) Recipients() int {
+	return approval.GetInt(PayoutApprovalsFieldRecipients)
+}
+
+func (approval *PayoutApproval) SetRecipients(value int) {
+	approval.Set(PayoutApprovalsFieldRecipients, value)
+}
+
+func (approval *PayoutApproval) Status() PayoutApprovalStatus {
+	return MustParsePayoutApprovalStatus(approval.GetString(PayoutApprovalsFieldStatus))
+}
+
+func (approval *PayoutApproval) SetStatus(value PayoutApprovalStatus) {
+	approval.Set(PayoutApprovalsFieldStatus, value)
+}
+
+func (approval *PayoutApproval) RequesterId() string {
+	return approval.GetString(PayoutApprovalsFieldRequesterId)
+}
+
+func (approval *PayoutApproval) SetRequesterId(value string) {
+	approval.Set(PayoutApprovalsFieldRequesterId, value)
+}
+
+func (approval *PayoutApproval) ApproverId() string {
+	return approval.GetString(PayoutApprovalsFieldApproverId)
+}
+
+func (approval *PayoutApproval) SetApproverId(value string) {
+	approval.Set(PayoutApprovalsFieldApproverId, value)
+}
+
+func (approval *PayoutApproval) Note() string {
+	return approval.GetString(PayoutApprovalsFieldNote)
+}
+
+func (approval *PayoutApproval) SetNote(value string) {
+	approval.Set(PayoutApprovalsFieldNote, value)
+}
+
+func (approval *PayoutApproval) ApprovedAt() types.DateTime {
+	return approval.GetDateTime(PayoutApprovalsFieldApprovedAt)
+}
+
+func (approval *PayoutApproval) SetApprovedAt(value types.DateTime) {
+	approval.Set(PayoutApprovalsFieldApprovedAt, value)
+}
+
+func (approval *PayoutApproval) ExecutedAt() types.DateTime {
+	return approval.GetDateTime(PayoutApprovalsFieldExecutedAt)
+}
+
+func (approval *PayoutApproval) SetExecutedAt(value types.DateTime) {
+	approval.Set(PayoutApprovalsFieldExecutedAt, value)
+}
+
+func (approval *PayoutApproval) Result() string {
+	return approval.GetString(PayoutApprovalsFieldResult)
+}
+
+func (approval *PayoutApproval) SetResult(value string) {
+	approval.Set(PayoutApprovalsFieldResult, value)
+}
+
+func (approval *PayoutApproval) Created() types.DateTime {
+	return approval.GetDateTime(PayoutApprovalsFieldCreated)
+}
+
+func (approval *PayoutApproval) Updated() types.DateTime {
+	return approval.GetDateTime(PayoutApprovalsFieldUpdated)
+}
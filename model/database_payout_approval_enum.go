@@ -0,0 +1,186 @@
+// Code generated by go-enum DO NOT EDIT.
+// Version:
+// Revision:
+// Build Date:
+// Built By:
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// PayoutApprovalStatusPending is a PayoutApprovalStatus of type pending.
+	// 待审批
+	PayoutApprovalStatusPending PayoutApprovalStatus = "pending"
+	// PayoutApprovalStatusRejected is a PayoutApprovalStatus of type rejected.
+	// 已驳回
+	PayoutApprovalStatusRejected PayoutApprovalStatus = "rejected"
+	// PayoutApprovalStatusExecuted is a PayoutApprovalStatus of type executed.
+	// 已审批并执行
+	PayoutApprovalStatusExecuted PayoutApprovalStatus = "executed"
+)
+
+var ErrInvalidPayoutApprovalStatus = fmt.Errorf("not a valid PayoutApprovalStatus, try [%s]", strings.Join(_PayoutApprovalStatusNames, ", "))
+
+var _PayoutApprovalStatusNames = []string{
+	string(PayoutApprovalStatusPending),
+	string(PayoutApprovalStatusRejected),
+	string(PayoutApprovalStatusExecuted),
+}
+
+// PayoutApprovalStatusNames returns a list of possible string values of PayoutApprovalStatus.
+func PayoutApprovalStatusNames() []string {
+	tmp := make([]string, len(_PayoutApprovalStatusNames))
+	copy(tmp, _PayoutApprovalStatusNames)
+	return tmp
+}
+
+// PayoutApprovalStatusValues returns a list of the values for PayoutApprovalStatus
+func PayoutApprovalStatusValues() []PayoutApprovalStatus {
+	return []PayoutApprovalStatus{
+		PayoutApprovalStatusPending,
+		PayoutApprovalStatusRejected,
+		PayoutApprovalStatusExecuted,
+	}
+}
+
+// String implements the Stringer interface.
+func (x PayoutApprovalStatus) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x PayoutApprovalStatus) IsValid() bool {
+	_, err := ParsePayoutApprovalStatus(string(x))
+	return err == nil
+}
+
+var _PayoutApprovalStatusValue = map[string]PayoutApprovalStatus{
+	"pending":  PayoutApprovalStatusPending,
+	"rejected": PayoutApprovalStatusRejected,
+	"executed": PayoutApprovalStatusExecuted,
+}
+
+// ParsePayoutApprovalStatus attempts to convert a string to a PayoutApprovalStatus.
+func ParsePayoutApprovalStatus(name string) (PayoutApprovalStatus, error) {
+	if x, ok := _PayoutApprovalStatusValue[name]; ok {
+		return x, nil
+	}
+	return PayoutApprovalStatus(""), fmt.Errorf("%s is %w", name, ErrInvalidPayoutApprovalStatus)
+}
+
+// MustParsePayoutApprovalStatus converts a string to a PayoutApprovalStatus, and panics if is not valid.
+func MustParsePayoutApprovalStatus(name string) PayoutApprovalStatus {
+	val, err := ParsePayoutApprovalStatus(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x PayoutApprovalStatus) Ptr() *PayoutApprovalStatus {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x PayoutApprovalStatus) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *PayoutApprovalStatus) UnmarshalText(text []byte) error {
+	tmp, err := ParsePayoutApprovalStatus(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+const (
+	// PayoutKindReward is a PayoutKind of type reward.
+	// 活动奖励发放
+	PayoutKindReward PayoutKind = "reward"
+	// PayoutKindPoint is a PayoutKind of type point.
+	// 积分管理批量发放
+	PayoutKindPoint PayoutKind = "point"
+)
+
+var ErrInvalidPayoutKind = fmt.Errorf("not a valid PayoutKind, try [%s]", strings.Join(_PayoutKindNames, ", "))
+
+var _PayoutKindNames = []string{
+	string(PayoutKindReward),
+	string(PayoutKindPoint),
+}
+
+// PayoutKindNames returns a list of possible string values of PayoutKind.
+func PayoutKindNames() []string {
+	tmp := make([]string, len(_PayoutKindNames))
+	copy(tmp, _PayoutKindNames)
+	return tmp
+}
+
+// PayoutKindValues returns a list of the values for PayoutKind
+func PayoutKindValues() []PayoutKind {
+	return []PayoutKind{
+		PayoutKindReward,
+		PayoutKindPoint,
+	}
+}
+
+// String implements the Stringer interface.
+func (x PayoutKind) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x PayoutKind) IsValid() bool {
+	_, err := ParsePayoutKind(string(x))
+	return err == nil
+}
+
+var _PayoutKindValue = map[string]PayoutKind{
+	"reward": PayoutKindReward,
+	"point":  PayoutKindPoint,
+}
+
+// ParsePayoutKind attempts to convert a string to a PayoutKind.
+func ParsePayoutKind(name string) (PayoutKind, error) {
+	if x, ok := _PayoutKindValue[name]; ok {
+		return x, nil
+	}
+	return PayoutKind(""), fmt.Errorf("%s is %w", name, ErrInvalidPayoutKind)
+}
+
+// MustParsePayoutKind converts a string to a PayoutKind, and panics if is not valid.
+func MustParsePayoutKind(name string) PayoutKind {
+	val, err := ParsePayoutKind(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x PayoutKind) Ptr() *PayoutKind {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x PayoutKind) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *PayoutKind) UnmarshalText(text []byte) error {
+	tmp, err := ParsePayoutKind(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
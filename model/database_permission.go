@@ -0,0 +1,75 @@
+//go:generate go-enum --marshal --names --values --ptr --mustparse
+package model
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	DbNamePermissions          = "permissions" // 权限授予表，activityId 为空表示全局授权
+	PermissionsFieldUserId     = "userId"      // 被授权用户ID
+	PermissionsFieldPermission = "permission"  // 权限名称
+	PermissionsFieldActivityId = "activityId"  // 授权范围，关联活动ID，为空时对所有活动生效
+	PermissionsFieldGrantedBy  = "grantedBy"   // 授权人ID
+	PermissionsFieldCreated    = "created"     // 创建时间
+)
+
+// Permission 权限名称
+/*
+ENUM(
+reward_distribute = "reward.distribute" // 发放活动奖励
+point_distribute  = "point.distribute"  // 发放积分
+medal_grant       = "medal.grant"       // 管理及授予勋章
+jury_manage       = "jury.manage"       // 管理评审团
+vote_manage       = "vote.manage"       // 管理投票结果
+)
+*/
+type Permission string
+
+// PermissionGrant wrapper type
+type PermissionGrant struct {
+	core.BaseRecordProxy
+}
+
+func NewPermissionGrant(record *core.Record) *PermissionGrant {
+	grant := new(PermissionGrant)
+	grant.SetProxyRecord(record)
+	return grant
+}
+
+func NewPermissionGrantFromCollection(collection *core.Collection) *PermissionGrant {
+	record := core.NewRecord(collection)
+	return NewPermissionGrant(record)
+}
+
+func (grant *PermissionGrant) UserId() string {
+	return grant.GetString(PermissionsFieldUserId)
+}
+
+func (grant *PermissionGrant) SetUserId(value string) {
+	grant.Set(PermissionsFieldUserId, value)
+}
+
+func (grant *PermissionGrant) Permission() Permission {
+	return Permission(grant.GetString(PermissionsFieldPermission))
+}
+
+func (grant *PermissionGrant) SetPermission(value Permission) {
+	grant.Set(PermissionsFieldPermission, string(value))
+}
+
+func (grant *PermissionGrant) ActivityId() string {
+	return grant.GetString(PermissionsFieldActivityId)
+}
+
+func (grant *PermissionGrant) SetActivityId(value string) {
+	grant.Set(PermissionsFieldActivityId, value)
+}
+
+func (grant *PermissionGrant) GrantedBy() string {
+	return grant.GetString(PermissionsFieldGrantedBy)
+}
+
+func (grant *PermissionGrant) SetGrantedBy(value string) {
+	grant.Set(PermissionsFieldGrantedBy, value)
+}
@@ -0,0 +1,114 @@
+// Code generated by go-enum DO NOT EDIT.
+// Version:
+// Revision:
+// Build Date:
+// Built By:
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// PermissionRewardDistribute is a Permission of type reward_distribute.
+	// 发放活动奖励
+	PermissionRewardDistribute Permission = "reward.distribute"
+	// PermissionPointDistribute is a Permission of type point_distribute.
+	// 发放积分
+	PermissionPointDistribute Permission = "point.distribute"
+	// PermissionMedalGrant is a Permission of type medal_grant.
+	// 管理及授予勋章
+	PermissionMedalGrant Permission = "medal.grant"
+	// PermissionJuryManage is a Permission of type jury_manage.
+	// 管理评审团
+	PermissionJuryManage Permission = "jury.manage"
+	// PermissionVoteManage is a Permission of type vote_manage.
+	// 管理投票结果
+	PermissionVoteManage Permission = "vote.manage"
+)
+
+var ErrInvalidPermission = fmt.Errorf("not a valid Permission, try [%s]", strings.Join(_PermissionNames, ", "))
+
+var _PermissionNames = []string{
+	string(PermissionRewardDistribute),
+	string(PermissionPointDistribute),
+	string(PermissionMedalGrant),
+	string(PermissionJuryManage),
+	string(PermissionVoteManage),
+}
+
+// PermissionNames returns a list of possible string values of Permission.
+func PermissionNames() []string {
+	tmp := make([]string, len(_PermissionNames))
+	copy(tmp, _PermissionNames)
+	return tmp
+}
+
+// PermissionValues returns a list of the values for Permission
+func PermissionValues() []Permission {
+	return []Permission{
+		PermissionRewardDistribute,
+		PermissionPointDistribute,
+		PermissionMedalGrant,
+		PermissionJuryManage,
+		PermissionVoteManage,
+	}
+}
+
+// String implements the Stringer interface.
+func (x Permission) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x Permission) IsValid() bool {
+	_, err := ParsePermission(string(x))
+	return err == nil
+}
+
+var _PermissionValue = map[string]Permission{
+	"reward.distribute": PermissionRewardDistribute,
+	"point.distribute":  PermissionPointDistribute,
+	"medal.grant":       PermissionMedalGrant,
+	"jury.manage":       PermissionJuryManage,
+	"vote.manage":       PermissionVoteManage,
+}
+
+// ParsePermission attempts to convert a string to a Permission.
+func ParsePermission(name string) (Permission, error) {
+	if x, ok := _PermissionValue[name]; ok {
+		return x, nil
+	}
+	return Permission(""), fmt.Errorf("%s is %w", name, ErrInvalidPermission)
+}
+
+// MustParsePermission converts a string to a Permission, and panics if is not valid.
+func MustParsePermission(name string) Permission {
+	val, err := ParsePermission(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x Permission) Ptr() *Permission {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x Permission) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *Permission) UnmarshalText(text []byte) error {
+	tmp, err := ParsePermission(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
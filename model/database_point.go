@@ -2,19 +2,26 @@
 package model
 
 import (
+	"time"
+
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 const (
-	DbNamePoints       = "points"  // 积分操作表
-	PointsFieldGroup   = "group"   // 分组标识
-	PointsFieldUserId  = "userId"  // 用户ID (关联users表)
-	PointsFieldPoint   = "point"   // 积分数量
-	PointsFieldStatus  = "status"  // 状态
-	PointsFieldMemo    = "memo"    // 备注
-	PointsFieldCreated = "created" // 创建时间
-	PointsFieldUpdated = "updated" // 更新时间
+	DbNamePoints               = "points"          // 积分操作表
+	PointsFieldGroup           = "group"           // 分组标识
+	PointsFieldActivityId      = "activityId"      // 关联活动ID，计入活动积分预算
+	PointsFieldUserId          = "userId"          // 用户ID (关联users表)
+	PointsFieldPoint           = "point"           // 积分数量
+	PointsFieldStatus          = "status"          // 状态
+	PointsFieldMemo            = "memo"            // 备注
+	PointsFieldScheduledAt     = "scheduledAt"     // 定时发放时间，为空时需手动发放
+	PointsFieldRecurrence      = "recurrence"      // 重复周期
+	PointsFieldRecurrenceUntil = "recurrenceUntil" // 重复截止时间，为空时一直重复
+	PointsFieldScheduledBy     = "scheduledBy"     // 设置定时发放的管理员ID，定时执行时计入其累计发放额度
+	PointsFieldCreated         = "created"         // 创建时间
+	PointsFieldUpdated         = "updated"         // 更新时间
 )
 
 // PointStatus 积分发放状态
@@ -28,6 +35,31 @@ failed       // 发放失败
 */
 type PointStatus string
 
+// PointRecurrence 定时发放的重复周期
+/*
+ENUM(
+none    // 不重复
+daily   // 每天
+weekly  // 每周
+monthly // 每月
+)
+*/
+type PointRecurrence string
+
+// Next 下一次发放时间
+func (x PointRecurrence) Next(t time.Time) time.Time {
+	switch x {
+	case PointRecurrenceDaily:
+		return t.AddDate(0, 0, 1)
+	case PointRecurrenceWeekly:
+		return t.AddDate(0, 0, 7)
+	case PointRecurrenceMonthly:
+		return t.AddDate(0, 1, 0)
+	default:
+		return time.Time{}
+	}
+}
+
 type Point struct {
 	core.BaseRecordProxy
 }
@@ -51,6 +83,14 @@ func (p *Point) SetGroup(value string) {
 	p.Set(PointsFieldGroup, value)
 }
 
+func (p *Point) ActivityId() string {
+	return p.GetString(PointsFieldActivityId)
+}
+
+func (p *Point) SetActivityId(value string) {
+	p.Set(PointsFieldActivityId, value)
+}
+
 func (p *Point) UserId() string {
 	return p.GetString(PointsFieldUserId)
 }
@@ -83,6 +123,41 @@ func (p *Point) SetMemo(value string) {
 	p.Set(PointsFieldMemo, value)
 }
 
+func (p *Point) ScheduledAt() types.DateTime {
+	return p.GetDateTime(PointsFieldScheduledAt)
+}
+
+func (p *Point) SetScheduledAt(value types.DateTime) {
+	p.Set(PointsFieldScheduledAt, value)
+}
+
+func (p *Point) Recurrence() PointRecurrence {
+	if value := p.GetString(PointsFieldRecurrence); value != "" {
+		return PointRecurrence(value)
+	}
+	return PointRecurrenceNone
+}
+
+func (p *Point) SetRecurrence(value PointRecurrence) {
+	p.Set(PointsFieldRecurrence, string(value))
+}
+
+func (p *Point) RecurrenceUntil() types.DateTime {
+	return p.GetDateTime(PointsFieldRecurrenceUntil)
+}
+
+func (p *Point) SetRecurrenceUntil(value types.DateTime) {
+	p.Set(PointsFieldRecurrenceUntil, value)
+}
+
+func (p *Point) ScheduledBy() string {
+	return p.GetString(PointsFieldScheduledBy)
+}
+
+func (p *Point) SetScheduledBy(value string) {
+	p.Set(PointsFieldScheduledBy, value)
+}
+
 func (p *Point) Created() types.DateTime {
 	return p.GetDateTime(PointsFieldCreated)
 }
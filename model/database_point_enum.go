@@ -11,6 +11,102 @@ import (
 	"strings"
 )
 
+const (
+	// PointRecurrenceNone is a PointRecurrence of type none.
+	// 不重复
+	PointRecurrenceNone PointRecurrence = "none"
+	// PointRecurrenceDaily is a PointRecurrence of type daily.
+	// 每天
+	PointRecurrenceDaily PointRecurrence = "daily"
+	// PointRecurrenceWeekly is a PointRecurrence of type weekly.
+	// 每周
+	PointRecurrenceWeekly PointRecurrence = "weekly"
+	// PointRecurrenceMonthly is a PointRecurrence of type monthly.
+	// 每月
+	PointRecurrenceMonthly PointRecurrence = "monthly"
+)
+
+var ErrInvalidPointRecurrence = fmt.Errorf("not a valid PointRecurrence, try [%s]", strings.Join(_PointRecurrenceNames, ", "))
+
+var _PointRecurrenceNames = []string{
+	string(PointRecurrenceNone),
+	string(PointRecurrenceDaily),
+	string(PointRecurrenceWeekly),
+	string(PointRecurrenceMonthly),
+}
+
+// PointRecurrenceNames returns a list of possible string values of PointRecurrence.
+func PointRecurrenceNames() []string {
+	tmp := make([]string, len(_PointRecurrenceNames))
+	copy(tmp, _PointRecurrenceNames)
+	return tmp
+}
+
+// PointRecurrenceValues returns a list of the values for PointRecurrence
+func PointRecurrenceValues() []PointRecurrence {
+	return []PointRecurrence{
+		PointRecurrenceNone,
+		PointRecurrenceDaily,
+		PointRecurrenceWeekly,
+		PointRecurrenceMonthly,
+	}
+}
+
+// String implements the Stringer interface.
+func (x PointRecurrence) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x PointRecurrence) IsValid() bool {
+	_, err := ParsePointRecurrence(string(x))
+	return err == nil
+}
+
+var _PointRecurrenceValue = map[string]PointRecurrence{
+	"none":    PointRecurrenceNone,
+	"daily":   PointRecurrenceDaily,
+	"weekly":  PointRecurrenceWeekly,
+	"monthly": PointRecurrenceMonthly,
+}
+
+// ParsePointRecurrence attempts to convert a string to a PointRecurrence.
+func ParsePointRecurrence(name string) (PointRecurrence, error) {
+	if x, ok := _PointRecurrenceValue[name]; ok {
+		return x, nil
+	}
+	return PointRecurrence(""), fmt.Errorf("%s is %w", name, ErrInvalidPointRecurrence)
+}
+
+// MustParsePointRecurrence converts a string to a PointRecurrence, and panics if is not valid.
+func MustParsePointRecurrence(name string) PointRecurrence {
+	val, err := ParsePointRecurrence(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x PointRecurrence) Ptr() *PointRecurrence {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x PointRecurrence) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *PointRecurrence) UnmarshalText(text []byte) error {
+	tmp, err := ParsePointRecurrence(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
 const (
 	// PointStatusPending is a PointStatus of type pending.
 	// 待发放
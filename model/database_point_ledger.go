@@ -0,0 +1,151 @@
+//go:generate go-enum --marshal --names --values --ptr --mustparse
+package model
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	DbNamePointLedgers             = "pointLedgers"  // 积分流水表，记录每一次向摸鱼派发出的积分变动
+	PointLedgersFieldActivityId    = "activityId"    // 关联活动ID
+	PointLedgersFieldGroup         = "group"         // 积分分组标识
+	PointLedgersFieldUserId        = "userId"        // 用户ID
+	PointLedgersFieldPoint         = "point"         // 积分数量
+	PointLedgersFieldMemo          = "memo"          // 发送给摸鱼派的备注
+	PointLedgersFieldTransactionNo = "transactionNo" // 交易单号，与备注中的交易单号一致
+	PointLedgersFieldSource        = "source"        // 积分来源
+	PointLedgersFieldSourceId      = "sourceId"      // 来源记录ID
+	PointLedgersFieldStatus        = "status"        // 发放结果
+	PointLedgersFieldError         = "error"         // 失败原因
+	PointLedgersFieldOperatorId    = "operatorId"    // 发起发放的管理员用户ID，审批通过后执行的发放为空
+	PointLedgersFieldCreated       = "created"       // 创建时间
+)
+
+// PointLedgerSource 积分来源
+/*
+ENUM(
+reward // 活动奖励发放
+point  // 积分管理发放
+)
+*/
+type PointLedgerSource string
+
+// PointLedgerStatus 积分流水结果
+/*
+ENUM(
+success   // 发放成功
+failed    // 发放失败
+pending   // 已预留预算，等待摸鱼派返回结果
+unsettled // 超时仍未结算，是否到账需人工核对，继续占用预算
+)
+*/
+type PointLedgerStatus string
+
+// PointLedger wrapper type
+type PointLedger struct {
+	core.BaseRecordProxy
+}
+
+func NewPointLedger(record *core.Record) *PointLedger {
+	ledger := new(PointLedger)
+	ledger.SetProxyRecord(record)
+	return ledger
+}
+
+func NewPointLedgerFromCollection(collection *core.Collection) *PointLedger {
+	record := core.NewRecord(collection)
+	return NewPointLedger(record)
+}
+
+func (ledger *PointLedger) ActivityId() string {
+	return ledger.GetString(PointLedgersFieldActivityId)
+}
+
+func (ledger *PointLedger) SetActivityId(value string) {
+	ledger.Set(PointLedgersFieldActivityId, value)
+}
+
+func (ledger *PointLedger) Group() string {
+	return ledger.GetString(PointLedgersFieldGroup)
+}
+
+func (ledger *PointLedger) SetGroup(value string) {
+	ledger.Set(PointLedgersFieldGroup, value)
+}
+
+func (ledger *PointLedger) UserId() string {
+	return ledger.GetString(PointLedgersFieldUserId)
+}
+
+func (ledger *PointLedger) SetUserId(value string) {
+	ledger.Set(PointLedgersFieldUserId, value)
+}
+
+func (ledger *PointLedger) Point() int {
+	return ledger.GetInt(PointLedgersFieldPoint)
+}
+
+func (ledger *PointLedger) SetPoint(value int) {
+	ledger.Set(PointLedgersFieldPoint, value)
+}
+
+func (ledger *PointLedger) Memo() string {
+	return ledger.GetString(PointLedgersFieldMemo)
+}
+
+func (ledger *PointLedger) SetMemo(value string) {
+	ledger.Set(PointLedgersFieldMemo, value)
+}
+
+func (ledger *PointLedger) TransactionNo() string {
+	return ledger.GetString(PointLedgersFieldTransactionNo)
+}
+
+func (ledger *PointLedger) SetTransactionNo(value string) {
+	ledger.Set(PointLedgersFieldTransactionNo, value)
+}
+
+func (ledger *PointLedger) Source() PointLedgerSource {
+	return MustParsePointLedgerSource(ledger.GetString(PointLedgersFieldSource))
+}
+
+func (ledger *PointLedger) SetSource(value PointLedgerSource) {
+	ledger.Set(PointLedgersFieldSource, value)
+}
+
+func (ledger *PointLedger) SourceId() string {
+	return ledger.GetString(PointLedgersFieldSourceId)
+}
+
+func (ledger *PointLedger) SetSourceId(value string) {
+	ledger.Set(PointLedgersFieldSourceId, value)
+}
+
+func (ledger *PointLedger) Status() PointLedgerStatus {
+	return MustParsePointLedgerStatus(ledger.GetString(PointLedgersFieldStatus))
+}
+
+func (ledger *PointLedger) SetStatus(value PointLedgerStatus) {
+	ledger.Set(PointLedgersFieldStatus, value)
+}
+
+func (ledger *PointLedger) Error() string {
+	return ledger.GetString(PointLedgersFieldError)
+}
+
+func (ledger *PointLedger) SetError(value string) {
+	ledger.Set(PointLedgersFieldError, value)
+}
+
+func (ledger *PointLedger) OperatorId() string {
+	return ledger.GetString(PointLedgersFieldOperatorId)
+}
+
+func (ledger *PointLedger) SetOperatorId(value string) {
+	ledger.Set(PointLedgersFieldOperatorId, value)
+}
+
+func (ledger *PointLedger) Created() types.DateTime {
+	return ledger.GetDateTime(PointLedgersFieldCreated)
+}
@@ -0,0 +1,192 @@
+// Code generated by go-enum DO NOT EDIT.
+// Version:
+// Revision:
+// Build Date:
+// Built By:
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// PointLedgerSourceReward is a PointLedgerSource of type reward.
+	// 活动奖励发放
+	PointLedgerSourceReward PointLedgerSource = "reward"
+	// PointLedgerSourcePoint is a PointLedgerSource of type point.
+	// 积分管理发放
+	PointLedgerSourcePoint PointLedgerSource = "point"
+)
+
+var ErrInvalidPointLedgerSource = fmt.Errorf("not a valid PointLedgerSource, try [%s]", strings.Join(_PointLedgerSourceNames, ", "))
+
+var _PointLedgerSourceNames = []string{
+	string(PointLedgerSourceReward),
+	string(PointLedgerSourcePoint),
+}
+
+// PointLedgerSourceNames returns a list of possible string values of PointLedgerSource.
+func PointLedgerSourceNames() []string {
+	tmp := make([]string, len(_PointLedgerSourceNames))
+	copy(tmp, _PointLedgerSourceNames)
+	return tmp
+}
+
+// PointLedgerSourceValues returns a list of the values for PointLedgerSource
+func PointLedgerSourceValues() []PointLedgerSource {
+	return []PointLedgerSource{
+		PointLedgerSourceReward,
+		PointLedgerSourcePoint,
+	}
+}
+
+// String implements the Stringer interface.
+func (x PointLedgerSource) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x PointLedgerSource) IsValid() bool {
+	_, err := ParsePointLedgerSource(string(x))
+	return err == nil
+}
+
+var _PointLedgerSourceValue = map[string]PointLedgerSource{
+	"reward": PointLedgerSourceReward,
+	"point":  PointLedgerSourcePoint,
+}
+
+// ParsePointLedgerSource attempts to convert a string to a PointLedgerSource.
+func ParsePointLedgerSource(name string) (PointLedgerSource, error) {
+	if x, ok := _PointLedgerSourceValue[name]; ok {
+		return x, nil
+	}
+	return PointLedgerSource(""), fmt.Errorf("%s is %w", name, ErrInvalidPointLedgerSource)
+}
+
+// MustParsePointLedgerSource converts a string to a PointLedgerSource, and panics if is not valid.
+func MustParsePointLedgerSource(name string) PointLedgerSource {
+	val, err := ParsePointLedgerSource(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x PointLedgerSource) Ptr() *PointLedgerSource {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x PointLedgerSource) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *PointLedgerSource) UnmarshalText(text []byte) error {
+	tmp, err := ParsePointLedgerSource(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+const (
+	// PointLedgerStatusSuccess is a PointLedgerStatus of type success.
+	// 发放成功
+	PointLedgerStatusSuccess PointLedgerStatus = "success"
+	// PointLedgerStatusFailed is a PointLedgerStatus of type failed.
+	// 发放失败
+	PointLedgerStatusFailed PointLedgerStatus = "failed"
+	// PointLedgerStatusPending is a PointLedgerStatus of type pending.
+	// 已预留预算，等待摸鱼派返回结果
+	PointLedgerStatusPending PointLedgerStatus = "pending"
+	// PointLedgerStatusUnsettled is a PointLedgerStatus of type unsettled.
+	// 超时仍未结算，是否到账需人工核对，继续占用预算
+	PointLedgerStatusUnsettled PointLedgerStatus = "unsettled"
+)
+
+var ErrInvalidPointLedgerStatus = fmt.Errorf("not a valid PointLedgerStatus, try [%s]", strings.Join(_PointLedgerStatusNames, ", "))
+
+var _PointLedgerStatusNames = []string{
+	string(PointLedgerStatusSuccess),
+	string(PointLedgerStatusFailed),
+	string(PointLedgerStatusPending),
+	string(PointLedgerStatusUnsettled),
+}
+
+// PointLedgerStatusNames returns a list of possible string values of PointLedgerStatus.
+func PointLedgerStatusNames() []string {
+	tmp := make([]string, len(_PointLedgerStatusNames))
+	copy(tmp, _PointLedgerStatusNames)
+	return tmp
+}
+
+// PointLedgerStatusValues returns a list of the values for PointLedgerStatus
+func PointLedgerStatusValues() []PointLedgerStatus {
+	return []PointLedgerStatus{
+		PointLedgerStatusSuccess,
+		PointLedgerStatusFailed,
+		PointLedgerStatusPending,
+		PointLedgerStatusUnsettled,
+	}
+}
+
+// String implements the Stringer interface.
+func (x PointLedgerStatus) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x PointLedgerStatus) IsValid() bool {
+	_, err := ParsePointLedgerStatus(string(x))
+	return err == nil
+}
+
+var _PointLedgerStatusValue = map[string]PointLedgerStatus{
+	"success":   PointLedgerStatusSuccess,
+	"failed":    PointLedgerStatusFailed,
+	"pending":   PointLedgerStatusPending,
+	"unsettled": PointLedgerStatusUnsettled,
+}
+
+// ParsePointLedgerStatus attempts to convert a string to a PointLedgerStatus.
+func ParsePointLedgerStatus(name string) (PointLedgerStatus, error) {
+	if x, ok := _PointLedgerStatusValue[name]; ok {
+		return x, nil
+	}
+	return PointLedgerStatus(""), fmt.Errorf("%s is %w", name, ErrInvalidPointLedgerStatus)
+}
+
+// MustParsePointLedgerStatus converts a string to a PointLedgerStatus, and panics if is not valid.
+func MustParsePointLedgerStatus(name string) PointLedgerStatus {
+	val, err := ParsePointLedgerStatus(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x PointLedgerStatus) Ptr() *PointLedgerStatus {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x PointLedgerStatus) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *PointLedgerStatus) UnmarshalText(text []byte) error {
+	tmp, err := ParsePointLedgerStatus(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"encoding/json"
+
 	"github.com/pocketbase/pocketbase/core"
 )
 
@@ -12,6 +14,7 @@ const (
 	RewardsFieldMax           = "max"
 	RewardsFieldPoint         = "point"
 	RewardsFieldShieldIds     = "shieldIds"
+	RewardsFieldMedals        = "medals" // 奖励的勋章 JSON
 	RewardsFieldMore          = "more"
 )
 
@@ -78,6 +81,24 @@ func (reward *Reward) SetShieldIds(value []string) {
 	reward.Set(RewardsFieldShieldIds, value)
 }
 
+// Medals 奖励的勋章列表
+func (reward *Reward) Medals() []RewardMedal {
+	var medals []RewardMedal
+	if value := reward.GetString(RewardsFieldMedals); value != "" {
+		_ = json.Unmarshal([]byte(value), &medals)
+	}
+	return medals
+}
+
+func (reward *Reward) SetMedals(value []RewardMedal) {
+	reward.Set(RewardsFieldMedals, value)
+}
+
+// HasPrize 是否配置了积分或勋章奖励
+func (reward *Reward) HasPrize() bool {
+	return reward.Point() > 0 || len(reward.Medals()) > 0
+}
+
 func (reward *Reward) More() string {
 	return reward.GetString(RewardsFieldMore)
 }
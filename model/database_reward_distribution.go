@@ -1,6 +1,9 @@
+//go:generate go-enum --marshal --names --values --ptr --mustparse
 package model
 
 import (
+	"encoding/json"
+
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/types"
 )
@@ -13,10 +16,23 @@ const (
 	RewardDistributionsFieldPoint   = "point"
 	RewardDistributionsFieldStatus  = "status"
 	RewardDistributionsFieldMemo    = "memo"
+	RewardDistributionsFieldSource  = "source" // 名次来源
+	RewardDistributionsFieldScore   = "score"  // 确定名次时的得票数，评审团评分模式下为加权总分
+	RewardDistributionsFieldRound   = "round"  // 评审团确定名次的轮次，普通投票为0
+	RewardDistributionsFieldMedals  = "medals" // 随奖励发放的勋章，重试时据此补建明细
 	RewardDistributionsFieldCreated = "created"
 	RewardDistributionsFieldUpdated = "updated"
 )
 
+// RewardRankSource 奖励发放的名次来源
+/*
+ENUM(
+vote_logs // 普通投票有效票数
+jury      // 评审团算票结果
+)
+*/
+type RewardRankSource string
+
 type RewardDistribution struct {
 	core.BaseRecordProxy
 }
@@ -80,6 +96,47 @@ func (rd *RewardDistribution) SetMemo(value string) {
 	rd.Set(RewardDistributionsFieldMemo, value)
 }
 
+func (rd *RewardDistribution) Source() RewardRankSource {
+	sourceStr := rd.GetString(RewardDistributionsFieldSource)
+	if sourceStr == "" {
+		return RewardRankSourceVoteLogs
+	}
+	return MustParseRewardRankSource(sourceStr)
+}
+
+func (rd *RewardDistribution) SetSource(value RewardRankSource) {
+	rd.Set(RewardDistributionsFieldSource, value)
+}
+
+func (rd *RewardDistribution) Score() float64 {
+	return rd.GetFloat(RewardDistributionsFieldScore)
+}
+
+func (rd *RewardDistribution) SetScore(value float64) {
+	rd.Set(RewardDistributionsFieldScore, value)
+}
+
+func (rd *RewardDistribution) Round() int {
+	return rd.GetInt(RewardDistributionsFieldRound)
+}
+
+func (rd *RewardDistribution) SetRound(value int) {
+	rd.Set(RewardDistributionsFieldRound, value)
+}
+
+// Medals 随奖励发放的勋章列表
+func (rd *RewardDistribution) Medals() []RewardMedal {
+	var medals []RewardMedal
+	if value := rd.GetString(RewardDistributionsFieldMedals); value != "" {
+		_ = json.Unmarshal([]byte(value), &medals)
+	}
+	return medals
+}
+
+func (rd *RewardDistribution) SetMedals(value []RewardMedal) {
+	rd.Set(RewardDistributionsFieldMedals, value)
+}
+
 func (rd *RewardDistribution) Created() types.DateTime {
 	return rd.GetDateTime(RewardDistributionsFieldCreated)
 }
@@ -0,0 +1,96 @@
+// Code generated by go-enum DO NOT EDIT.
+// Version:
+// Revision:
+// Build Date:
+// Built By:
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// RewardRankSourceVoteLogs is a RewardRankSource of type vote_logs.
+	// 普通投票有效票数
+	RewardRankSourceVoteLogs RewardRankSource = "vote_logs"
+	// RewardRankSourceJury is a RewardRankSource of type jury.
+	// 评审团算票结果
+	RewardRankSourceJury RewardRankSource = "jury"
+)
+
+var ErrInvalidRewardRankSource = fmt.Errorf("not a valid RewardRankSource, try [%s]", strings.Join(_RewardRankSourceNames, ", "))
+
+var _RewardRankSourceNames = []string{
+	string(RewardRankSourceVoteLogs),
+	string(RewardRankSourceJury),
+}
+
+// RewardRankSourceNames returns a list of possible string values of RewardRankSource.
+func RewardRankSourceNames() []string {
+	tmp := make([]string, len(_RewardRankSourceNames))
+	copy(tmp, _RewardRankSourceNames)
+	return tmp
+}
+
+// RewardRankSourceValues returns a list of the values for RewardRankSource
+func RewardRankSourceValues() []RewardRankSource {
+	return []RewardRankSource{
+		RewardRankSourceVoteLogs,
+		RewardRankSourceJury,
+	}
+}
+
+// String implements the Stringer interface.
+func (x RewardRankSource) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x RewardRankSource) IsValid() bool {
+	_, err := ParseRewardRankSource(string(x))
+	return err == nil
+}
+
+var _RewardRankSourceValue = map[string]RewardRankSource{
+	"vote_logs": RewardRankSourceVoteLogs,
+	"jury":      RewardRankSourceJury,
+}
+
+// ParseRewardRankSource attempts to convert a string to a RewardRankSource.
+func ParseRewardRankSource(name string) (RewardRankSource, error) {
+	if x, ok := _RewardRankSourceValue[name]; ok {
+		return x, nil
+	}
+	return RewardRankSource(""), fmt.Errorf("%s is %w", name, ErrInvalidRewardRankSource)
+}
+
+// MustParseRewardRankSource converts a string to a RewardRankSource, and panics if is not valid.
+func MustParseRewardRankSource(name string) RewardRankSource {
+	val, err := ParseRewardRankSource(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x RewardRankSource) Ptr() *RewardRankSource {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x RewardRankSource) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *RewardRankSource) UnmarshalText(text []byte) error {
+	tmp, err := ParseRewardRankSource(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
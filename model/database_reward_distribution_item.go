@@ -0,0 +1,118 @@
+//go:generate go-enum --marshal --names --values --ptr --mustparse
+package model
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	DbNameRewardDistributionItems              = "rewardDistributionItems" // 奖励发放明细表
+	RewardDistributionItemsFieldDistributionId = "distributionId"          // 关联发放记录ID
+	RewardDistributionItemsFieldKind           = "kind"                    // 奖励类型
+	RewardDistributionItemsFieldPoint          = "point"                   // 积分数量
+	RewardDistributionItemsFieldMedalId        = "medalId"                 // 摸鱼派勋章ID
+	RewardDistributionItemsFieldExpireTime     = "expireTime"              // 勋章过期时间（毫秒时间戳），0表示永不过期
+	RewardDistributionItemsFieldData           = "data"                    // 勋章附加数据
+	RewardDistributionItemsFieldStatus         = "status"                  // 发放状态
+	RewardDistributionItemsFieldError          = "error"                   // 失败原因
+	RewardDistributionItemsFieldCreated        = "created"                 // 创建时间
+	RewardDistributionItemsFieldUpdated        = "updated"                 // 更新时间
+)
+
+// RewardItemKind 奖励类型
+/*
+ENUM(
+point // 积分
+medal // 勋章
+)
+*/
+type RewardItemKind string
+
+// RewardDistributionItem wrapper type
+type RewardDistributionItem struct {
+	core.BaseRecordProxy
+}
+
+func NewRewardDistributionItem(record *core.Record) *RewardDistributionItem {
+	item := new(RewardDistributionItem)
+	item.SetProxyRecord(record)
+	return item
+}
+
+func NewRewardDistributionItemFromCollection(collection *core.Collection) *RewardDistributionItem {
+	record := core.NewRecord(collection)
+	return NewRewardDistributionItem(record)
+}
+
+func (item *RewardDistributionItem) DistributionId() string {
+	return item.GetString(RewardDistributionItemsFieldDistributionId)
+}
+
+func (item *RewardDistributionItem) SetDistributionId(value string) {
+	item.Set(RewardDistributionItemsFieldDistributionId, value)
+}
+
+func (item *RewardDistributionItem) Kind() RewardItemKind {
+	return MustParseRewardItemKind(item.GetString(RewardDistributionItemsFieldKind))
+}
+
+func (item *RewardDistributionItem) SetKind(value RewardItemKind) {
+	item.Set(RewardDistributionItemsFieldKind, value)
+}
+
+func (item *RewardDistributionItem) Point() int {
+	return item.GetInt(RewardDistributionItemsFieldPoint)
+}
+
+func (item *RewardDistributionItem) SetPoint(value int) {
+	item.Set(RewardDistributionItemsFieldPoint, value)
+}
+
+func (item *RewardDistributionItem) MedalId() string {
+	return item.GetString(RewardDistributionItemsFieldMedalId)
+}
+
+func (item *RewardDistributionItem) SetMedalId(value string) {
+	item.Set(RewardDistributionItemsFieldMedalId, value)
+}
+
+func (item *RewardDistributionItem) ExpireTime() int64 {
+	return int64(item.GetInt(RewardDistributionItemsFieldExpireTime))
+}
+
+func (item *RewardDistributionItem) SetExpireTime(value int64) {
+	item.Set(RewardDistributionItemsFieldExpireTime, value)
+}
+
+func (item *RewardDistributionItem) Data() string {
+	return item.GetString(RewardDistributionItemsFieldData)
+}
+
+func (item *RewardDistributionItem) SetData(value string) {
+	item.Set(RewardDistributionItemsFieldData, value)
+}
+
+func (item *RewardDistributionItem) Status() DistributionStatus {
+	return MustParseDistributionStatus(item.GetString(RewardDistributionItemsFieldStatus))
+}
+
+func (item *RewardDistributionItem) SetStatus(value DistributionStatus) {
+	item.Set(RewardDistributionItemsFieldStatus, value)
+}
+
+func (item *RewardDistributionItem) Error() string {
+	return item.GetString(RewardDistributionItemsFieldError)
+}
+
+func (item *RewardDistributionItem) SetError(value string) {
+	item.Set(RewardDistributionItemsFieldError, value)
+}
+
+func (item *RewardDistributionItem) Created() types.DateTime {
+	return item.GetDateTime(RewardDistributionItemsFieldCreated)
+}
+
+func (item *RewardDistributionItem) Updated() types.DateTime {
+	return item.GetDateTime(RewardDistributionItemsFieldUpdated)
+}
@@ -0,0 +1,96 @@
+// Code generated by go-enum DO NOT EDIT.
+// Version:
+// Revision:
+// Build Date:
+// Built By:
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// RewardItemKindPoint is a RewardItemKind of type point.
+	// 积分
+	RewardItemKindPoint RewardItemKind = "point"
+	// RewardItemKindMedal is a RewardItemKind of type medal.
+	// 勋章
+	RewardItemKindMedal RewardItemKind = "medal"
+)
+
+var ErrInvalidRewardItemKind = fmt.Errorf("not a valid RewardItemKind, try [%s]", strings.Join(_RewardItemKindNames, ", "))
+
+var _RewardItemKindNames = []string{
+	string(RewardItemKindPoint),
+	string(RewardItemKindMedal),
+}
+
+// RewardItemKindNames returns a list of possible string values of RewardItemKind.
+func RewardItemKindNames() []string {
+	tmp := make([]string, len(_RewardItemKindNames))
+	copy(tmp, _RewardItemKindNames)
+	return tmp
+}
+
+// RewardItemKindValues returns a list of the values for RewardItemKind
+func RewardItemKindValues() []RewardItemKind {
+	return []RewardItemKind{
+		RewardItemKindPoint,
+		RewardItemKindMedal,
+	}
+}
+
+// String implements the Stringer interface.
+func (x RewardItemKind) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x RewardItemKind) IsValid() bool {
+	_, err := ParseRewardItemKind(string(x))
+	return err == nil
+}
+
+var _RewardItemKindValue = map[string]RewardItemKind{
+	"point": RewardItemKindPoint,
+	"medal": RewardItemKindMedal,
+}
+
+// ParseRewardItemKind attempts to convert a string to a RewardItemKind.
+func ParseRewardItemKind(name string) (RewardItemKind, error) {
+	if x, ok := _RewardItemKindValue[name]; ok {
+		return x, nil
+	}
+	return RewardItemKind(""), fmt.Errorf("%s is %w", name, ErrInvalidRewardItemKind)
+}
+
+// MustParseRewardItemKind converts a string to a RewardItemKind, and panics if is not valid.
+func MustParseRewardItemKind(name string) RewardItemKind {
+	val, err := ParseRewardItemKind(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x RewardItemKind) Ptr() *RewardItemKind {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x RewardItemKind) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *RewardItemKind) UnmarshalText(text []byte) error {
+	tmp, err := ParseRewardItemKind(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
@@ -1,3 +1,4 @@
+//go:generate go-enum --marshal --names --values --ptr --mustparse
 package model
 
 import (
@@ -6,12 +7,38 @@ import (
 )
 
 const (
-	DbNameRewardGroups       = "rewardGroups"
-	RewardGroupsFieldName    = "name"
-	RewardGroupsFieldCreated = "created"
-	RewardGroupsFieldUpdated = "updated"
+	DbNameRewardGroups         = "rewardGroups"
+	RewardGroupsFieldName      = "name"
+	RewardGroupsFieldTiePolicy = "tiePolicy" // 同票处理方式
+	RewardGroupsFieldTieSeed   = "tieSeed"   // 随机抽签的种子，公开后可复现抽签结果
+	RewardGroupsFieldCreated   = "created"
+	RewardGroupsFieldUpdated   = "updated"
 )
 
+// RewardTiePolicy 普通投票同票时的名次处理方式
+/*
+ENUM(
+shared         // 并列名次，同票用户均获得较高名次的奖励
+split          // 并列名次，同票用户平分所占名次的奖励积分
+last_vote_time // 最后一张有效票越早名次越靠前
+random         // 按公开种子随机抽签
+)
+*/
+type RewardTiePolicy string
+
+// rewardTiePolicyDescriptions 向参与者展示的同票规则说明
+var rewardTiePolicyDescriptions = map[RewardTiePolicy]string{
+	RewardTiePolicyShared:       "同票并列名次，均获得较高名次的奖励",
+	RewardTiePolicySplit:        "同票并列名次，平分所占名次的奖励积分（向下取整）",
+	RewardTiePolicyLastVoteTime: "同票时最后一张有效票越早，名次越靠前",
+	RewardTiePolicyRandom:       "同票时按公开的随机种子抽签决定名次",
+}
+
+// Description 同票规则说明
+func (x RewardTiePolicy) Description() string {
+	return rewardTiePolicyDescriptions[x]
+}
+
 type RewardGroup struct {
 	core.BaseRecordProxy
 }
@@ -35,6 +62,26 @@ func (rewardGroup *RewardGroup) SetName(value string) {
 	rewardGroup.Set(RewardGroupsFieldName, value)
 }
 
+func (rewardGroup *RewardGroup) TiePolicy() RewardTiePolicy {
+	policyStr := rewardGroup.GetString(RewardGroupsFieldTiePolicy)
+	if policyStr == "" {
+		return RewardTiePolicyLastVoteTime
+	}
+	return MustParseRewardTiePolicy(policyStr)
+}
+
+func (rewardGroup *RewardGroup) SetTiePolicy(value RewardTiePolicy) {
+	rewardGroup.Set(RewardGroupsFieldTiePolicy, value)
+}
+
+func (rewardGroup *RewardGroup) TieSeed() string {
+	return rewardGroup.GetString(RewardGroupsFieldTieSeed)
+}
+
+func (rewardGroup *RewardGroup) SetTieSeed(value string) {
+	rewardGroup.Set(RewardGroupsFieldTieSeed, value)
+}
+
 func (rewardGroup *RewardGroup) Created() types.DateTime {
 	return rewardGroup.GetDateTime(RewardGroupsFieldCreated)
 }
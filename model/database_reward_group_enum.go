@@ -0,0 +1,108 @@
+// Code generated by go-enum DO NOT EDIT.
+// Version:
+// Revision:
+// Build Date:
+// Built By:
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// RewardTiePolicyShared is a RewardTiePolicy of type shared.
+	// 并列名次，同票用户均获得较高名次的奖励
+	RewardTiePolicyShared RewardTiePolicy = "shared"
+	// RewardTiePolicySplit is a RewardTiePolicy of type split.
+	// 并列名次，同票用户平分所占名次的奖励积分
+	RewardTiePolicySplit RewardTiePolicy = "split"
+	// RewardTiePolicyLastVoteTime is a RewardTiePolicy of type last_vote_time.
+	// 最后一张有效票越早名次越靠前
+	RewardTiePolicyLastVoteTime RewardTiePolicy = "last_vote_time"
+	// RewardTiePolicyRandom is a RewardTiePolicy of type random.
+	// 按公开种子随机抽签
+	RewardTiePolicyRandom RewardTiePolicy = "random"
+)
+
+var ErrInvalidRewardTiePolicy = fmt.Errorf("not a valid RewardTiePolicy, try [%s]", strings.Join(_RewardTiePolicyNames, ", "))
+
+var _RewardTiePolicyNames = []string{
+	string(RewardTiePolicyShared),
+	string(RewardTiePolicySplit),
+	string(RewardTiePolicyLastVoteTime),
+	string(RewardTiePolicyRandom),
+}
+
+// RewardTiePolicyNames returns a list of possible string values of RewardTiePolicy.
+func RewardTiePolicyNames() []string {
+	tmp := make([]string, len(_RewardTiePolicyNames))
+	copy(tmp, _RewardTiePolicyNames)
+	return tmp
+}
+
+// RewardTiePolicyValues returns a list of the values for RewardTiePolicy
+func RewardTiePolicyValues() []RewardTiePolicy {
+	return []RewardTiePolicy{
+		RewardTiePolicyShared,
+		RewardTiePolicySplit,
+		RewardTiePolicyLastVoteTime,
+		RewardTiePolicyRandom,
+	}
+}
+
+// String implements the Stringer interface.
+func (x RewardTiePolicy) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x RewardTiePolicy) IsValid() bool {
+	_, err := ParseRewardTiePolicy(string(x))
+	return err == nil
+}
+
+var _RewardTiePolicyValue = map[string]RewardTiePolicy{
+	"shared":         RewardTiePolicyShared,
+	"split":          RewardTiePolicySplit,
+	"last_vote_time": RewardTiePolicyLastVoteTime,
+	"random":         RewardTiePolicyRandom,
+}
+
+// ParseRewardTiePolicy attempts to convert a string to a RewardTiePolicy.
+func ParseRewardTiePolicy(name string) (RewardTiePolicy, error) {
+	if x, ok := _RewardTiePolicyValue[name]; ok {
+		return x, nil
+	}
+	return RewardTiePolicy(""), fmt.Errorf("%s is %w", name, ErrInvalidRewardTiePolicy)
+}
+
+// MustParseRewardTiePolicy converts a string to a RewardTiePolicy, and panics if is not valid.
+func MustParseRewardTiePolicy(name string) RewardTiePolicy {
+	val, err := ParseRewardTiePolicy(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x RewardTiePolicy) Ptr() *RewardTiePolicy {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x RewardTiePolicy) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *RewardTiePolicy) UnmarshalText(text []byte) error {
+	tmp, err := ParseRewardTiePolicy(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
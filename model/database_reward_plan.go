@@ -0,0 +1,118 @@
+//go:generate go-enum --marshal --names --values --ptr --mustparse
+package model
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	DbNameRewardPlans          = "rewardPlans" // 奖励发放计划表
+	RewardPlansFieldActivityId = "activityId"  // 关联活动ID
+	RewardPlansFieldVoteId     = "voteId"      // 关联投票ID
+	RewardPlansFieldSource     = "source"      // 名次来源
+	RewardPlansFieldTiePolicy  = "tiePolicy"   // 生成计划时的同票处理方式
+	RewardPlansFieldItems      = "items"       // 发放清单 JSON
+	RewardPlansFieldTotalPoint = "totalPoint"  // 待发放的积分总额（不含已发放）
+	RewardPlansFieldStatus     = "status"      // 计划状态
+	RewardPlansFieldExecutedAt = "executedAt"  // 执行时间
+	RewardPlansFieldCreated    = "created"     // 创建时间
+	RewardPlansFieldUpdated    = "updated"     // 更新时间
+)
+
+// RewardPlanStatus 奖励发放计划状态
+/*
+ENUM(
+preview  // 预览，尚未执行
+executed // 已执行
+)
+*/
+type RewardPlanStatus string
+
+// RewardPlan wrapper type
+type RewardPlan struct {
+	core.BaseRecordProxy
+}
+
+func NewRewardPlan(record *core.Record) *RewardPlan {
+	plan := new(RewardPlan)
+	plan.SetProxyRecord(record)
+	return plan
+}
+
+func NewRewardPlanFromCollection(collection *core.Collection) *RewardPlan {
+	record := core.NewRecord(collection)
+	return NewRewardPlan(record)
+}
+
+func (plan *RewardPlan) ActivityId() string {
+	return plan.GetString(RewardPlansFieldActivityId)
+}
+
+func (plan *RewardPlan) SetActivityId(value string) {
+	plan.Set(RewardPlansFieldActivityId, value)
+}
+
+func (plan *RewardPlan) VoteId() string {
+	return plan.GetString(RewardPlansFieldVoteId)
+}
+
+func (plan *RewardPlan) SetVoteId(value string) {
+	plan.Set(RewardPlansFieldVoteId, value)
+}
+
+func (plan *RewardPlan) Source() RewardRankSource {
+	return MustParseRewardRankSource(plan.GetString(RewardPlansFieldSource))
+}
+
+func (plan *RewardPlan) SetSource(value RewardRankSource) {
+	plan.Set(RewardPlansFieldSource, value)
+}
+
+func (plan *RewardPlan) TiePolicy() RewardTiePolicy {
+	return MustParseRewardTiePolicy(plan.GetString(RewardPlansFieldTiePolicy))
+}
+
+func (plan *RewardPlan) SetTiePolicy(value RewardTiePolicy) {
+	plan.Set(RewardPlansFieldTiePolicy, value)
+}
+
+func (plan *RewardPlan) Items() string {
+	return plan.GetString(RewardPlansFieldItems)
+}
+
+func (plan *RewardPlan) SetItems(value string) {
+	plan.Set(RewardPlansFieldItems, value)
+}
+
+func (plan *RewardPlan) TotalPoint() int {
+	return plan.GetInt(RewardPlansFieldTotalPoint)
+}
+
+func (plan *RewardPlan) SetTotalPoint(value int) {
+	plan.Set(RewardPlansFieldTotalPoint, value)
+}
+
+func (plan *RewardPlan) Status() RewardPlanStatus {
+	return MustParseRewardPlanStatus(plan.GetString(RewardPlansFieldStatus))
+}
+
+func (plan *RewardPlan) SetStatus(value RewardPlanStatus) {
+	plan.Set(RewardPlansFieldStatus, value)
+}
+
+func (plan *RewardPlan) ExecutedAt() types.DateTime {
+	return plan.GetDateTime(RewardPlansFieldExecutedAt)
+}
+
+func (plan *RewardPlan) SetExecutedAt(value types.DateTime) {
+	plan.Set(RewardPlansFieldExecutedAt, value)
+}
+
+func (plan *RewardPlan) Created() types.DateTime {
+	return plan.GetDateTime(RewardPlansFieldCreated)
+}
+
+func (plan *RewardPlan) Updated() types.DateTime {
+	return plan.GetDateTime(RewardPlansFieldUpdated)
+}
@@ -0,0 +1,96 @@
+// Code generated by go-enum DO NOT EDIT.
+// Version:
+// Revision:
+// Build Date:
+// Built By:
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// RewardPlanStatusPreview is a RewardPlanStatus of type preview.
+	// 预览，尚未执行
+	RewardPlanStatusPreview RewardPlanStatus = "preview"
+	// RewardPlanStatusExecuted is a RewardPlanStatus of type executed.
+	// 已执行
+	RewardPlanStatusExecuted RewardPlanStatus = "executed"
+)
+
+var ErrInvalidRewardPlanStatus = fmt.Errorf("not a valid RewardPlanStatus, try [%s]", strings.Join(_RewardPlanStatusNames, ", "))
+
+var _RewardPlanStatusNames = []string{
+	string(RewardPlanStatusPreview),
+	string(RewardPlanStatusExecuted),
+}
+
+// RewardPlanStatusNames returns a list of possible string values of RewardPlanStatus.
+func RewardPlanStatusNames() []string {
+	tmp := make([]string, len(_RewardPlanStatusNames))
+	copy(tmp, _RewardPlanStatusNames)
+	return tmp
+}
+
+// RewardPlanStatusValues returns a list of the values for RewardPlanStatus
+func RewardPlanStatusValues() []RewardPlanStatus {
+	return []RewardPlanStatus{
+		RewardPlanStatusPreview,
+		RewardPlanStatusExecuted,
+	}
+}
+
+// String implements the Stringer interface.
+func (x RewardPlanStatus) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x RewardPlanStatus) IsValid() bool {
+	_, err := ParseRewardPlanStatus(string(x))
+	return err == nil
+}
+
+var _RewardPlanStatusValue = map[string]RewardPlanStatus{
+	"preview":  RewardPlanStatusPreview,
+	"executed": RewardPlanStatusExecuted,
+}
+
+// ParseRewardPlanStatus attempts to convert a string to a RewardPlanStatus.
+func ParseRewardPlanStatus(name string) (RewardPlanStatus, error) {
+	if x, ok := _RewardPlanStatusValue[name]; ok {
+		return x, nil
+	}
+	return RewardPlanStatus(""), fmt.Errorf("%s is %w", name, ErrInvalidRewardPlanStatus)
+}
+
+// MustParseRewardPlanStatus converts a string to a RewardPlanStatus, and panics if is not valid.
+func MustParseRewardPlanStatus(name string) RewardPlanStatus {
+	val, err := ParseRewardPlanStatus(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x RewardPlanStatus) Ptr() *RewardPlanStatus {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x RewardPlanStatus) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *RewardPlanStatus) UnmarshalText(text []byte) error {
+	tmp, err := ParseRewardPlanStatus(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
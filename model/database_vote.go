@@ -2,6 +2,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/types"
 )
@@ -16,6 +18,12 @@ const (
 	VotesFieldUserRegisterDays = "userRegisterDays" // 用户注册天数限制
 	VotesFieldStart            = "start"            // 开始时间
 	VotesFieldEnd              = "end"              // 结束时间
+	VotesFieldVisibility       = "visibility"       // 结果可见性
+	VotesFieldPublished        = "published"        // 结果是否已公布
+	VotesFieldPublishedAt      = "publishedAt"      // 结果公布时间
+	VotesFieldResultSnapshot   = "resultSnapshot"   // 公布时冻结的结果快照 JSON
+	VotesFieldWeightPolicy     = "weightPolicy"     // 投票权重策略
+	VotesFieldWeightConfig     = "weightConfig"     // 投票权重配置 JSON
 )
 
 // VoteType 投票类型
@@ -27,6 +35,27 @@ jury   // 评审团投票
 */
 type VoteType string
 
+// VoteVisibility 投票结果可见性
+/*
+ENUM(
+live                 // 实时公开
+hidden_until_end     // 投票结束后公开
+hidden_until_publish // 管理员公布后公开
+)
+*/
+type VoteVisibility string
+
+// VoteWeightPolicy 投票权重策略
+/*
+ENUM(
+none          // 不加权，每票计1
+account_age   // 按账号注册天数
+medal         // 按持有的勋章
+participation // 按历史参与次数
+)
+*/
+type VoteWeightPolicy string
+
 // Vote wrapper type
 type Vote struct {
 	core.BaseRecordProxy
@@ -110,3 +139,75 @@ func (vote *Vote) End() types.DateTime {
 func (vote *Vote) SetEnd(value types.DateTime) {
 	vote.Set(VotesFieldEnd, value)
 }
+
+func (vote *Vote) Visibility() VoteVisibility {
+	visibilityStr := vote.GetString(VotesFieldVisibility)
+	if visibilityStr == "" {
+		return VoteVisibilityLive
+	}
+	return MustParseVoteVisibility(visibilityStr)
+}
+
+func (vote *Vote) SetVisibility(value VoteVisibility) {
+	vote.Set(VotesFieldVisibility, value)
+}
+
+func (vote *Vote) Published() bool {
+	return vote.GetBool(VotesFieldPublished)
+}
+
+func (vote *Vote) SetPublished(value bool) {
+	vote.Set(VotesFieldPublished, value)
+}
+
+func (vote *Vote) PublishedAt() types.DateTime {
+	return vote.GetDateTime(VotesFieldPublishedAt)
+}
+
+func (vote *Vote) SetPublishedAt(value types.DateTime) {
+	vote.Set(VotesFieldPublishedAt, value)
+}
+
+func (vote *Vote) ResultSnapshot() string {
+	return vote.GetString(VotesFieldResultSnapshot)
+}
+
+func (vote *Vote) SetResultSnapshot(value string) {
+	vote.Set(VotesFieldResultSnapshot, value)
+}
+
+func (vote *Vote) WeightPolicy() VoteWeightPolicy {
+	policyStr := vote.GetString(VotesFieldWeightPolicy)
+	if policyStr == "" {
+		return VoteWeightPolicyNone
+	}
+	return MustParseVoteWeightPolicy(policyStr)
+}
+
+func (vote *Vote) SetWeightPolicy(value VoteWeightPolicy) {
+	vote.Set(VotesFieldWeightPolicy, value)
+}
+
+func (vote *Vote) WeightConfig() string {
+	return vote.GetString(VotesFieldWeightConfig)
+}
+
+func (vote *Vote) SetWeightConfig(value string) {
+	vote.Set(VotesFieldWeightConfig, value)
+}
+
+// ResultVisible 判断非管理员在指定时间是否可以查看投票结果
+func (vote *Vote) ResultVisible(now time.Time) bool {
+	switch vote.Visibility() {
+	case VoteVisibilityHiddenUntilEnd:
+		if vote.Published() {
+			return true
+		}
+		end := vote.End()
+		return !end.IsZero() && now.After(end.Time())
+	case VoteVisibilityHiddenUntilPublish:
+		return vote.Published()
+	default:
+		return true
+	}
+}
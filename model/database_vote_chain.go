@@ -0,0 +1,132 @@
+//go:generate go-enum --marshal --names --values --ptr --mustparse
+package model
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	DbNameVoteChains        = "voteChains" // 投票哈希链表，只追加不修改
+	VoteChainsFieldVoteId   = "voteId"     // 关联投票ID
+	VoteChainsFieldSeq      = "seq"        // 链上序号，从1开始
+	VoteChainsFieldKind     = "kind"       // 操作类型
+	VoteChainsFieldSource   = "source"     // 日志来源
+	VoteChainsFieldLogId    = "logId"      // 投票日志ID
+	VoteChainsFieldReceipt  = "receipt"    // 投票回执
+	VoteChainsFieldDigest   = "digest"     // 投票日志内容摘要
+	VoteChainsFieldPrevHash = "prevHash"   // 上一条记录的哈希
+	VoteChainsFieldHash     = "hash"       // 本条记录的哈希
+	VoteChainsFieldCreated  = "created"    // 创建时间
+)
+
+// VoteChainKind 哈希链操作类型
+/*
+ENUM(
+cast       // 投票
+revoke     // 撤销投票
+invalidate // 判定无效
+)
+*/
+type VoteChainKind string
+
+// VoteChainSource 哈希链日志来源
+/*
+ENUM(
+vote_log // 普通投票日志
+jury_log // 评审团投票日志
+)
+*/
+type VoteChainSource string
+
+// VoteChain wrapper type
+type VoteChain struct {
+	core.BaseRecordProxy
+}
+
+func NewVoteChain(record *core.Record) *VoteChain {
+	chain := new(VoteChain)
+	chain.SetProxyRecord(record)
+	return chain
+}
+
+func NewVoteChainFromCollection(collection *core.Collection) *VoteChain {
+	record := core.NewRecord(collection)
+	return NewVoteChain(record)
+}
+
+func (chain *VoteChain) VoteId() string {
+	return chain.GetString(VoteChainsFieldVoteId)
+}
+
+func (chain *VoteChain) SetVoteId(value string) {
+	chain.Set(VoteChainsFieldVoteId, value)
+}
+
+func (chain *VoteChain) Seq() int {
+	return chain.GetInt(VoteChainsFieldSeq)
+}
+
+func (chain *VoteChain) SetSeq(value int) {
+	chain.Set(VoteChainsFieldSeq, value)
+}
+
+func (chain *VoteChain) Kind() VoteChainKind {
+	return MustParseVoteChainKind(chain.GetString(VoteChainsFieldKind))
+}
+
+func (chain *VoteChain) SetKind(value VoteChainKind) {
+	chain.Set(VoteChainsFieldKind, value)
+}
+
+func (chain *VoteChain) Source() VoteChainSource {
+	return MustParseVoteChainSource(chain.GetString(VoteChainsFieldSource))
+}
+
+func (chain *VoteChain) SetSource(value VoteChainSource) {
+	chain.Set(VoteChainsFieldSource, value)
+}
+
+func (chain *VoteChain) LogId() string {
+	return chain.GetString(VoteChainsFieldLogId)
+}
+
+func (chain *VoteChain) SetLogId(value string) {
+	chain.Set(VoteChainsFieldLogId, value)
+}
+
+func (chain *VoteChain) Receipt() string {
+	return chain.GetString(VoteChainsFieldReceipt)
+}
+
+func (chain *VoteChain) SetReceipt(value string) {
+	chain.Set(VoteChainsFieldReceipt, value)
+}
+
+func (chain *VoteChain) Digest() string {
+	return chain.GetString(VoteChainsFieldDigest)
+}
+
+func (chain *VoteChain) SetDigest(value string) {
+	chain.Set(VoteChainsFieldDigest, value)
+}
+
+func (chain *VoteChain) PrevHash() string {
+	return chain.GetString(VoteChainsFieldPrevHash)
+}
+
+func (chain *VoteChain) SetPrevHash(value string) {
+	chain.Set(VoteChainsFieldPrevHash, value)
+}
+
+func (chain *VoteChain) Hash() string {
+	return chain.GetString(VoteChainsFieldHash)
+}
+
+func (chain *VoteChain) SetHash(value string) {
+	chain.Set(VoteChainsFieldHash, value)
+}
+
+func (chain *VoteChain) Created() types.DateTime {
+	return chain.GetDateTime(VoteChainsFieldCreated)
+}
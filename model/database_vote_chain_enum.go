@@ -0,0 +1,186 @@
+// Code generated by go-enum DO NOT EDIT.
+// Version:
+// Revision:
+// Build Date:
+// Built By:
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// VoteChainKindCast is a VoteChainKind of type cast.
+	// 投票
+	VoteChainKindCast VoteChainKind = "cast"
+	// VoteChainKindRevoke is a VoteChainKind of type revoke.
+	// 撤销投票
+	VoteChainKindRevoke VoteChainKind = "revoke"
+	// VoteChainKindInvalidate is a VoteChainKind of type invalidate.
+	// 判定无效
+	VoteChainKindInvalidate VoteChainKind = "invalidate"
+)
+
+var ErrInvalidVoteChainKind = fmt.Errorf("not a valid VoteChainKind, try [%s]", strings.Join(_VoteChainKindNames, ", "))
+
+var _VoteChainKindNames = []string{
+	string(VoteChainKindCast),
+	string(VoteChainKindRevoke),
+	string(VoteChainKindInvalidate),
+}
+
+// VoteChainKindNames returns a list of possible string values of VoteChainKind.
+func VoteChainKindNames() []string {
+	tmp := make([]string, len(_VoteChainKindNames))
+	copy(tmp, _VoteChainKindNames)
+	return tmp
+}
+
+// VoteChainKindValues returns a list of the values for VoteChainKind
+func VoteChainKindValues() []VoteChainKind {
+	return []VoteChainKind{
+		VoteChainKindCast,
+		VoteChainKindRevoke,
+		VoteChainKindInvalidate,
+	}
+}
+
+// String implements the Stringer interface.
+func (x VoteChainKind) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x VoteChainKind) IsValid() bool {
+	_, err := ParseVoteChainKind(string(x))
+	return err == nil
+}
+
+var _VoteChainKindValue = map[string]VoteChainKind{
+	"cast":       VoteChainKindCast,
+	"revoke":     VoteChainKindRevoke,
+	"invalidate": VoteChainKindInvalidate,
+}
+
+// ParseVoteChainKind attempts to convert a string to a VoteChainKind.
+func ParseVoteChainKind(name string) (VoteChainKind, error) {
+	if x, ok := _VoteChainKindValue[name]; ok {
+		return x, nil
+	}
+	return VoteChainKind(""), fmt.Errorf("%s is %w", name, ErrInvalidVoteChainKind)
+}
+
+// MustParseVoteChainKind converts a string to a VoteChainKind, and panics if is not valid.
+func MustParseVoteChainKind(name string) VoteChainKind {
+	val, err := ParseVoteChainKind(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x VoteChainKind) Ptr() *VoteChainKind {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x VoteChainKind) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *VoteChainKind) UnmarshalText(text []byte) error {
+	tmp, err := ParseVoteChainKind(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+const (
+	// VoteChainSourceVoteLog is a VoteChainSource of type vote_log.
+	// 普通投票日志
+	VoteChainSourceVoteLog VoteChainSource = "vote_log"
+	// VoteChainSourceJuryLog is a VoteChainSource of type jury_log.
+	// 评审团投票日志
+	VoteChainSourceJuryLog VoteChainSource = "jury_log"
+)
+
+var ErrInvalidVoteChainSource = fmt.Errorf("not a valid VoteChainSource, try [%s]", strings.Join(_VoteChainSourceNames, ", "))
+
+var _VoteChainSourceNames = []string{
+	string(VoteChainSourceVoteLog),
+	string(VoteChainSourceJuryLog),
+}
+
+// VoteChainSourceNames returns a list of possible string values of VoteChainSource.
+func VoteChainSourceNames() []string {
+	tmp := make([]string, len(_VoteChainSourceNames))
+	copy(tmp, _VoteChainSourceNames)
+	return tmp
+}
+
+// VoteChainSourceValues returns a list of the values for VoteChainSource
+func VoteChainSourceValues() []VoteChainSource {
+	return []VoteChainSource{
+		VoteChainSourceVoteLog,
+		VoteChainSourceJuryLog,
+	}
+}
+
+// String implements the Stringer interface.
+func (x VoteChainSource) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x VoteChainSource) IsValid() bool {
+	_, err := ParseVoteChainSource(string(x))
+	return err == nil
+}
+
+var _VoteChainSourceValue = map[string]VoteChainSource{
+	"vote_log": VoteChainSourceVoteLog,
+	"jury_log": VoteChainSourceJuryLog,
+}
+
+// ParseVoteChainSource attempts to convert a string to a VoteChainSource.
+func ParseVoteChainSource(name string) (VoteChainSource, error) {
+	if x, ok := _VoteChainSourceValue[name]; ok {
+		return x, nil
+	}
+	return VoteChainSource(""), fmt.Errorf("%s is %w", name, ErrInvalidVoteChainSource)
+}
+
+// MustParseVoteChainSource converts a string to a VoteChainSource, and panics if is not valid.
+func MustParseVoteChainSource(name string) VoteChainSource {
+	val, err := ParseVoteChainSource(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x VoteChainSource) Ptr() *VoteChainSource {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x VoteChainSource) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *VoteChainSource) UnmarshalText(text []byte) error {
+	tmp, err := ParseVoteChainSource(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
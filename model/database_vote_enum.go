@@ -178,3 +178,189 @@ func (x *VoteType) UnmarshalText(text []byte) error {
 	*x = tmp
 	return nil
 }
+
+const (
+	// VoteVisibilityLive is a VoteVisibility of type live.
+	// 实时公开
+	VoteVisibilityLive VoteVisibility = "live"
+	// VoteVisibilityHiddenUntilEnd is a VoteVisibility of type hidden_until_end.
+	// 投票结束后公开
+	VoteVisibilityHiddenUntilEnd VoteVisibility = "hidden_until_end"
+	// VoteVisibilityHiddenUntilPublish is a VoteVisibility of type hidden_until_publish.
+	// 管理员公布后公开
+	VoteVisibilityHiddenUntilPublish VoteVisibility = "hidden_until_publish"
+)
+
+var ErrInvalidVoteVisibility = fmt.Errorf("not a valid VoteVisibility, try [%s]", strings.Join(_VoteVisibilityNames, ", "))
+
+var _VoteVisibilityNames = []string{
+	string(VoteVisibilityLive),
+	string(VoteVisibilityHiddenUntilEnd),
+	string(VoteVisibilityHiddenUntilPublish),
+}
+
+// VoteVisibilityNames returns a list of possible string values of VoteVisibility.
+func VoteVisibilityNames() []string {
+	tmp := make([]string, len(_VoteVisibilityNames))
+	copy(tmp, _VoteVisibilityNames)
+	return tmp
+}
+
+// VoteVisibilityValues returns a list of the values for VoteVisibility
+func VoteVisibilityValues() []VoteVisibility {
+	return []VoteVisibility{
+		VoteVisibilityLive,
+		VoteVisibilityHiddenUntilEnd,
+		VoteVisibilityHiddenUntilPublish,
+	}
+}
+
+// String implements the Stringer interface.
+func (x VoteVisibility) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x VoteVisibility) IsValid() bool {
+	_, err := ParseVoteVisibility(string(x))
+	return err == nil
+}
+
+var _VoteVisibilityValue = map[string]VoteVisibility{
+	"live":                 VoteVisibilityLive,
+	"hidden_until_end":     VoteVisibilityHiddenUntilEnd,
+	"hidden_until_publish": VoteVisibilityHiddenUntilPublish,
+}
+
+// ParseVoteVisibility attempts to convert a string to a VoteVisibility.
+func ParseVoteVisibility(name string) (VoteVisibility, error) {
+	if x, ok := _VoteVisibilityValue[name]; ok {
+		return x, nil
+	}
+	return VoteVisibility(""), fmt.Errorf("%s is %w", name, ErrInvalidVoteVisibility)
+}
+
+// MustParseVoteVisibility converts a string to a VoteVisibility, and panics if is not valid.
+func MustParseVoteVisibility(name string) VoteVisibility {
+	val, err := ParseVoteVisibility(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x VoteVisibility) Ptr() *VoteVisibility {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x VoteVisibility) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *VoteVisibility) UnmarshalText(text []byte) error {
+	tmp, err := ParseVoteVisibility(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+const (
+	// VoteWeightPolicyNone is a VoteWeightPolicy of type none.
+	// 不加权，每票计1
+	VoteWeightPolicyNone VoteWeightPolicy = "none"
+	// VoteWeightPolicyAccountAge is a VoteWeightPolicy of type account_age.
+	// 按账号注册天数
+	VoteWeightPolicyAccountAge VoteWeightPolicy = "account_age"
+	// VoteWeightPolicyMedal is a VoteWeightPolicy of type medal.
+	// 按持有的勋章
+	VoteWeightPolicyMedal VoteWeightPolicy = "medal"
+	// VoteWeightPolicyParticipation is a VoteWeightPolicy of type participation.
+	// 按历史参与次数
+	VoteWeightPolicyParticipation VoteWeightPolicy = "participation"
+)
+
+var ErrInvalidVoteWeightPolicy = fmt.Errorf("not a valid VoteWeightPolicy, try [%s]", strings.Join(_VoteWeightPolicyNames, ", "))
+
+var _VoteWeightPolicyNames = []string{
+	string(VoteWeightPolicyNone),
+	string(VoteWeightPolicyAccountAge),
+	string(VoteWeightPolicyMedal),
+	string(VoteWeightPolicyParticipation),
+}
+
+// VoteWeightPolicyNames returns a list of possible string values of VoteWeightPolicy.
+func VoteWeightPolicyNames() []string {
+	tmp := make([]string, len(_VoteWeightPolicyNames))
+	copy(tmp, _VoteWeightPolicyNames)
+	return tmp
+}
+
+// VoteWeightPolicyValues returns a list of the values for VoteWeightPolicy
+func VoteWeightPolicyValues() []VoteWeightPolicy {
+	return []VoteWeightPolicy{
+		VoteWeightPolicyNone,
+		VoteWeightPolicyAccountAge,
+		VoteWeightPolicyMedal,
+		VoteWeightPolicyParticipation,
+	}
+}
+
+// String implements the Stringer interface.
+func (x VoteWeightPolicy) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x VoteWeightPolicy) IsValid() bool {
+	_, err := ParseVoteWeightPolicy(string(x))
+	return err == nil
+}
+
+var _VoteWeightPolicyValue = map[string]VoteWeightPolicy{
+	"none":          VoteWeightPolicyNone,
+	"account_age":   VoteWeightPolicyAccountAge,
+	"medal":         VoteWeightPolicyMedal,
+	"participation": VoteWeightPolicyParticipation,
+}
+
+// ParseVoteWeightPolicy attempts to convert a string to a VoteWeightPolicy.
+func ParseVoteWeightPolicy(name string) (VoteWeightPolicy, error) {
+	if x, ok := _VoteWeightPolicyValue[name]; ok {
+		return x, nil
+	}
+	return VoteWeightPolicy(""), fmt.Errorf("%s is %w", name, ErrInvalidVoteWeightPolicy)
+}
+
+// MustParseVoteWeightPolicy converts a string to a VoteWeightPolicy, and panics if is not valid.
+func MustParseVoteWeightPolicy(name string) VoteWeightPolicy {
+	val, err := ParseVoteWeightPolicy(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x VoteWeightPolicy) Ptr() *VoteWeightPolicy {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x VoteWeightPolicy) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *VoteWeightPolicy) UnmarshalText(text []byte) error {
+	tmp, err := ParseVoteWeightPolicy(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
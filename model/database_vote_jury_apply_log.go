@@ -10,7 +10,7 @@ const (
 	VoteJuryApplyLogFieldVoteId  = "voteId"            // 关联投票ID
 	VoteJuryApplyLogFieldUserId  = "userId"            // 申请用户ID
 	VoteJuryApplyLogFieldReason  = "reason"            // 申请理由
-	VoteJuryApplyLogFieldStatus  = "status"            // 申请状态 待审核、已通过、已拒绝
+	VoteJuryApplyLogFieldStatus  = "status"            // 申请状态 待审核、已通过、已拒绝、候补
 	VoteJuryApplyLogFieldAdminId = "adminId"           // 审核管理员用户ID
 	VoteJuryApplyLogFieldCreated = "created"           // 创建时间
 	VoteJuryApplyLogFieldUpdated = "updated"           // 更新时间
@@ -19,9 +19,10 @@ const (
 // VoteJuryApplyLogStatus 评审团申请日志状态
 /*
 ENUM(
-pending  // 待审核
-approved // 已通过
-rejected // 已拒绝
+pending    // 待审核
+approved   // 已通过
+rejected   // 已拒绝
+waitlisted // 候补
 )
 */
 type VoteJuryApplyLogStatus string
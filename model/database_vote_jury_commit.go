@@ -0,0 +1,104 @@
+package model
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	DbNameVoteJuryCommits         = "voteJuryCommits" // 评审团秘密投票承诺表
+	VoteJuryCommitFieldVoteId     = "voteId"          // 关联投票ID
+	VoteJuryCommitFieldRound      = "round"           // 评审轮次
+	VoteJuryCommitFieldFromUserId = "fromUserId"      // 评审团成员用户ID
+	VoteJuryCommitFieldCommitment = "commitment"      // 选票承诺哈希
+	VoteJuryCommitFieldRevealed   = "revealed"        // 是否已揭示
+	VoteJuryCommitFieldBallot     = "ballot"          // 揭示后的选票 JSON 被投票用户ID列表
+	VoteJuryCommitFieldNonce      = "nonce"           // 揭示时提交的随机数
+	VoteJuryCommitFieldRevealedAt = "revealedAt"      // 揭示时间
+	VoteJuryCommitFieldCreated    = "created"         // 创建时间
+	VoteJuryCommitFieldUpdated    = "updated"         // 更新时间
+)
+
+// VoteJuryCommit wrapper type
+type VoteJuryCommit struct {
+	core.BaseRecordProxy
+}
+
+func NewVoteJuryCommit(record *core.Record) *VoteJuryCommit {
+	commit := new(VoteJuryCommit)
+	commit.SetProxyRecord(record)
+	return commit
+}
+
+func NewVoteJuryCommitFromCollection(collection *core.Collection) *VoteJuryCommit {
+	record := core.NewRecord(collection)
+	return NewVoteJuryCommit(record)
+}
+
+func (commit *VoteJuryCommit) VoteId() string {
+	return commit.GetString(VoteJuryCommitFieldVoteId)
+}
+
+func (commit *VoteJuryCommit) SetVoteId(value string) {
+	commit.Set(VoteJuryCommitFieldVoteId, value)
+}
+
+func (commit *VoteJuryCommit) Round() int {
+	return commit.GetInt(VoteJuryCommitFieldRound)
+}
+
+func (commit *VoteJuryCommit) SetRound(value int) {
+	commit.Set(VoteJuryCommitFieldRound, value)
+}
+
+func (commit *VoteJuryCommit) FromUserId() string {
+	return commit.GetString(VoteJuryCommitFieldFromUserId)
+}
+
+func (commit *VoteJuryCommit) SetFromUserId(value string) {
+	commit.Set(VoteJuryCommitFieldFromUserId, value)
+}
+
+func (commit *VoteJuryCommit) Commitment() string {
+	return commit.GetString(VoteJuryCommitFieldCommitment)
+}
+
+func (commit *VoteJuryCommit) SetCommitment(value string) {
+	commit.Set(VoteJuryCommitFieldCommitment, value)
+}
+
+func (commit *VoteJuryCommit) Revealed() bool {
+	return commit.GetBool(VoteJuryCommitFieldRevealed)
+}
+
+func (commit *VoteJuryCommit) SetRevealed(value bool) {
+	commit.Set(VoteJuryCommitFieldRevealed, value)
+}
+
+func (commit *VoteJuryCommit) Ballot() string {
+	return commit.GetString(VoteJuryCommitFieldBallot)
+}
+
+func (commit *VoteJuryCommit) SetBallot(value string) {
+	commit.Set(VoteJuryCommitFieldBallot, value)
+}
+
+func (commit *VoteJuryCommit) Nonce() string {
+	return commit.GetString(VoteJuryCommitFieldNonce)
+}
+
+func (commit *VoteJuryCommit) SetNonce(value string) {
+	commit.Set(VoteJuryCommitFieldNonce, value)
+}
+
+func (commit *VoteJuryCommit) RevealedAt() types.DateTime {
+	return commit.GetDateTime(VoteJuryCommitFieldRevealedAt)
+}
+
+func (commit *VoteJuryCommit) SetRevealedAt(value types.DateTime) {
+	commit.Set(VoteJuryCommitFieldRevealedAt, value)
+}
+
+func (commit *VoteJuryCommit) Created() types.DateTime {
+	return commit.GetDateTime(VoteJuryCommitFieldCreated)
+}
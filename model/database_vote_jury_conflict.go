@@ -0,0 +1,76 @@
+package model
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	DbNameVoteJuryConflicts          = "voteJuryConflicts" // 评审团利益冲突声明表
+	VoteJuryConflictFieldVoteId      = "voteId"            // 关联投票ID
+	VoteJuryConflictFieldJurorId     = "jurorId"           // 评审团成员用户ID
+	VoteJuryConflictFieldCandidateId = "candidateId"       // 存在利益冲突的候选人用户ID
+	VoteJuryConflictFieldReason      = "reason"            // 冲突说明
+	VoteJuryConflictFieldAdminId     = "adminId"           // 登记管理员用户ID
+	VoteJuryConflictFieldCreated     = "created"           // 创建时间
+)
+
+// VoteJuryConflict wrapper type
+type VoteJuryConflict struct {
+	core.BaseRecordProxy
+}
+
+func NewVoteJuryConflict(record *core.Record) *VoteJuryConflict {
+	conflict := new(VoteJuryConflict)
+	conflict.SetProxyRecord(record)
+	return conflict
+}
+
+func NewVoteJuryConflictFromCollection(collection *core.Collection) *VoteJuryConflict {
+	record := core.NewRecord(collection)
+	return NewVoteJuryConflict(record)
+}
+
+func (conflict *VoteJuryConflict) VoteId() string {
+	return conflict.GetString(VoteJuryConflictFieldVoteId)
+}
+
+func (conflict *VoteJuryConflict) SetVoteId(value string) {
+	conflict.Set(VoteJuryConflictFieldVoteId, value)
+}
+
+func (conflict *VoteJuryConflict) JurorId() string {
+	return conflict.GetString(VoteJuryConflictFieldJurorId)
+}
+
+func (conflict *VoteJuryConflict) SetJurorId(value string) {
+	conflict.Set(VoteJuryConflictFieldJurorId, value)
+}
+
+func (conflict *VoteJuryConflict) CandidateId() string {
+	return conflict.GetString(VoteJuryConflictFieldCandidateId)
+}
+
+func (conflict *VoteJuryConflict) SetCandidateId(value string) {
+	conflict.Set(VoteJuryConflictFieldCandidateId, value)
+}
+
+func (conflict *VoteJuryConflict) Reason() string {
+	return conflict.GetString(VoteJuryConflictFieldReason)
+}
+
+func (conflict *VoteJuryConflict) SetReason(value string) {
+	conflict.Set(VoteJuryConflictFieldReason, value)
+}
+
+func (conflict *VoteJuryConflict) AdminId() string {
+	return conflict.GetString(VoteJuryConflictFieldAdminId)
+}
+
+func (conflict *VoteJuryConflict) SetAdminId(value string) {
+	conflict.Set(VoteJuryConflictFieldAdminId, value)
+}
+
+func (conflict *VoteJuryConflict) Created() types.DateTime {
+	return conflict.GetDateTime(VoteJuryConflictFieldCreated)
+}
@@ -21,6 +21,9 @@ const (
 	// VoteJuryApplyLogStatusRejected is a VoteJuryApplyLogStatus of type rejected.
 	// 已拒绝
 	VoteJuryApplyLogStatusRejected VoteJuryApplyLogStatus = "rejected"
+	// VoteJuryApplyLogStatusWaitlisted is a VoteJuryApplyLogStatus of type waitlisted.
+	// 候补
+	VoteJuryApplyLogStatusWaitlisted VoteJuryApplyLogStatus = "waitlisted"
 )
 
 var ErrInvalidVoteJuryApplyLogStatus = fmt.Errorf("not a valid VoteJuryApplyLogStatus, try [%s]", strings.Join(_VoteJuryApplyLogStatusNames, ", "))
@@ -29,6 +32,7 @@ var _VoteJuryApplyLogStatusNames = []string{
 	string(VoteJuryApplyLogStatusPending),
 	string(VoteJuryApplyLogStatusApproved),
 	string(VoteJuryApplyLogStatusRejected),
+	string(VoteJuryApplyLogStatusWaitlisted),
 }
 
 // VoteJuryApplyLogStatusNames returns a list of possible string values of VoteJuryApplyLogStatus.
@@ -44,6 +48,7 @@ func VoteJuryApplyLogStatusValues() []VoteJuryApplyLogStatus {
 		VoteJuryApplyLogStatusPending,
 		VoteJuryApplyLogStatusApproved,
 		VoteJuryApplyLogStatusRejected,
+		VoteJuryApplyLogStatusWaitlisted,
 	}
 }
 
@@ -60,9 +65,10 @@ func (x VoteJuryApplyLogStatus) IsValid() bool {
 }
 
 var _VoteJuryApplyLogStatusValue = map[string]VoteJuryApplyLogStatus{
-	"pending":  VoteJuryApplyLogStatusPending,
-	"approved": VoteJuryApplyLogStatusApproved,
-	"rejected": VoteJuryApplyLogStatusRejected,
+	"pending":    VoteJuryApplyLogStatusPending,
+	"approved":   VoteJuryApplyLogStatusApproved,
+	"rejected":   VoteJuryApplyLogStatusRejected,
+	"waitlisted": VoteJuryApplyLogStatusWaitlisted,
 }
 
 // ParseVoteJuryApplyLogStatus attempts to convert a string to a VoteJuryApplyLogStatus.
@@ -292,3 +298,267 @@ func (x *VoteJuryUserStatus) UnmarshalText(text []byte) error {
 	*x = tmp
 	return nil
 }
+
+const (
+	// VoteJurySelectModeManual is a VoteJurySelectMode of type manual.
+	// 管理员手动审核
+	VoteJurySelectModeManual VoteJurySelectMode = "manual"
+	// VoteJurySelectModeRandom is a VoteJurySelectMode of type random.
+	// 按种子随机抽取
+	VoteJurySelectModeRandom VoteJurySelectMode = "random"
+	// VoteJurySelectModeScore is a VoteJurySelectMode of type score.
+	// 按资格分排序
+	VoteJurySelectModeScore VoteJurySelectMode = "score"
+	// VoteJurySelectModeQuota is a VoteJurySelectMode of type quota.
+	// 往届与新评审按配额随机抽取
+	VoteJurySelectModeQuota VoteJurySelectMode = "quota"
+)
+
+var ErrInvalidVoteJurySelectMode = fmt.Errorf("not a valid VoteJurySelectMode, try [%s]", strings.Join(_VoteJurySelectModeNames, ", "))
+
+var _VoteJurySelectModeNames = []string{
+	string(VoteJurySelectModeManual),
+	string(VoteJurySelectModeRandom),
+	string(VoteJurySelectModeScore),
+	string(VoteJurySelectModeQuota),
+}
+
+// VoteJurySelectModeNames returns a list of possible string values of VoteJurySelectMode.
+func VoteJurySelectModeNames() []string {
+	tmp := make([]string, len(_VoteJurySelectModeNames))
+	copy(tmp, _VoteJurySelectModeNames)
+	return tmp
+}
+
+// VoteJurySelectModeValues returns a list of the values for VoteJurySelectMode
+func VoteJurySelectModeValues() []VoteJurySelectMode {
+	return []VoteJurySelectMode{
+		VoteJurySelectModeManual,
+		VoteJurySelectModeRandom,
+		VoteJurySelectModeScore,
+		VoteJurySelectModeQuota,
+	}
+}
+
+// String implements the Stringer interface.
+func (x VoteJurySelectMode) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x VoteJurySelectMode) IsValid() bool {
+	_, err := ParseVoteJurySelectMode(string(x))
+	return err == nil
+}
+
+var _VoteJurySelectModeValue = map[string]VoteJurySelectMode{
+	"manual": VoteJurySelectModeManual,
+	"random": VoteJurySelectModeRandom,
+	"score":  VoteJurySelectModeScore,
+	"quota":  VoteJurySelectModeQuota,
+}
+
+// ParseVoteJurySelectMode attempts to convert a string to a VoteJurySelectMode.
+func ParseVoteJurySelectMode(name string) (VoteJurySelectMode, error) {
+	if x, ok := _VoteJurySelectModeValue[name]; ok {
+		return x, nil
+	}
+	return VoteJurySelectMode(""), fmt.Errorf("%s is %w", name, ErrInvalidVoteJurySelectMode)
+}
+
+// MustParseVoteJurySelectMode converts a string to a VoteJurySelectMode, and panics if is not valid.
+func MustParseVoteJurySelectMode(name string) VoteJurySelectMode {
+	val, err := ParseVoteJurySelectMode(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x VoteJurySelectMode) Ptr() *VoteJurySelectMode {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x VoteJurySelectMode) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *VoteJurySelectMode) UnmarshalText(text []byte) error {
+	tmp, err := ParseVoteJurySelectMode(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+const (
+	// VoteJuryScoringVotes is a VoteJuryScoring of type votes.
+	// 投票
+	VoteJuryScoringVotes VoteJuryScoring = "votes"
+	// VoteJuryScoringRubric is a VoteJuryScoring of type rubric.
+	// 按评分项打分
+	VoteJuryScoringRubric VoteJuryScoring = "rubric"
+)
+
+var ErrInvalidVoteJuryScoring = fmt.Errorf("not a valid VoteJuryScoring, try [%s]", strings.Join(_VoteJuryScoringNames, ", "))
+
+var _VoteJuryScoringNames = []string{
+	string(VoteJuryScoringVotes),
+	string(VoteJuryScoringRubric),
+}
+
+// VoteJuryScoringNames returns a list of possible string values of VoteJuryScoring.
+func VoteJuryScoringNames() []string {
+	tmp := make([]string, len(_VoteJuryScoringNames))
+	copy(tmp, _VoteJuryScoringNames)
+	return tmp
+}
+
+// VoteJuryScoringValues returns a list of the values for VoteJuryScoring
+func VoteJuryScoringValues() []VoteJuryScoring {
+	return []VoteJuryScoring{
+		VoteJuryScoringVotes,
+		VoteJuryScoringRubric,
+	}
+}
+
+// String implements the Stringer interface.
+func (x VoteJuryScoring) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x VoteJuryScoring) IsValid() bool {
+	_, err := ParseVoteJuryScoring(string(x))
+	return err == nil
+}
+
+var _VoteJuryScoringValue = map[string]VoteJuryScoring{
+	"votes":  VoteJuryScoringVotes,
+	"rubric": VoteJuryScoringRubric,
+}
+
+// ParseVoteJuryScoring attempts to convert a string to a VoteJuryScoring.
+func ParseVoteJuryScoring(name string) (VoteJuryScoring, error) {
+	if x, ok := _VoteJuryScoringValue[name]; ok {
+		return x, nil
+	}
+	return VoteJuryScoring(""), fmt.Errorf("%s is %w", name, ErrInvalidVoteJuryScoring)
+}
+
+// MustParseVoteJuryScoring converts a string to a VoteJuryScoring, and panics if is not valid.
+func MustParseVoteJuryScoring(name string) VoteJuryScoring {
+	val, err := ParseVoteJuryScoring(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x VoteJuryScoring) Ptr() *VoteJuryScoring {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x VoteJuryScoring) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *VoteJuryScoring) UnmarshalText(text []byte) error {
+	tmp, err := ParseVoteJuryScoring(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+const (
+	// VoteJuryAggregationTrimmedMean is a VoteJuryAggregation of type trimmed_mean.
+	// 截尾均值
+	VoteJuryAggregationTrimmedMean VoteJuryAggregation = "trimmed_mean"
+	// VoteJuryAggregationMedian is a VoteJuryAggregation of type median.
+	// 中位数
+	VoteJuryAggregationMedian VoteJuryAggregation = "median"
+)
+
+var ErrInvalidVoteJuryAggregation = fmt.Errorf("not a valid VoteJuryAggregation, try [%s]", strings.Join(_VoteJuryAggregationNames, ", "))
+
+var _VoteJuryAggregationNames = []string{
+	string(VoteJuryAggregationTrimmedMean),
+	string(VoteJuryAggregationMedian),
+}
+
+// VoteJuryAggregationNames returns a list of possible string values of VoteJuryAggregation.
+func VoteJuryAggregationNames() []string {
+	tmp := make([]string, len(_VoteJuryAggregationNames))
+	copy(tmp, _VoteJuryAggregationNames)
+	return tmp
+}
+
+// VoteJuryAggregationValues returns a list of the values for VoteJuryAggregation
+func VoteJuryAggregationValues() []VoteJuryAggregation {
+	return []VoteJuryAggregation{
+		VoteJuryAggregationTrimmedMean,
+		VoteJuryAggregationMedian,
+	}
+}
+
+// String implements the Stringer interface.
+func (x VoteJuryAggregation) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x VoteJuryAggregation) IsValid() bool {
+	_, err := ParseVoteJuryAggregation(string(x))
+	return err == nil
+}
+
+var _VoteJuryAggregationValue = map[string]VoteJuryAggregation{
+	"trimmed_mean": VoteJuryAggregationTrimmedMean,
+	"median":       VoteJuryAggregationMedian,
+}
+
+// ParseVoteJuryAggregation attempts to convert a string to a VoteJuryAggregation.
+func ParseVoteJuryAggregation(name string) (VoteJuryAggregation, error) {
+	if x, ok := _VoteJuryAggregationValue[name]; ok {
+		return x, nil
+	}
+	return VoteJuryAggregation(""), fmt.Errorf("%s is %w", name, ErrInvalidVoteJuryAggregation)
+}
+
+// MustParseVoteJuryAggregation converts a string to a VoteJuryAggregation, and panics if is not valid.
+func MustParseVoteJuryAggregation(name string) VoteJuryAggregation {
+	val, err := ParseVoteJuryAggregation(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x VoteJuryAggregation) Ptr() *VoteJuryAggregation {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x VoteJuryAggregation) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *VoteJuryAggregation) UnmarshalText(text []byte) error {
+	tmp, err := ParseVoteJuryAggregation(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
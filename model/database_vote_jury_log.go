@@ -12,6 +12,7 @@ const (
 	VoteJuryLogFieldTimes      = "times"        // 投票次数
 	VoteJuryLogFieldRound      = "round"        // 评审轮次
 	VoteJuryLogFieldComment    = "comment"      // 投票备注
+	VoteJuryLogFieldReceipt    = "receipt"      // 投票回执
 )
 
 // VoteJuryLog wrapper type
@@ -77,3 +78,11 @@ func (log *VoteJuryLog) Comment() string {
 func (log *VoteJuryLog) SetComment(value string) {
 	log.Set(VoteJuryLogFieldComment, value)
 }
+
+func (log *VoteJuryLog) Receipt() string {
+	return log.GetString(VoteJuryLogFieldReceipt)
+}
+
+func (log *VoteJuryLog) SetReceipt(value string) {
+	log.Set(VoteJuryLogFieldReceipt, value)
+}
@@ -0,0 +1,128 @@
+//go:generate go-enum --marshal --names --values --ptr --mustparse
+package model
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	DbNameVoteJuryObjections         = "voteJuryObjections" // 评审团公示异议表
+	VoteJuryObjectionFieldVoteId     = "voteId"             // 关联投票ID
+	VoteJuryObjectionFieldJurorId    = "jurorId"            // 被提出异议的评审团成员用户ID
+	VoteJuryObjectionFieldUserId     = "userId"             // 提出异议的用户ID
+	VoteJuryObjectionFieldReason     = "reason"             // 异议理由
+	VoteJuryObjectionFieldStatus     = "status"             // 处理状态
+	VoteJuryObjectionFieldNote       = "note"               // 处理说明
+	VoteJuryObjectionFieldAdminId    = "adminId"            // 处理管理员用户ID
+	VoteJuryObjectionFieldRemoved    = "removed"            // 是否因异议成立移出评审团
+	VoteJuryObjectionFieldResolvedAt = "resolvedAt"         // 处理时间
+	VoteJuryObjectionFieldCreated    = "created"            // 创建时间
+	VoteJuryObjectionFieldUpdated    = "updated"            // 更新时间
+)
+
+// VoteJuryObjectionStatus 评审团异议处理状态
+/*
+ENUM(
+pending  // 待处理
+resolved // 异议成立，已处理
+rejected // 异议不成立，已驳回
+)
+*/
+type VoteJuryObjectionStatus string
+
+// VoteJuryObjection wrapper type
+type VoteJuryObjection struct {
+	core.BaseRecordProxy
+}
+
+func NewVoteJuryObjection(record *core.Record) *VoteJuryObjection {
+	objection := new(VoteJuryObjection)
+	objection.SetProxyRecord(record)
+	return objection
+}
+
+func NewVoteJuryObjectionFromCollection(collection *core.Collection) *VoteJuryObjection {
+	record := core.NewRecord(collection)
+	return NewVoteJuryObjection(record)
+}
+
+func (objection *VoteJuryObjection) VoteId() string {
+	return objection.GetString(VoteJuryObjectionFieldVoteId)
+}
+
+func (objection *VoteJuryObjection) SetVoteId(value string) {
+	objection.Set(VoteJuryObjectionFieldVoteId, value)
+}
+
+func (objection *VoteJuryObjection) JurorId() string {
+	return objection.GetString(VoteJuryObjectionFieldJurorId)
+}
+
+func (objection *VoteJuryObjection) SetJurorId(value string) {
+	objection.Set(VoteJuryObjectionFieldJurorId, value)
+}
+
+func (objection *VoteJuryObjection) UserId() string {
+	return objection.GetString(VoteJuryObjectionFieldUserId)
+}
+
+func (objection *VoteJuryObjection) SetUserId(value string) {
+	objection.Set(VoteJuryObjectionFieldUserId, value)
+}
+
+func (objection *VoteJuryObjection) Reason() string {
+	return objection.GetString(VoteJuryObjectionFieldReason)
+}
+
+func (objection *VoteJuryObjection) SetReason(value string) {
+	objection.Set(VoteJuryObjectionFieldReason, value)
+}
+
+func (objection *VoteJuryObjection) Status() VoteJuryObjectionStatus {
+	return MustParseVoteJuryObjectionStatus(objection.GetString(VoteJuryObjectionFieldStatus))
+}
+
+func (objection *VoteJuryObjection) SetStatus(value VoteJuryObjectionStatus) {
+	objection.Set(VoteJuryObjectionFieldStatus, value)
+}
+
+func (objection *VoteJuryObjection) Note() string {
+	return objection.GetString(VoteJuryObjectionFieldNote)
+}
+
+func (objection *VoteJuryObjection) SetNote(value string) {
+	objection.Set(VoteJuryObjectionFieldNote, value)
+}
+
+func (objection *VoteJuryObjection) AdminId() string {
+	return objection.GetString(VoteJuryObjectionFieldAdminId)
+}
+
+func (objection *VoteJuryObjection) SetAdminId(value string) {
+	objection.Set(VoteJuryObjectionFieldAdminId, value)
+}
+
+func (objection *VoteJuryObjection) Removed() bool {
+	return objection.GetBool(VoteJuryObjectionFieldRemoved)
+}
+
+func (objection *VoteJuryObjection) SetRemoved(value bool) {
+	objection.Set(VoteJuryObjectionFieldRemoved, value)
+}
+
+func (objection *VoteJuryObjection) ResolvedAt() types.DateTime {
+	return objection.GetDateTime(VoteJuryObjectionFieldResolvedAt)
+}
+
+func (objection *VoteJuryObjection) SetResolvedAt(value types.DateTime) {
+	objection.Set(VoteJuryObjectionFieldResolvedAt, value)
+}
+
+func (objection *VoteJuryObjection) Created() types.DateTime {
+	return objection.GetDateTime(VoteJuryObjectionFieldCreated)
+}
+
+func (objection *VoteJuryObjection) Updated() types.DateTime {
+	return objection.GetDateTime(VoteJuryObjectionFieldUpdated)
+}
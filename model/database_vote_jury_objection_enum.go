@@ -0,0 +1,102 @@
+// Code generated by go-enum DO NOT EDIT.
+// Version:
+// Revision:
+// Build Date:
+// Built By:
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// VoteJuryObjectionStatusPending is a VoteJuryObjectionStatus of type pending.
+	// 待处理
+	VoteJuryObjectionStatusPending VoteJuryObjectionStatus = "pending"
+	// VoteJuryObjectionStatusResolved is a VoteJuryObjectionStatus of type resolved.
+	// 异议成立，已处理
+	VoteJuryObjectionStatusResolved VoteJuryObjectionStatus = "resolved"
+	// VoteJuryObjectionStatusRejected is a VoteJuryObjectionStatus of type rejected.
+	// 异议不成立，已驳回
+	VoteJuryObjectionStatusRejected VoteJuryObjectionStatus = "rejected"
+)
+
+var ErrInvalidVoteJuryObjectionStatus = fmt.Errorf("not a valid VoteJuryObjectionStatus, try [%s]", strings.Join(_VoteJuryObjectionStatusNames, ", "))
+
+var _VoteJuryObjectionStatusNames = []string{
+	string(VoteJuryObjectionStatusPending),
+	string(VoteJuryObjectionStatusResolved),
+	string(VoteJuryObjectionStatusRejected),
+}
+
+// VoteJuryObjectionStatusNames returns a list of possible string values of VoteJuryObjectionStatus.
+func VoteJuryObjectionStatusNames() []string {
+	tmp := make([]string, len(_VoteJuryObjectionStatusNames))
+	copy(tmp, _VoteJuryObjectionStatusNames)
+	return tmp
+}
+
+// VoteJuryObjectionStatusValues returns a list of the values for VoteJuryObjectionStatus
+func VoteJuryObjectionStatusValues() []VoteJuryObjectionStatus {
+	return []VoteJuryObjectionStatus{
+		VoteJuryObjectionStatusPending,
+		VoteJuryObjectionStatusResolved,
+		VoteJuryObjectionStatusRejected,
+	}
+}
+
+// String implements the Stringer interface.
+func (x VoteJuryObjectionStatus) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x VoteJuryObjectionStatus) IsValid() bool {
+	_, err := ParseVoteJuryObjectionStatus(string(x))
+	return err == nil
+}
+
+var _VoteJuryObjectionStatusValue = map[string]VoteJuryObjectionStatus{
+	"pending":  VoteJuryObjectionStatusPending,
+	"resolved": VoteJuryObjectionStatusResolved,
+	"rejected": VoteJuryObjectionStatusRejected,
+}
+
+// ParseVoteJuryObjectionStatus attempts to convert a string to a VoteJuryObjectionStatus.
+func ParseVoteJuryObjectionStatus(name string) (VoteJuryObjectionStatus, error) {
+	if x, ok := _VoteJuryObjectionStatusValue[name]; ok {
+		return x, nil
+	}
+	return VoteJuryObjectionStatus(""), fmt.Errorf("%s is %w", name, ErrInvalidVoteJuryObjectionStatus)
+}
+
+// MustParseVoteJuryObjectionStatus converts a string to a VoteJuryObjectionStatus, and panics if is not valid.
+func MustParseVoteJuryObjectionStatus(name string) VoteJuryObjectionStatus {
+	val, err := ParseVoteJuryObjectionStatus(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x VoteJuryObjectionStatus) Ptr() *VoteJuryObjectionStatus {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x VoteJuryObjectionStatus) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *VoteJuryObjectionStatus) UnmarshalText(text []byte) error {
+	tmp, err := ParseVoteJuryObjectionStatus(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
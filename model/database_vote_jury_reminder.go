@@ -0,0 +1,140 @@
+//go:generate go-enum --marshal --names --values --ptr --mustparse
+package model
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	DbNameVoteJuryReminders      = "voteJuryReminders" // 评审团催投提醒记录表
+	VoteJuryReminderFieldVoteId  = "voteId"            // 关联投票ID
+	VoteJuryReminderFieldRound   = "round"             // 评审轮次
+	VoteJuryReminderFieldPhase   = "phase"             // 提醒阶段
+	VoteJuryReminderFieldOffset  = "offset"            // 距截止时间的提前量（分钟），手动提醒为0
+	VoteJuryReminderFieldUserId  = "userId"            // 被提醒的评审团成员用户ID
+	VoteJuryReminderFieldTrigger = "trigger"           // 触发方式
+	VoteJuryReminderFieldActorId = "actorId"           // 手动提醒的管理员用户ID
+	VoteJuryReminderFieldContent = "content"           // 发送内容
+	VoteJuryReminderFieldStatus  = "status"            // 发送状态
+	VoteJuryReminderFieldError   = "error"             // 失败原因
+	VoteJuryReminderFieldCreated = "created"           // 创建时间
+)
+
+// VoteJuryReminderPhase 评审团提醒阶段
+/*
+ENUM(
+vote   // 投票（含评分、提交承诺）
+reveal // 揭示选票
+)
+*/
+type VoteJuryReminderPhase string
+
+// VoteJuryReminderStatus 评审团提醒发送状态
+/*
+ENUM(
+sent   // 已发送
+failed // 发送失败
+)
+*/
+type VoteJuryReminderStatus string
+
+// VoteJuryReminder wrapper type
+type VoteJuryReminder struct {
+	core.BaseRecordProxy
+}
+
+func NewVoteJuryReminder(record *core.Record) *VoteJuryReminder {
+	reminder := new(VoteJuryReminder)
+	reminder.SetProxyRecord(record)
+	return reminder
+}
+
+func NewVoteJuryReminderFromCollection(collection *core.Collection) *VoteJuryReminder {
+	record := core.NewRecord(collection)
+	return NewVoteJuryReminder(record)
+}
+
+func (reminder *VoteJuryReminder) VoteId() string {
+	return reminder.GetString(VoteJuryReminderFieldVoteId)
+}
+
+func (reminder *VoteJuryReminder) SetVoteId(value string) {
+	reminder.Set(VoteJuryReminderFieldVoteId, value)
+}
+
+func (reminder *VoteJuryReminder) Round() int {
+	return reminder.GetInt(VoteJuryReminderFieldRound)
+}
+
+func (reminder *VoteJuryReminder) SetRound(value int) {
+	reminder.Set(VoteJuryReminderFieldRound, value)
+}
+
+func (reminder *VoteJuryReminder) Phase() VoteJuryReminderPhase {
+	return MustParseVoteJuryReminderPhase(reminder.GetString(VoteJuryReminderFieldPhase))
+}
+
+func (reminder *VoteJuryReminder) SetPhase(value VoteJuryReminderPhase) {
+	reminder.Set(VoteJuryReminderFieldPhase, value)
+}
+
+func (reminder *VoteJuryReminder) Offset() int {
+	return reminder.GetInt(VoteJuryReminderFieldOffset)
+}
+
+func (reminder *VoteJuryReminder) SetOffset(value int) {
+	reminder.Set(VoteJuryReminderFieldOffset, value)
+}
+
+func (reminder *VoteJuryReminder) UserId() string {
+	return reminder.GetString(VoteJuryReminderFieldUserId)
+}
+
+func (reminder *VoteJuryReminder) SetUserId(value string) {
+	reminder.Set(VoteJuryReminderFieldUserId, value)
+}
+
+func (reminder *VoteJuryReminder) Trigger() VoteJuryStatusTrigger {
+	return MustParseVoteJuryStatusTrigger(reminder.GetString(VoteJuryReminderFieldTrigger))
+}
+
+func (reminder *VoteJuryReminder) SetTrigger(value VoteJuryStatusTrigger) {
+	reminder.Set(VoteJuryReminderFieldTrigger, value)
+}
+
+func (reminder *VoteJuryReminder) ActorId() string {
+	return reminder.GetString(VoteJuryReminderFieldActorId)
+}
+
+func (reminder *VoteJuryReminder) SetActorId(value string) {
+	reminder.Set(VoteJuryReminderFieldActorId, value)
+}
+
+func (reminder *VoteJuryReminder) Content() string {
+	return reminder.GetString(VoteJuryReminderFieldContent)
+}
+
+func (reminder *VoteJuryReminder) SetContent(value string) {
+	reminder.Set(VoteJuryReminderFieldContent, value)
+}
+
+func (reminder *VoteJuryReminder) Status() VoteJuryReminderStatus {
+	return MustParseVoteJuryReminderStatus(reminder.GetString(VoteJuryReminderFieldStatus))
+}
+
+func (reminder *VoteJuryReminder) SetStatus(value VoteJuryReminderStatus) {
+	reminder.Set(VoteJuryReminderFieldStatus, value)
+}
+
+func (reminder *VoteJuryReminder) Error() string {
+	return reminder.GetString(VoteJuryReminderFieldError)
+}
+
+func (reminder *VoteJuryReminder) SetError(value string) {
+	reminder.Set(VoteJuryReminderFieldError, value)
+}
+
+func (reminder *VoteJuryReminder) Created() types.DateTime {
+	return reminder.GetDateTime(VoteJuryReminderFieldCreated)
+}
@@ -0,0 +1,180 @@
+// Code generated by go-enum DO NOT EDIT.
+// Version:
+// Revision:
+// Build Date:
+// Built By:
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// VoteJuryReminderPhaseVote is a VoteJuryReminderPhase of type vote.
+	// 投票（含评分、提交承诺）
+	VoteJuryReminderPhaseVote VoteJuryReminderPhase = "vote"
+	// VoteJuryReminderPhaseReveal is a VoteJuryReminderPhase of type reveal.
+	// 揭示选票
+	VoteJuryReminderPhaseReveal VoteJuryReminderPhase = "reveal"
+)
+
+var ErrInvalidVoteJuryReminderPhase = fmt.Errorf("not a valid VoteJuryReminderPhase, try [%s]", strings.Join(_VoteJuryReminderPhaseNames, ", "))
+
+var _VoteJuryReminderPhaseNames = []string{
+	string(VoteJuryReminderPhaseVote),
+	string(VoteJuryReminderPhaseReveal),
+}
+
+// VoteJuryReminderPhaseNames returns a list of possible string values of VoteJuryReminderPhase.
+func VoteJuryReminderPhaseNames() []string {
+	tmp := make([]string, len(_VoteJuryReminderPhaseNames))
+	copy(tmp, _VoteJuryReminderPhaseNames)
+	return tmp
+}
+
+// VoteJuryReminderPhaseValues returns a list of the values for VoteJuryReminderPhase
+func VoteJuryReminderPhaseValues() []VoteJuryReminderPhase {
+	return []VoteJuryReminderPhase{
+		VoteJuryReminderPhaseVote,
+		VoteJuryReminderPhaseReveal,
+	}
+}
+
+// String implements the Stringer interface.
+func (x VoteJuryReminderPhase) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x VoteJuryReminderPhase) IsValid() bool {
+	_, err := ParseVoteJuryReminderPhase(string(x))
+	return err == nil
+}
+
+var _VoteJuryReminderPhaseValue = map[string]VoteJuryReminderPhase{
+	"vote":   VoteJuryReminderPhaseVote,
+	"reveal": VoteJuryReminderPhaseReveal,
+}
+
+// ParseVoteJuryReminderPhase attempts to convert a string to a VoteJuryReminderPhase.
+func ParseVoteJuryReminderPhase(name string) (VoteJuryReminderPhase, error) {
+	if x, ok := _VoteJuryReminderPhaseValue[name]; ok {
+		return x, nil
+	}
+	return VoteJuryReminderPhase(""), fmt.Errorf("%s is %w", name, ErrInvalidVoteJuryReminderPhase)
+}
+
+// MustParseVoteJuryReminderPhase converts a string to a VoteJuryReminderPhase, and panics if is not valid.
+func MustParseVoteJuryReminderPhase(name string) VoteJuryReminderPhase {
+	val, err := ParseVoteJuryReminderPhase(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x VoteJuryReminderPhase) Ptr() *VoteJuryReminderPhase {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x VoteJuryReminderPhase) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *VoteJuryReminderPhase) UnmarshalText(text []byte) error {
+	tmp, err := ParseVoteJuryReminderPhase(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+const (
+	// VoteJuryReminderStatusSent is a VoteJuryReminderStatus of type sent.
+	// 已发送
+	VoteJuryReminderStatusSent VoteJuryReminderStatus = "sent"
+	// VoteJuryReminderStatusFailed is a VoteJuryReminderStatus of type failed.
+	// 发送失败
+	VoteJuryReminderStatusFailed VoteJuryReminderStatus = "failed"
+)
+
+var ErrInvalidVoteJuryReminderStatus = fmt.Errorf("not a valid VoteJuryReminderStatus, try [%s]", strings.Join(_VoteJuryReminderStatusNames, ", "))
+
+var _VoteJuryReminderStatusNames = []string{
+	string(VoteJuryReminderStatusSent),
+	string(VoteJuryReminderStatusFailed),
+}
+
+// VoteJuryReminderStatusNames returns a list of possible string values of VoteJuryReminderStatus.
+func VoteJuryReminderStatusNames() []string {
+	tmp := make([]string, len(_VoteJuryReminderStatusNames))
+	copy(tmp, _VoteJuryReminderStatusNames)
+	return tmp
+}
+
+// VoteJuryReminderStatusValues returns a list of the values for VoteJuryReminderStatus
+func VoteJuryReminderStatusValues() []VoteJuryReminderStatus {
+	return []VoteJuryReminderStatus{
+		VoteJuryReminderStatusSent,
+		VoteJuryReminderStatusFailed,
+	}
+}
+
+// String implements the Stringer interface.
+func (x VoteJuryReminderStatus) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x VoteJuryReminderStatus) IsValid() bool {
+	_, err := ParseVoteJuryReminderStatus(string(x))
+	return err == nil
+}
+
+var _VoteJuryReminderStatusValue = map[string]VoteJuryReminderStatus{
+	"sent":   VoteJuryReminderStatusSent,
+	"failed": VoteJuryReminderStatusFailed,
+}
+
+// ParseVoteJuryReminderStatus attempts to convert a string to a VoteJuryReminderStatus.
+func ParseVoteJuryReminderStatus(name string) (VoteJuryReminderStatus, error) {
+	if x, ok := _VoteJuryReminderStatusValue[name]; ok {
+		return x, nil
+	}
+	return VoteJuryReminderStatus(""), fmt.Errorf("%s is %w", name, ErrInvalidVoteJuryReminderStatus)
+}
+
+// MustParseVoteJuryReminderStatus converts a string to a VoteJuryReminderStatus, and panics if is not valid.
+func MustParseVoteJuryReminderStatus(name string) VoteJuryReminderStatus {
+	val, err := ParseVoteJuryReminderStatus(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x VoteJuryReminderStatus) Ptr() *VoteJuryReminderStatus {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x VoteJuryReminderStatus) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *VoteJuryReminderStatus) UnmarshalText(text []byte) error {
+	tmp, err := ParseVoteJuryReminderStatus(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
@@ -6,13 +6,15 @@ import (
 )
 
 const (
-	DbNameVoteJuryResults       = "voteJuryResults" // 评审团结果表
-	VoteJuryResultFieldVoteId   = "voteId"          // 关联投票ID
-	VoteJuryResultFieldRound    = "round"           // 评审轮次
-	VoteJuryResultFieldResults  = "results"         // 评审结果 JSON 用户ID与得票数映射
-	VoteJuryResultFieldContinue = "continue"        // 是否进入下一轮评审
-	VoteJuryResultFieldUserIds  = "userIds"         // 进入下一轮评审的用户ID列表
-	VoteJuryResultFieldCreated  = "created"         // 创建时间
+	DbNameVoteJuryResults        = "voteJuryResults" // 评审团结果表
+	VoteJuryResultFieldVoteId    = "voteId"          // 关联投票ID
+	VoteJuryResultFieldRound     = "round"           // 评审轮次
+	VoteJuryResultFieldResults   = "results"         // 评审结果 JSON 用户ID与得票数映射
+	VoteJuryResultFieldContinue  = "continue"        // 是否进入下一轮评审
+	VoteJuryResultFieldUserIds   = "userIds"         // 进入下一轮评审的用户ID列表，评审结束时为按名次排列的获奖用户ID
+	VoteJuryResultFieldBreakdown = "breakdown"       // 评分模式下各候选人评分明细 JSON
+	VoteJuryResultFieldRanking   = "ranking"         // 名次结算 JSON
+	VoteJuryResultFieldCreated   = "created"         // 创建时间
 )
 
 // VoteJuryResult wrapper type
@@ -71,6 +73,22 @@ func (result *VoteJuryResult) SetUserIds(value []string) {
 	result.Set(VoteJuryResultFieldUserIds, value)
 }
 
+func (result *VoteJuryResult) Ranking() string {
+	return result.GetString(VoteJuryResultFieldRanking)
+}
+
+func (result *VoteJuryResult) SetRanking(value string) {
+	result.Set(VoteJuryResultFieldRanking, value)
+}
+
+func (result *VoteJuryResult) Breakdown() string {
+	return result.GetString(VoteJuryResultFieldBreakdown)
+}
+
+func (result *VoteJuryResult) SetBreakdown(value string) {
+	result.Set(VoteJuryResultFieldBreakdown, value)
+}
+
 func (result *VoteJuryResult) Created() types.DateTime {
 	return result.GetDateTime(VoteJuryResultFieldCreated)
 }
@@ -2,22 +2,45 @@
 package model
 
 import (
+	"encoding/json"
+
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 const (
-	DbNameVoteJuryRules            = "voteJuryRules" // 评审团投票规则表
-	VoteJuryRuleFieldVoteId        = "voteId"        // 关联投票ID
-	VoteJuryRuleFieldCount         = "count"         // 评审团成员数量
-	VoteJuryRuleFieldAdmins        = "admins"        // 评审团管理员用户ID列表
-	VoteJuryRuleFieldDecisions     = "decisions"     // 评审团决策者用户ID列表
-	VoteJuryRuleFieldStatus        = "status"        // 评审团状态 未开启、开放申请中、公示中、评审中、计票完成
-	VoteJuryRuleFieldCurrentRound  = "currentRound"  // 当前轮次
-	VoteJuryRuleFieldApplyTime     = "applyTime"     // 开放申请时间
-	VoteJuryRuleFieldPublicityTime = "publicityTime" // 公示时间
-	VoteJuryRuleFieldCreated       = "created"       // 创建时间
-	VoteJuryRuleFieldUpdated       = "updated"       // 更新时间
+	DbNameVoteJuryRules                   = "voteJuryRules"        // 评审团投票规则表
+	VoteJuryRuleFieldVoteId               = "voteId"               // 关联投票ID
+	VoteJuryRuleFieldCount                = "count"                // 评审团成员数量
+	VoteJuryRuleFieldAdmins               = "admins"               // 评审团管理员用户ID列表
+	VoteJuryRuleFieldDecisions            = "decisions"            // 评审团决策者用户ID列表
+	VoteJuryRuleFieldStatus               = "status"               // 评审团状态 未开启、开放申请中、公示中、评审中、计票完成
+	VoteJuryRuleFieldCurrentRound         = "currentRound"         // 当前轮次
+	VoteJuryRuleFieldApplyTime            = "applyTime"            // 开放申请时间
+	VoteJuryRuleFieldPublicityTime        = "publicityTime"        // 公示时间
+	VoteJuryRuleFieldAutoSchedule         = "autoSchedule"         // 是否按时间自动切换状态
+	VoteJuryRuleFieldAutoCalculate        = "autoCalculate"        // 投票结束后是否自动算票
+	VoteJuryRuleFieldSelectMode           = "selectMode"           // 评审团成员遴选方式
+	VoteJuryRuleFieldSelectSeed           = "selectSeed"           // 随机遴选种子，进入申请阶段时生成，遴选时公开
+	VoteJuryRuleFieldSelectSeedHash       = "selectSeedHash"       // 遴选种子的 sha256 承诺，进入申请阶段时公示
+	VoteJuryRuleFieldReturningQuota       = "returningQuota"       // 配额遴选时保留给往届评审的席位数
+	VoteJuryRuleFieldSelection            = "selection"            // 遴选结果记录 JSON
+	VoteJuryRuleFieldWinners              = "winners"              // 需要决出的名次数量，默认为1
+	VoteJuryRuleFieldScoring              = "scoring"              // 评审方式 投票、评分
+	VoteJuryRuleFieldCriteria             = "criteria"             // 评分项配置 JSON
+	VoteJuryRuleFieldAggregation          = "aggregation"          // 评分汇总方式
+	VoteJuryRuleFieldTrimRatio            = "trimRatio"            // 截尾均值两端各去除的比例，默认0.2
+	VoteJuryRuleFieldOutlierThreshold     = "outlierThreshold"     // 偏离中位数超过分值范围的该比例视为异常评分，默认0.3
+	VoteJuryRuleFieldSecretBallot         = "secretBallot"         // 是否启用承诺-揭示秘密投票
+	VoteJuryRuleFieldRevealing            = "revealing"            // 当前轮次是否处于揭示阶段
+	VoteJuryRuleFieldRevealStart          = "revealStart"          // 当前轮次揭示阶段开始时间
+	VoteJuryRuleFieldRevealMinutes        = "revealMinutes"        // 揭示阶段时长（分钟），默认60
+	VoteJuryRuleFieldReminderOffsets      = "reminderOffsets"      // 催投提醒时间点 JSON 距截止时间的分钟数列表
+	VoteJuryRuleFieldReminderTemplate     = "reminderTemplate"     // 投票催投提醒模板，为空时使用默认模板
+	VoteJuryRuleFieldRevealTemplate       = "revealTemplate"       // 揭示催投提醒模板，为空时使用默认模板
+	VoteJuryRuleFieldMinParticipationRate = "minParticipationRate" // 申请人往届评审的最低参与率（0-1），0表示不限制，没有往届记录的申请人不受限制
+	VoteJuryRuleFieldCreated              = "created"              // 创建时间
+	VoteJuryRuleFieldUpdated              = "updated"              // 更新时间
 )
 
 // VoteJuryRuleStatus 评审团规则状态
@@ -32,6 +55,35 @@ completed  // 计票完成
 */
 type VoteJuryRuleStatus string
 
+// VoteJurySelectMode 评审团成员遴选方式
+/*
+ENUM(
+manual // 管理员手动审核
+random // 按种子随机抽取
+score  // 按资格分排序
+quota  // 往届与新评审按配额随机抽取
+)
+*/
+type VoteJurySelectMode string
+
+// VoteJuryScoring 评审方式
+/*
+ENUM(
+votes  // 投票
+rubric // 按评分项打分
+)
+*/
+type VoteJuryScoring string
+
+// VoteJuryAggregation 评分汇总方式
+/*
+ENUM(
+trimmed_mean // 截尾均值
+median       // 中位数
+)
+*/
+type VoteJuryAggregation string
+
 // VoteJuryRule wrapper type
 type VoteJuryRule struct {
 	core.BaseRecordProxy
@@ -111,3 +163,202 @@ func (rule *VoteJuryRule) CurrentRound() int {
 func (rule *VoteJuryRule) SetCurrentRound(value int) {
 	rule.Set(VoteJuryRuleFieldCurrentRound, value)
 }
+
+func (rule *VoteJuryRule) AutoSchedule() bool {
+	return rule.GetBool(VoteJuryRuleFieldAutoSchedule)
+}
+
+func (rule *VoteJuryRule) SetAutoSchedule(value bool) {
+	rule.Set(VoteJuryRuleFieldAutoSchedule, value)
+}
+
+func (rule *VoteJuryRule) AutoCalculate() bool {
+	return rule.GetBool(VoteJuryRuleFieldAutoCalculate)
+}
+
+func (rule *VoteJuryRule) SetAutoCalculate(value bool) {
+	rule.Set(VoteJuryRuleFieldAutoCalculate, value)
+}
+
+func (rule *VoteJuryRule) SelectMode() VoteJurySelectMode {
+	modeStr := rule.GetString(VoteJuryRuleFieldSelectMode)
+	if modeStr == "" {
+		return VoteJurySelectModeManual
+	}
+	return MustParseVoteJurySelectMode(modeStr)
+}
+
+func (rule *VoteJuryRule) SetSelectMode(value VoteJurySelectMode) {
+	rule.Set(VoteJuryRuleFieldSelectMode, value)
+}
+
+func (rule *VoteJuryRule) SelectSeed() string {
+	return rule.GetString(VoteJuryRuleFieldSelectSeed)
+}
+
+func (rule *VoteJuryRule) SetSelectSeed(value string) {
+	rule.Set(VoteJuryRuleFieldSelectSeed, value)
+}
+
+func (rule *VoteJuryRule) SelectSeedHash() string {
+	return rule.GetString(VoteJuryRuleFieldSelectSeedHash)
+}
+
+func (rule *VoteJuryRule) SetSelectSeedHash(value string) {
+	rule.Set(VoteJuryRuleFieldSelectSeedHash, value)
+}
+
+func (rule *VoteJuryRule) ReturningQuota() int {
+	return rule.GetInt(VoteJuryRuleFieldReturningQuota)
+}
+
+func (rule *VoteJuryRule) SetReturningQuota(value int) {
+	rule.Set(VoteJuryRuleFieldReturningQuota, value)
+}
+
+func (rule *VoteJuryRule) Selection() string {
+	return rule.GetString(VoteJuryRuleFieldSelection)
+}
+
+func (rule *VoteJuryRule) SetSelection(value string) {
+	rule.Set(VoteJuryRuleFieldSelection, value)
+}
+
+// Winners 需要决出的名次数量，未配置时为1
+func (rule *VoteJuryRule) Winners() int {
+	if winners := rule.GetInt(VoteJuryRuleFieldWinners); winners > 0 {
+		return winners
+	}
+	return 1
+}
+
+func (rule *VoteJuryRule) SetWinners(value int) {
+	rule.Set(VoteJuryRuleFieldWinners, value)
+}
+
+func (rule *VoteJuryRule) Scoring() VoteJuryScoring {
+	scoringStr := rule.GetString(VoteJuryRuleFieldScoring)
+	if scoringStr == "" {
+		return VoteJuryScoringVotes
+	}
+	return MustParseVoteJuryScoring(scoringStr)
+}
+
+func (rule *VoteJuryRule) SetScoring(value VoteJuryScoring) {
+	rule.Set(VoteJuryRuleFieldScoring, value)
+}
+
+func (rule *VoteJuryRule) Criteria() string {
+	return rule.GetString(VoteJuryRuleFieldCriteria)
+}
+
+func (rule *VoteJuryRule) SetCriteria(value string) {
+	rule.Set(VoteJuryRuleFieldCriteria, value)
+}
+
+func (rule *VoteJuryRule) Aggregation() VoteJuryAggregation {
+	aggregationStr := rule.GetString(VoteJuryRuleFieldAggregation)
+	if aggregationStr == "" {
+		return VoteJuryAggregationTrimmedMean
+	}
+	return MustParseVoteJuryAggregation(aggregationStr)
+}
+
+func (rule *VoteJuryRule) SetAggregation(value VoteJuryAggregation) {
+	rule.Set(VoteJuryRuleFieldAggregation, value)
+}
+
+func (rule *VoteJuryRule) TrimRatio() float64 {
+	ratio := rule.GetFloat(VoteJuryRuleFieldTrimRatio)
+	if ratio <= 0 || ratio >= 0.5 {
+		return 0.2
+	}
+	return ratio
+}
+
+func (rule *VoteJuryRule) SetTrimRatio(value float64) {
+	rule.Set(VoteJuryRuleFieldTrimRatio, value)
+}
+
+func (rule *VoteJuryRule) OutlierThreshold() float64 {
+	if threshold := rule.GetFloat(VoteJuryRuleFieldOutlierThreshold); threshold > 0 {
+		return threshold
+	}
+	return 0.3
+}
+
+func (rule *VoteJuryRule) SetOutlierThreshold(value float64) {
+	rule.Set(VoteJuryRuleFieldOutlierThreshold, value)
+}
+
+func (rule *VoteJuryRule) SecretBallot() bool {
+	return rule.GetBool(VoteJuryRuleFieldSecretBallot)
+}
+
+func (rule *VoteJuryRule) SetSecretBallot(value bool) {
+	rule.Set(VoteJuryRuleFieldSecretBallot, value)
+}
+
+func (rule *VoteJuryRule) Revealing() bool {
+	return rule.GetBool(VoteJuryRuleFieldRevealing)
+}
+
+func (rule *VoteJuryRule) SetRevealing(value bool) {
+	rule.Set(VoteJuryRuleFieldRevealing, value)
+}
+
+func (rule *VoteJuryRule) RevealStart() types.DateTime {
+	return rule.GetDateTime(VoteJuryRuleFieldRevealStart)
+}
+
+func (rule *VoteJuryRule) SetRevealStart(value types.DateTime) {
+	rule.Set(VoteJuryRuleFieldRevealStart, value)
+}
+
+func (rule *VoteJuryRule) RevealMinutes() int {
+	if minutes := rule.GetInt(VoteJuryRuleFieldRevealMinutes); minutes > 0 {
+		return minutes
+	}
+	return 60
+}
+
+func (rule *VoteJuryRule) SetRevealMinutes(value int) {
+	rule.Set(VoteJuryRuleFieldRevealMinutes, value)
+}
+
+// ReminderOffsets 催投提醒时间点，距截止时间的分钟数，未配置时不自动提醒
+func (rule *VoteJuryRule) ReminderOffsets() []int {
+	var offsets []int
+	if value := rule.GetString(VoteJuryRuleFieldReminderOffsets); value != "" {
+		_ = json.Unmarshal([]byte(value), &offsets)
+	}
+	return offsets
+}
+
+func (rule *VoteJuryRule) SetReminderOffsets(value []int) {
+	rule.Set(VoteJuryRuleFieldReminderOffsets, value)
+}
+
+func (rule *VoteJuryRule) ReminderTemplate() string {
+	return rule.GetString(VoteJuryRuleFieldReminderTemplate)
+}
+
+func (rule *VoteJuryRule) SetReminderTemplate(value string) {
+	rule.Set(VoteJuryRuleFieldReminderTemplate, value)
+}
+
+func (rule *VoteJuryRule) RevealTemplate() string {
+	return rule.GetString(VoteJuryRuleFieldRevealTemplate)
+}
+
+func (rule *VoteJuryRule) SetRevealTemplate(value string) {
+	rule.Set(VoteJuryRuleFieldRevealTemplate, value)
+}
+
+func (rule *VoteJuryRule) MinParticipationRate() float64 {
+	return rule.GetFloat(VoteJuryRuleFieldMinParticipationRate)
+}
+
+func (rule *VoteJuryRule) SetMinParticipationRate(value float64) {
+	rule.Set(VoteJuryRuleFieldMinParticipationRate, value)
+}
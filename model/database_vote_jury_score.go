@@ -0,0 +1,90 @@
+package model
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	DbNameVoteJuryScores         = "voteJuryScores" // 评审团评分表
+	VoteJuryScoreFieldVoteId     = "voteId"         // 关联投票ID
+	VoteJuryScoreFieldRound      = "round"          // 评审轮次
+	VoteJuryScoreFieldFromUserId = "fromUserId"     // 评审团成员用户ID
+	VoteJuryScoreFieldToUserId   = "toUserId"       // 被评分用户ID
+	VoteJuryScoreFieldScores     = "scores"         // 各评分项得分 JSON 评分项key与分数映射
+	VoteJuryScoreFieldComment    = "comment"        // 评分备注
+	VoteJuryScoreFieldCreated    = "created"        // 创建时间
+	VoteJuryScoreFieldUpdated    = "updated"        // 更新时间
+)
+
+// VoteJuryScore wrapper type
+type VoteJuryScore struct {
+	core.BaseRecordProxy
+}
+
+func NewVoteJuryScore(record *core.Record) *VoteJuryScore {
+	score := new(VoteJuryScore)
+	score.SetProxyRecord(record)
+	return score
+}
+
+func NewVoteJuryScoreFromCollection(collection *core.Collection) *VoteJuryScore {
+	record := core.NewRecord(collection)
+	return NewVoteJuryScore(record)
+}
+
+func (score *VoteJuryScore) VoteId() string {
+	return score.GetString(VoteJuryScoreFieldVoteId)
+}
+
+func (score *VoteJuryScore) SetVoteId(value string) {
+	score.Set(VoteJuryScoreFieldVoteId, value)
+}
+
+func (score *VoteJuryScore) Round() int {
+	return score.GetInt(VoteJuryScoreFieldRound)
+}
+
+func (score *VoteJuryScore) SetRound(value int) {
+	score.Set(VoteJuryScoreFieldRound, value)
+}
+
+func (score *VoteJuryScore) FromUserId() string {
+	return score.GetString(VoteJuryScoreFieldFromUserId)
+}
+
+func (score *VoteJuryScore) SetFromUserId(value string) {
+	score.Set(VoteJuryScoreFieldFromUserId, value)
+}
+
+func (score *VoteJuryScore) ToUserId() string {
+	return score.GetString(VoteJuryScoreFieldToUserId)
+}
+
+func (score *VoteJuryScore) SetToUserId(value string) {
+	score.Set(VoteJuryScoreFieldToUserId, value)
+}
+
+func (score *VoteJuryScore) Scores() string {
+	return score.GetString(VoteJuryScoreFieldScores)
+}
+
+func (score *VoteJuryScore) SetScores(value string) {
+	score.Set(VoteJuryScoreFieldScores, value)
+}
+
+func (score *VoteJuryScore) Comment() string {
+	return score.GetString(VoteJuryScoreFieldComment)
+}
+
+func (score *VoteJuryScore) SetComment(value string) {
+	score.Set(VoteJuryScoreFieldComment, value)
+}
+
+func (score *VoteJuryScore) Created() types.DateTime {
+	return score.GetDateTime(VoteJuryScoreFieldCreated)
+}
+
+func (score *VoteJuryScore) Updated() types.DateTime {
+	return score.GetDateTime(VoteJuryScoreFieldUpdated)
+}
@@ -0,0 +1,113 @@
+//go:generate go-enum --marshal --names --values --ptr --mustparse
+package model
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	DbNameVoteJuryStatusLogs         = "voteJuryStatusLogs" // 评审团状态变更日志表
+	VoteJuryStatusLogFieldVoteId     = "voteId"             // 关联投票ID
+	VoteJuryStatusLogFieldFromStatus = "fromStatus"         // 变更前状态
+	VoteJuryStatusLogFieldToStatus   = "toStatus"           // 变更后状态
+	VoteJuryStatusLogFieldRound      = "round"              // 变更时的轮次
+	VoteJuryStatusLogFieldTrigger    = "trigger"            // 触发方式
+	VoteJuryStatusLogFieldActorId    = "actorId"            // 操作管理员用户ID，定时任务触发时为空
+	VoteJuryStatusLogFieldReason     = "reason"             // 变更原因
+	VoteJuryStatusLogFieldSnapshot   = "snapshot"           // 回滚算票时被删除的评审结果 JSON
+	VoteJuryStatusLogFieldCreated    = "created"            // 创建时间
+)
+
+// VoteJuryStatusTrigger 评审团状态变更触发方式
+/*
+ENUM(
+manual   // 管理员手动
+schedule // 定时任务
+)
+*/
+type VoteJuryStatusTrigger string
+
+// VoteJuryStatusLog wrapper type
+type VoteJuryStatusLog struct {
+	core.BaseRecordProxy
+}
+
+func NewVoteJuryStatusLog(record *core.Record) *VoteJuryStatusLog {
+	log := new(VoteJuryStatusLog)
+	log.SetProxyRecord(record)
+	return log
+}
+
+func NewVoteJuryStatusLogFromCollection(collection *core.Collection) *VoteJuryStatusLog {
+	record := core.NewRecord(collection)
+	return NewVoteJuryStatusLog(record)
+}
+
+func (log *VoteJuryStatusLog) VoteId() string {
+	return log.GetString(VoteJuryStatusLogFieldVoteId)
+}
+
+func (log *VoteJuryStatusLog) SetVoteId(value string) {
+	log.Set(VoteJuryStatusLogFieldVoteId, value)
+}
+
+func (log *VoteJuryStatusLog) FromStatus() VoteJuryRuleStatus {
+	return VoteJuryRuleStatus(log.GetString(VoteJuryStatusLogFieldFromStatus))
+}
+
+func (log *VoteJuryStatusLog) SetFromStatus(value VoteJuryRuleStatus) {
+	log.Set(VoteJuryStatusLogFieldFromStatus, value)
+}
+
+func (log *VoteJuryStatusLog) ToStatus() VoteJuryRuleStatus {
+	return VoteJuryRuleStatus(log.GetString(VoteJuryStatusLogFieldToStatus))
+}
+
+func (log *VoteJuryStatusLog) SetToStatus(value VoteJuryRuleStatus) {
+	log.Set(VoteJuryStatusLogFieldToStatus, value)
+}
+
+func (log *VoteJuryStatusLog) Round() int {
+	return log.GetInt(VoteJuryStatusLogFieldRound)
+}
+
+func (log *VoteJuryStatusLog) SetRound(value int) {
+	log.Set(VoteJuryStatusLogFieldRound, value)
+}
+
+func (log *VoteJuryStatusLog) Trigger() VoteJuryStatusTrigger {
+	return MustParseVoteJuryStatusTrigger(log.GetString(VoteJuryStatusLogFieldTrigger))
+}
+
+func (log *VoteJuryStatusLog) SetTrigger(value VoteJuryStatusTrigger) {
+	log.Set(VoteJuryStatusLogFieldTrigger, value)
+}
+
+func (log *VoteJuryStatusLog) ActorId() string {
+	return log.GetString(VoteJuryStatusLogFieldActorId)
+}
+
+func (log *VoteJuryStatusLog) SetActorId(value string) {
+	log.Set(VoteJuryStatusLogFieldActorId, value)
+}
+
+func (log *VoteJuryStatusLog) Reason() string {
+	return log.GetString(VoteJuryStatusLogFieldReason)
+}
+
+func (log *VoteJuryStatusLog) SetReason(value string) {
+	log.Set(VoteJuryStatusLogFieldReason, value)
+}
+
+func (log *VoteJuryStatusLog) Snapshot() string {
+	return log.GetString(VoteJuryStatusLogFieldSnapshot)
+}
+
+func (log *VoteJuryStatusLog) SetSnapshot(value string) {
+	log.Set(VoteJuryStatusLogFieldSnapshot, value)
+}
+
+func (log *VoteJuryStatusLog) Created() types.DateTime {
+	return log.GetDateTime(VoteJuryStatusLogFieldCreated)
+}
@@ -0,0 +1,96 @@
+// Code generated by go-enum DO NOT EDIT.
+// Version:
+// Revision:
+// Build Date:
+// Built By:
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// VoteJuryStatusTriggerManual is a VoteJuryStatusTrigger of type manual.
+	// 管理员手动
+	VoteJuryStatusTriggerManual VoteJuryStatusTrigger = "manual"
+	// VoteJuryStatusTriggerSchedule is a VoteJuryStatusTrigger of type schedule.
+	// 定时任务
+	VoteJuryStatusTriggerSchedule VoteJuryStatusTrigger = "schedule"
+)
+
+var ErrInvalidVoteJuryStatusTrigger = fmt.Errorf("not a valid VoteJuryStatusTrigger, try [%s]", strings.Join(_VoteJuryStatusTriggerNames, ", "))
+
+var _VoteJuryStatusTriggerNames = []string{
+	string(VoteJuryStatusTriggerManual),
+	string(VoteJuryStatusTriggerSchedule),
+}
+
+// VoteJuryStatusTriggerNames returns a list of possible string values of VoteJuryStatusTrigger.
+func VoteJuryStatusTriggerNames() []string {
+	tmp := make([]string, len(_VoteJuryStatusTriggerNames))
+	copy(tmp, _VoteJuryStatusTriggerNames)
+	return tmp
+}
+
+// VoteJuryStatusTriggerValues returns a list of the values for VoteJuryStatusTrigger
+func VoteJuryStatusTriggerValues() []VoteJuryStatusTrigger {
+	return []VoteJuryStatusTrigger{
+		VoteJuryStatusTriggerManual,
+		VoteJuryStatusTriggerSchedule,
+	}
+}
+
+// String implements the Stringer interface.
+func (x VoteJuryStatusTrigger) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x VoteJuryStatusTrigger) IsValid() bool {
+	_, err := ParseVoteJuryStatusTrigger(string(x))
+	return err == nil
+}
+
+var _VoteJuryStatusTriggerValue = map[string]VoteJuryStatusTrigger{
+	"manual":   VoteJuryStatusTriggerManual,
+	"schedule": VoteJuryStatusTriggerSchedule,
+}
+
+// ParseVoteJuryStatusTrigger attempts to convert a string to a VoteJuryStatusTrigger.
+func ParseVoteJuryStatusTrigger(name string) (VoteJuryStatusTrigger, error) {
+	if x, ok := _VoteJuryStatusTriggerValue[name]; ok {
+		return x, nil
+	}
+	return VoteJuryStatusTrigger(""), fmt.Errorf("%s is %w", name, ErrInvalidVoteJuryStatusTrigger)
+}
+
+// MustParseVoteJuryStatusTrigger converts a string to a VoteJuryStatusTrigger, and panics if is not valid.
+func MustParseVoteJuryStatusTrigger(name string) VoteJuryStatusTrigger {
+	val, err := ParseVoteJuryStatusTrigger(name)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+func (x VoteJuryStatusTrigger) Ptr() *VoteJuryStatusTrigger {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x VoteJuryStatusTrigger) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *VoteJuryStatusTrigger) UnmarshalText(text []byte) error {
+	tmp, err := ParseVoteJuryStatusTrigger(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
@@ -7,14 +7,21 @@ import (
 )
 
 const (
-	DbNameVoteLogs          = "voteLogs"   // 投票日志表
-	VoteLogsFieldVoteId     = "voteId"     // 关联投票ID
-	VoteLogsFieldFromUserId = "fromUserId" // 投票用户ID
-	VoteLogsFieldToUserId   = "toUserId"   // 被投票用户ID
-	VoteLogsFieldComment    = "comment"    // 投票备注
-	VoteLogsFieldValid      = "valid"      // 投票有效性
-	VoteLogsFieldCreated    = "created"    // 创建时间
-	VoteLogsFieldUpdated    = "updated"    // 更新时间
+	DbNameVoteLogs             = "voteLogs"      // 投票日志表
+	VoteLogsFieldVoteId        = "voteId"        // 关联投票ID
+	VoteLogsFieldFromUserId    = "fromUserId"    // 投票用户ID
+	VoteLogsFieldToUserId      = "toUserId"      // 被投票用户ID
+	VoteLogsFieldComment       = "comment"       // 投票备注
+	VoteLogsFieldValid         = "valid"         // 投票有效性
+	VoteLogsFieldIpHash        = "ipHash"        // 投票来源IP哈希
+	VoteLogsFieldUaHash        = "uaHash"        // 投票来源User-Agent指纹
+	VoteLogsFieldInvalidReason = "invalidReason" // 判定无效的原因
+	VoteLogsFieldInvalidatedBy = "invalidatedBy" // 作废投票的管理员ID
+	VoteLogsFieldInvalidatedAt = "invalidatedAt" // 作废时间
+	VoteLogsFieldWeight        = "weight"        // 投票时计算的权重
+	VoteLogsFieldReceipt       = "receipt"       // 投票回执
+	VoteLogsFieldCreated       = "created"       // 创建时间
+	VoteLogsFieldUpdated       = "updated"       // 更新时间
 )
 
 // VoteLogValid 投票日志有效性
@@ -82,6 +89,67 @@ func (voteLog *VoteLog) SetValid(value VoteLogValid) {
 	voteLog.Set(VoteLogsFieldValid, value)
 }
 
+func (voteLog *VoteLog) IpHash() string {
+	return voteLog.GetString(VoteLogsFieldIpHash)
+}
+
+func (voteLog *VoteLog) SetIpHash(value string) {
+	voteLog.Set(VoteLogsFieldIpHash, value)
+}
+
+func (voteLog *VoteLog) UaHash() string {
+	return voteLog.GetString(VoteLogsFieldUaHash)
+}
+
+func (voteLog *VoteLog) SetUaHash(value string) {
+	voteLog.Set(VoteLogsFieldUaHash, value)
+}
+
+func (voteLog *VoteLog) InvalidReason() string {
+	return voteLog.GetString(VoteLogsFieldInvalidReason)
+}
+
+func (voteLog *VoteLog) SetInvalidReason(value string) {
+	voteLog.Set(VoteLogsFieldInvalidReason, value)
+}
+
+func (voteLog *VoteLog) InvalidatedBy() string {
+	return voteLog.GetString(VoteLogsFieldInvalidatedBy)
+}
+
+func (voteLog *VoteLog) SetInvalidatedBy(value string) {
+	voteLog.Set(VoteLogsFieldInvalidatedBy, value)
+}
+
+func (voteLog *VoteLog) InvalidatedAt() types.DateTime {
+	return voteLog.GetDateTime(VoteLogsFieldInvalidatedAt)
+}
+
+func (voteLog *VoteLog) SetInvalidatedAt(value types.DateTime) {
+	voteLog.Set(VoteLogsFieldInvalidatedAt, value)
+}
+
+// Weight 投票权重，未记录权重的历史投票按1计算
+func (voteLog *VoteLog) Weight() float64 {
+	weight := voteLog.GetFloat(VoteLogsFieldWeight)
+	if weight <= 0 {
+		return 1
+	}
+	return weight
+}
+
+func (voteLog *VoteLog) SetWeight(value float64) {
+	voteLog.Set(VoteLogsFieldWeight, value)
+}
+
+func (voteLog *VoteLog) Receipt() string {
+	return voteLog.GetString(VoteLogsFieldReceipt)
+}
+
+func (voteLog *VoteLog) SetReceipt(value string) {
+	voteLog.Set(VoteLogsFieldReceipt, value)
+}
+
 func (voteLog *VoteLog) Created() types.DateTime {
 	return voteLog.GetDateTime(VoteLogsFieldCreated)
 }
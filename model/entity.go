@@ -1 +1,139 @@
 package model
+
+// VoteWeightTier 权重档位，取值达到 Min 即使用该档位权重
+type VoteWeightTier struct {
+	Min    int     `json:"min"`
+	Weight float64 `json:"weight"`
+}
+
+// VoteWeightConfig 投票权重配置
+type VoteWeightConfig struct {
+	Default float64            `json:"default"` // 未命中任何规则时的权重，缺省为1
+	Tiers   []VoteWeightTier   `json:"tiers"`   // 账号注册天数或历史参与次数的档位
+	Medals  map[string]float64 `json:"medals"`  // 勋章ID与权重映射，持有多个时取最大值
+}
+
+// VoteJurySelectionCandidate 遴选候选人及其排序依据
+type VoteJurySelectionCandidate struct {
+	UserId    string  `json:"userId"`
+	ApplyId   string  `json:"applyId"`
+	Returning bool    `json:"returning"` // 是否担任过往届评审
+	Score     float64 `json:"score"`     // 资格分，仅 score 模式使用
+	Hash      string  `json:"hash"`      // sha256(seed:userId)，用于复核随机顺序
+
+	// 资格分的计算依据，按遴选时间冻结，复核时无需重新查询
+	PastCount    int    `json:"pastCount"`    // 往届担任评审次数
+	RegisteredAt string `json:"registeredAt"` // 注册时间
+}
+
+// VoteJurySelection 评审团遴选记录，公示期间供复核
+type VoteJurySelection struct {
+	Mode       VoteJurySelectMode           `json:"mode"`
+	Seed       string                       `json:"seed"`
+	SeedHash   string                       `json:"seedHash"`   // 申请阶段公示的种子承诺，为空表示种子未提前承诺
+	Seats      int                          `json:"seats"`      // 本次遴选的空余席位
+	Quota      int                          `json:"quota"`      // 往届评审配额
+	Candidates []VoteJurySelectionCandidate `json:"candidates"` // 排序后的候选人
+	Selected   []string                     `json:"selected"`
+	Waitlist   []string                     `json:"waitlist"`
+	Excluded   []string                     `json:"excluded"` // 因利益冲突被排除的申请人
+	ExecutedAt string                       `json:"executedAt"`
+}
+
+// VoteJuryRankEntry 评审团已确定的名次
+type VoteJuryRankEntry struct {
+	UserId string  `json:"userId"`
+	Rank   int     `json:"rank"`
+	Votes  float64 `json:"votes"` // 确定名次所在轮次的得票数，评分模式下为加权总分
+	Round  int     `json:"round"` // 确定名次的轮次
+}
+
+// VoteJuryRankGroup 跨名次平票、需要加赛的候选人
+type VoteJuryRankGroup struct {
+	Position int      `json:"position"` // 该组争夺的起始名次
+	UserIds  []string `json:"userIds"`
+}
+
+// VoteJuryRanking 评审团名次结算状态，每轮算票后累积
+type VoteJuryRanking struct {
+	Winners int                 `json:"winners"`
+	Settled []VoteJuryRankEntry `json:"settled"` // 按名次排列
+	Pending []VoteJuryRankGroup `json:"pending"` // 进入下一轮加赛的分组
+}
+
+// VoteJuryCriterion 评审团评分项
+type VoteJuryCriterion struct {
+	Key    string  `json:"key"`
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+// VoteJuryCriterionStat 单个评分项的汇总
+type VoteJuryCriterionStat struct {
+	Score  float64 `json:"score"`  // 汇总后的得分
+	Median float64 `json:"median"` // 中位数
+	Count  int     `json:"count"`  // 参与评分的评审数
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+// VoteJuryScoreOutlier 偏离其他评审过多的评分
+type VoteJuryScoreOutlier struct {
+	JurorId   string  `json:"jurorId"`
+	Criterion string  `json:"criterion"`
+	Score     float64 `json:"score"`
+	Median    float64 `json:"median"`
+}
+
+// VoteJuryScoreBreakdown 候选人评分明细
+type VoteJuryScoreBreakdown struct {
+	Total    float64                          `json:"total"` // 按权重加总的得分
+	Criteria map[string]VoteJuryCriterionStat `json:"criteria"`
+	Outliers []VoteJuryScoreOutlier           `json:"outliers"`
+}
+
+// RewardPlanItem 奖励发放计划中的单个用户
+type RewardPlanItem struct {
+	UserId   string           `json:"userId"`
+	Name     string           `json:"name"`
+	Nickname string           `json:"nickname"`
+	Rank     int              `json:"rank"` // 参与奖为0
+	Tied     int              `json:"tied"` // 并列名次的人数，未并列为1
+	Point    int              `json:"point"`
+	Medals   []RewardMedal    `json:"medals,omitempty"`
+	Score    float64          `json:"score"` // 确定名次时的得票数
+	Round    int              `json:"round"` // 评审团确定名次的轮次
+	Source   RewardRankSource `json:"source"`
+	Memo     string           `json:"memo"` // 发放时附加交易单号
+	Paid     bool             `json:"paid"` // 生成计划时是否已成功发放
+}
+
+// RewardMedal 作为奖励发放的勋章
+type RewardMedal struct {
+	MedalId    string `json:"medalId"`    // 摸鱼派勋章ID
+	ExpireDays int    `json:"expireDays"` // 发放后的有效天数，0表示永不过期
+	Data       string `json:"data"`       // 勋章附加数据
+}
+
+// VoteFingerprintConfig 投票来源指纹配置，IP 使用该密钥做 HMAC，避免通过穷举还原
+type VoteFingerprintConfig struct {
+	Secret string `json:"secret"`
+}
+
+// PayoutApprovalConfig 大额发放审批阈值，超过任一阈值的发放需要另一位管理员审批，0表示不限制
+// 同一管理员在统计窗口内未经审批的发放会与本次发放合并计算，避免拆分成小批次绕过审批
+type PayoutApprovalConfig struct {
+	MaxPoints     int `json:"maxPoints"`     // 本次与窗口内累计发放的积分总额
+	MaxRecipients int `json:"maxRecipients"` // 本次与窗口内累计发放的人次
+	WindowHours   int `json:"windowHours"`   // 累计统计窗口（小时），0 表示 24 小时
+}
+
+// PayoutApprovalItem 审批时冻结的单条发放内容
+type PayoutApprovalItem struct {
+	Id     string `json:"id,omitempty"` // 积分记录ID，奖励发放为空
+	UserId string `json:"userId"`
+	Point  int    `json:"point"`
+	Memo   string `json:"memo,omitempty"`
+}
@@ -4,7 +4,9 @@ package model
 // ConfigKey
 /*
 ENUM(
-fishpi // 摸鱼派
+fishpi           // 摸鱼派
+payout_approval  // 大额发放审批阈值
+vote_fingerprint // 投票来源指纹密钥
 )
 */
 type ConfigKey string
@@ -15,12 +15,20 @@ const (
 	// ConfigKeyFishpi is a ConfigKey of type fishpi.
 	// 摸鱼派
 	ConfigKeyFishpi ConfigKey = "fishpi"
+	// ConfigKeyPayoutApproval is a ConfigKey of type payout_approval.
+	// 大额发放审批阈值
+	ConfigKeyPayoutApproval ConfigKey = "payout_approval"
+	// ConfigKeyVoteFingerprint is a ConfigKey of type vote_fingerprint.
+	// 投票来源指纹密钥
+	ConfigKeyVoteFingerprint ConfigKey = "vote_fingerprint"
 )
 
 var ErrInvalidConfigKey = fmt.Errorf("not a valid ConfigKey, try [%s]", strings.Join(_ConfigKeyNames, ", "))
 
 var _ConfigKeyNames = []string{
 	string(ConfigKeyFishpi),
+	string(ConfigKeyPayoutApproval),
+	string(ConfigKeyVoteFingerprint),
 }
 
 // ConfigKeyNames returns a list of possible string values of ConfigKey.
@@ -34,6 +42,8 @@ func ConfigKeyNames() []string {
 func ConfigKeyValues() []ConfigKey {
 	return []ConfigKey{
 		ConfigKeyFishpi,
+		ConfigKeyPayoutApproval,
+		ConfigKeyVoteFingerprint,
 	}
 }
 
@@ -50,7 +60,9 @@ func (x ConfigKey) IsValid() bool {
 }
 
 var _ConfigKeyValue = map[string]ConfigKey{
-	"fishpi": ConfigKeyFishpi,
+	"fishpi":           ConfigKeyFishpi,
+	"payout_approval":  ConfigKeyPayoutApproval,
+	"vote_fingerprint": ConfigKeyVoteFingerprint,
 }
 
 // ParseConfigKey attempts to convert a string to a ConfigKey.
@@ -0,0 +1,195 @@
+// Package xlsx 读取 xlsx 文件第一个工作表的单元格文本，只支持导入所需的最小子集
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNoSheet = errors.New("xlsx 文件中没有工作表")
+)
+
+type workbook struct {
+	Sheets []struct {
+		RelId string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type relationships struct {
+	Items []struct {
+		Id     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type sharedStrings struct {
+	Items []richText `xml:"si"`
+}
+
+// richText 单元格文本，富文本时由多个 r 片段组成
+type richText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (text richText) String() string {
+	if len(text.Runs) == 0 {
+		return text.Text
+	}
+	var builder strings.Builder
+	for _, run := range text.Runs {
+		builder.WriteString(run.Text)
+	}
+	return builder.String()
+}
+
+type worksheet struct {
+	Rows []struct {
+		Index int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Value  string   `xml:"v"`
+			Inline richText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadFirstSheet 读取第一个工作表，返回按行列排列的单元格文本，空行保留为空切片
+func ReadFirstSheet(data []byte) ([][]string, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		files[file.Name] = file
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared sharedStrings
+	if file, ok := files["xl/sharedStrings.xml"]; ok {
+		if err = decode(file, &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	file, ok := files[sheetPath]
+	if !ok {
+		return nil, ErrNoSheet
+	}
+	var sheet worksheet
+	if err = decode(file, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for i, row := range sheet.Rows {
+		index := row.Index
+		if index == 0 {
+			index = i + 1
+		}
+		for len(rows) < index {
+			rows = append(rows, nil)
+		}
+
+		var cells []string
+		for j, cell := range row.Cells {
+			column := j
+			if cell.Ref != "" {
+				column = columnIndex(cell.Ref)
+			}
+			for len(cells) <= column {
+				cells = append(cells, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				n, convErr := strconv.Atoi(cell.Value)
+				if convErr == nil && n >= 0 && n < len(shared.Items) {
+					cells[column] = shared.Items[n].String()
+				}
+			case "inlineStr":
+				cells[column] = cell.Inline.String()
+			default:
+				cells[column] = cell.Value
+			}
+		}
+		rows[index-1] = cells
+	}
+
+	return rows, nil
+}
+
+// firstSheetPath 通过 workbook 与其关系文件找到第一个工作表的路径
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	file, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", ErrNoSheet
+	}
+	var book workbook
+	if err := decode(file, &book); err != nil {
+		return "", err
+	}
+	if len(book.Sheets) == 0 {
+		return "", ErrNoSheet
+	}
+
+	if file, ok = files["xl/_rels/workbook.xml.rels"]; ok {
+		var rels relationships
+		if err := decode(file, &rels); err != nil {
+			return "", err
+		}
+		for _, rel := range rels.Items {
+			if rel.Id != book.Sheets[0].RelId {
+				continue
+			}
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+			return path.Join("xl", rel.Target), nil
+		}
+	}
+
+	return "xl/worksheets/sheet1.xml", nil
+}
+
+func decode(file *zip.File, v any) error {
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(content, v)
+}
+
+// columnIndex 将单元格引用（如 AB12）的列转换为从 0 开始的下标
+func columnIndex(ref string) int {
+	index := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A'+1)
+	}
+	return index - 1
+}
@@ -1,21 +1,30 @@
 package events
 
 import (
+	"bless-activity/service/permission"
+	"bless-activity/service/vote_result"
 	"log/slog"
 
 	"github.com/pocketbase/pocketbase/core"
 )
 
 type Service struct {
-	app core.App
+	app        core.App
+	voteResult *vote_result.Service
+	permission *permission.Service
 
 	logger *slog.Logger
 }
 
-func NewService(app core.App) *Service {
+func NewService(app core.App, voteResult *vote_result.Service, permission *permission.Service) *Service {
 	service := &Service{
-		app:    app,
-		logger: app.Logger().WithGroup("service.events"),
+		app:        app,
+		voteResult: voteResult,
+		permission: permission,
+		logger:     app.Logger().WithGroup("service.events"),
 	}
+
+	service.registerHooks()
+
 	return service
 }
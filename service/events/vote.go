@@ -0,0 +1,351 @@
+package events
+
+import (
+	"bless-activity/model"
+	"bless-activity/service/vote_jury"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/routine"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
+)
+
+const (
+	voteTopicPrefix      = "vote/"
+	voteAdminTopicSuffix = "/admin"
+)
+
+// 推送消息类型
+const (
+	MessageTypeTally         = "tally"          // 票数增量
+	MessageTypeVoteLog       = "vote_log"       // 投票明细（仅管理员）
+	MessageTypePublished     = "published"      // 结果已公布
+	MessageTypeJuryProgress  = "jury_progress"  // 评审团投票进度
+	MessageTypeJuryLog       = "jury_log"       // 评审团投票明细（仅管理员）
+	MessageTypeJuryStatus    = "jury_status"    // 评审团状态切换
+	MessageTypeJuryRoundDone = "jury_round_end" // 评审团轮次结果
+	MessageTypeJuryRollback  = "jury_rollback"  // 评审团轮次结果已回滚
+)
+
+// VoteTopic 投票公开频道，只推送汇总数据
+func VoteTopic(voteId string) string {
+	return voteTopicPrefix + voteId
+}
+
+// VoteAdminTopic 投票管理员频道，额外推送逐票明细
+func VoteAdminTopic(voteId string) string {
+	return voteTopicPrefix + voteId + voteAdminTopicSuffix
+}
+
+// parseVoteTopic 解析订阅频道，返回投票ID和是否为管理员频道
+func parseVoteTopic(subscription string) (voteId string, admin bool, ok bool) {
+	topic, _, _ := strings.Cut(subscription, "?")
+	if !strings.HasPrefix(topic, voteTopicPrefix) {
+		return "", false, false
+	}
+
+	voteId = strings.TrimPrefix(topic, voteTopicPrefix)
+	if strings.HasSuffix(voteId, voteAdminTopicSuffix) {
+		voteId = strings.TrimSuffix(voteId, voteAdminTopicSuffix)
+		admin = true
+	}
+	if voteId == "" || strings.Contains(voteId, "/") {
+		return "", false, false
+	}
+
+	return voteId, admin, true
+}
+
+func (service *Service) registerHooks() {
+	service.app.OnRealtimeSubscribeRequest().BindFunc(service.authorizeVoteSubscriptions)
+
+	service.app.OnRecordAfterCreateSuccess(model.DbNameVoteLogs).BindFunc(service.onVoteLogChanged("create"))
+	service.app.OnRecordAfterUpdateSuccess(model.DbNameVoteLogs).BindFunc(service.onVoteLogChanged("update"))
+	service.app.OnRecordAfterDeleteSuccess(model.DbNameVoteLogs).BindFunc(service.onVoteLogChanged("delete"))
+	service.app.OnRecordAfterUpdateSuccess(model.DbNameVotes).BindFunc(service.onVoteUpdated)
+
+	service.app.OnRecordAfterCreateSuccess(model.DbNameVoteJuryLogs).BindFunc(service.onJuryLogChanged("create"))
+	service.app.OnRecordAfterDeleteSuccess(model.DbNameVoteJuryLogs).BindFunc(service.onJuryLogChanged("delete"))
+	service.app.OnRecordAfterUpdateSuccess(model.DbNameVoteJuryRules).BindFunc(service.onJuryRuleUpdated)
+	service.app.OnRecordAfterCreateSuccess(model.DbNameVoteJuryResults).BindFunc(service.onJuryResultCreated)
+	service.app.OnRecordAfterDeleteSuccess(model.DbNameVoteJuryResults).BindFunc(service.onJuryResultDeleted)
+}
+
+// authorizeVoteSubscriptions 校验投票频道订阅权限
+func (service *Service) authorizeVoteSubscriptions(event *core.RealtimeSubscribeRequestEvent) error {
+	for _, subscription := range event.Subscriptions {
+		voteId, admin, ok := parseVoteTopic(subscription)
+		if !ok {
+			continue
+		}
+
+		vote := new(model.Vote)
+		if err := service.app.RecordQuery(model.DbNameVotes).Where(dbx.HashExp{model.CommonFieldId: voteId}).One(vote); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return event.NotFoundError("投票不存在", nil)
+			}
+			return event.InternalServerError("获取投票失败", err)
+		}
+
+		if admin && !service.isVoteAdmin(event.Auth, voteId) {
+			return event.ForbiddenError("无权订阅该投票的管理员频道", nil)
+		}
+	}
+
+	return event.Next()
+}
+
+// isVoteAdmin 拥有投票所属活动的评审团或投票管理权限时可以查看逐票明细
+func (service *Service) isVoteAdmin(auth *core.Record, voteId string) bool {
+	return service.permission.HasForVote(auth, model.PermissionJuryManage, voteId) ||
+		service.permission.HasForVote(auth, model.PermissionVoteManage, voteId)
+}
+
+// broadcast 向订阅了指定频道的客户端推送消息
+func (service *Service) broadcast(topic string, payload map[string]any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		service.logger.Error("序列化推送消息失败", slog.String("topic", topic), slog.Any("err", err))
+		return
+	}
+
+	for _, client := range service.app.SubscriptionsBroker().Clients() {
+		for subscription := range client.Subscriptions(topic) {
+			if name, _, _ := strings.Cut(subscription, "?"); name != topic {
+				continue
+			}
+
+			message := subscriptions.Message{
+				Name: subscription,
+				Data: data,
+			}
+			routine.FireAndForget(func() {
+				client.Send(message)
+			})
+		}
+	}
+}
+
+func (service *Service) onVoteLogChanged(action string) func(event *core.RecordEvent) error {
+	return func(event *core.RecordEvent) error {
+		voteLog := model.NewVoteLog(event.Record)
+		voteId := voteLog.VoteId()
+
+		service.broadcast(VoteAdminTopic(voteId), map[string]any{
+			"type":   MessageTypeVoteLog,
+			"voteId": voteId,
+			"action": action,
+			"voteLog": map[string]any{
+				"id":         voteLog.Id,
+				"fromUserId": voteLog.FromUserId(),
+				"toUserId":   voteLog.ToUserId(),
+				"comment":    voteLog.Comment(),
+				"valid":      voteLog.GetString(model.VoteLogsFieldValid),
+				"created":    voteLog.Created(),
+			},
+		})
+
+		valid := voteLog.GetString(model.VoteLogsFieldValid) == string(model.VoteLogValidValid)
+		sign := 0
+		switch action {
+		case "create":
+			if valid {
+				sign = 1
+			}
+		case "delete":
+			if valid {
+				sign = -1
+			}
+		case "update":
+			wasValid := event.Record.Original().GetString(model.VoteLogsFieldValid) == string(model.VoteLogValidValid)
+			if valid && !wasValid {
+				sign = 1
+			} else if !valid && wasValid {
+				sign = -1
+			}
+		}
+		if sign == 0 || !service.resultVisible(voteId) {
+			return event.Next()
+		}
+
+		stats, total, err := service.voteResult.Tally(voteId)
+		if err != nil {
+			service.logger.Error("推送票数时统计失败", slog.String("voteId", voteId), slog.Any("err", err))
+			return event.Next()
+		}
+
+		service.broadcast(VoteTopic(voteId), map[string]any{
+			"type":     MessageTypeTally,
+			"voteId":   voteId,
+			"toUserId": voteLog.ToUserId(),
+			"delta":    float64(sign) * voteLog.Weight(), // 与 count、total 一样按权重计
+			"count":    stats[voteLog.ToUserId()],
+			"total":    total,
+		})
+
+		return event.Next()
+	}
+}
+
+// resultVisible 非管理员当前能否查看投票结果，与结果查询接口的可见性一致
+func (service *Service) resultVisible(voteId string) bool {
+	vote := new(model.Vote)
+	if err := service.app.RecordQuery(model.DbNameVotes).Where(dbx.HashExp{model.CommonFieldId: voteId}).One(vote); err != nil {
+		service.logger.Error("推送结果时获取投票失败", slog.String("voteId", voteId), slog.Any("err", err))
+		return false
+	}
+	return vote.ResultVisible(time.Now())
+}
+
+func (service *Service) onVoteUpdated(event *core.RecordEvent) error {
+	vote := model.NewVote(event.Record)
+	if !vote.Published() || event.Record.Original().GetBool(model.VotesFieldPublished) {
+		return event.Next()
+	}
+
+	snapshot := map[string]any{}
+	if err := json.Unmarshal([]byte(vote.ResultSnapshot()), &snapshot); err != nil {
+		service.logger.Error("解析结果快照失败", slog.String("voteId", vote.Id), slog.Any("err", err))
+	}
+
+	service.broadcast(VoteTopic(vote.Id), map[string]any{
+		"type":     MessageTypePublished,
+		"voteId":   vote.Id,
+		"snapshot": snapshot,
+	})
+
+	return event.Next()
+}
+
+func (service *Service) onJuryLogChanged(action string) func(event *core.RecordEvent) error {
+	return func(event *core.RecordEvent) error {
+		juryLog := model.NewVoteJuryLog(event.Record)
+		voteId := juryLog.VoteId()
+
+		service.broadcast(VoteAdminTopic(voteId), map[string]any{
+			"type":   MessageTypeJuryLog,
+			"voteId": voteId,
+			"action": action,
+			"juryLog": map[string]any{
+				"id":         juryLog.Id,
+				"fromUserId": juryLog.FromUserId(),
+				"toUserId":   juryLog.ToUserId(),
+				"times":      juryLog.Times(),
+				"round":      juryLog.Round(),
+			},
+		})
+
+		progress, err := service.juryProgress(voteId, juryLog.Round())
+		if err != nil {
+			service.logger.Error("统计评审团投票进度失败", slog.String("voteId", voteId), slog.Any("err", err))
+			return event.Next()
+		}
+
+		service.broadcast(VoteTopic(voteId), progress)
+
+		return event.Next()
+	}
+}
+
+// juryProgress 统计评审团某一轮的已投票、未投票人数
+func (service *Service) juryProgress(voteId string, round int) (map[string]any, error) {
+	var juryUsers []*model.VoteJuryUser
+	if err := service.app.RecordQuery(model.DbNameVoteJuryUsers).Where(dbx.HashExp{
+		model.VoteJuryUserFieldVoteId: voteId,
+		model.VoteJuryUserFieldStatus: model.VoteJuryUserStatusApproved,
+	}).All(&juryUsers); err != nil {
+		return nil, err
+	}
+
+	var votedLogs []*model.VoteJuryLog
+	if err := service.app.RecordQuery(model.DbNameVoteJuryLogs).Where(dbx.HashExp{
+		model.VoteJuryLogFieldVoteId: voteId,
+		model.VoteJuryLogFieldRound:  round,
+	}).All(&votedLogs); err != nil {
+		return nil, err
+	}
+
+	votedUsers := make(map[string]bool)
+	for _, log := range votedLogs {
+		votedUsers[log.FromUserId()] = true
+	}
+
+	return map[string]any{
+		"type":    MessageTypeJuryProgress,
+		"voteId":  voteId,
+		"round":   round,
+		"voted":   len(votedUsers),
+		"total":   len(juryUsers),
+		"unvoted": len(juryUsers) - len(votedUsers),
+	}, nil
+}
+
+func (service *Service) onJuryRuleUpdated(event *core.RecordEvent) error {
+	rule := model.NewVoteJuryRule(event.Record)
+	original := model.NewVoteJuryRule(event.Record.Original())
+
+	if rule.Status() == original.Status() && rule.CurrentRound() == original.CurrentRound() && rule.Revealing() == original.Revealing() {
+		return event.Next()
+	}
+
+	service.broadcast(VoteTopic(rule.VoteId()), map[string]any{
+		"type":           MessageTypeJuryStatus,
+		"voteId":         rule.VoteId(),
+		"status":         rule.Status(),
+		"previousStatus": original.Status(),
+		"currentRound":   rule.CurrentRound(),
+		"revealing":      rule.Revealing(),
+	})
+
+	return event.Next()
+}
+
+func (service *Service) onJuryResultCreated(event *core.RecordEvent) error {
+	result := model.NewVoteJuryResult(event.Record)
+
+	results := map[string]any{}
+	if err := json.Unmarshal([]byte(result.Results()), &results); err != nil {
+		service.logger.Error("解析评审结果失败", slog.String("voteId", result.VoteId()), slog.Any("err", err))
+	}
+
+	ranking, err := vote_jury.ParseRanking(result)
+	if err != nil {
+		service.logger.Error("解析评审名次失败", slog.String("voteId", result.VoteId()), slog.Any("err", err))
+	}
+
+	// 结果未公开时只推送给管理员
+	topic := VoteTopic(result.VoteId())
+	if !service.resultVisible(result.VoteId()) {
+		topic = VoteAdminTopic(result.VoteId())
+	}
+
+	service.broadcast(topic, map[string]any{
+		"type":     MessageTypeJuryRoundDone,
+		"voteId":   result.VoteId(),
+		"round":    result.Round(),
+		"results":  results,
+		"continue": result.Continue(),
+		"userIds":  result.UserIds(),
+		"ranking":  ranking,
+	})
+
+	return event.Next()
+}
+
+func (service *Service) onJuryResultDeleted(event *core.RecordEvent) error {
+	result := model.NewVoteJuryResult(event.Record)
+
+	service.broadcast(VoteTopic(result.VoteId()), map[string]any{
+		"type":   MessageTypeJuryRollback,
+		"voteId": result.VoteId(),
+		"round":  result.Round(),
+	})
+
+	return event.Next()
+}
@@ -0,0 +1,466 @@
+package jury_reminder
+
+import (
+	"bless-activity/model"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FishPiOffical/golang-sdk/sdk"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	sendInterval   = 2 * time.Second  // 两条私信之间的间隔，避免触发摸鱼派限流
+	maxPerRun      = 30               // 每次调度最多发送的私信数，剩余的在下一次调度中继续发送
+	manualCooldown = 30 * time.Minute // 手动提醒的冷却时间，冷却期内已提醒过的成员不再重复提醒
+
+	DefaultReminderTemplate = "{nickname}，您好！您担任评审的「{voteName}」第{round}轮投票将于 {deadline} 截止（剩余 {remaining}），您还有 {votes} 票未投出，请尽快完成投票。"
+	DefaultRevealTemplate   = "{nickname}，您好！「{voteName}」第{round}轮已进入揭示阶段，将于 {deadline} 截止（剩余 {remaining}），您提交的投票承诺尚未揭示，未揭示的选票将视为弃权。"
+)
+
+var (
+	ErrNotVoting   = errors.New("评审团不在投票阶段")
+	ErrNoDeadline  = errors.New("当前阶段没有截止时间")
+	ErrReminderRun = errors.New("正在发送提醒，请稍后再试")
+)
+
+// Target 待提醒的评审团成员
+type Target struct {
+	User      *model.User `json:"-"`
+	UserId    string      `json:"userId"`
+	Remaining int         `json:"remaining"` // 剩余票数，揭示阶段为0
+}
+
+// Plan 一次提醒的范围
+type Plan struct {
+	Rule     *model.VoteJuryRule         `json:"-"`
+	Vote     *model.Vote                 `json:"-"`
+	Round    int                         `json:"round"`
+	Phase    model.VoteJuryReminderPhase `json:"phase"`
+	Deadline time.Time                   `json:"deadline"`
+	Targets  []Target                    `json:"targets"`
+}
+
+type Service struct {
+	app core.App
+	sdk *sdk.FishPiSDK
+
+	// 发送私信耗时较长，同一时间只允许一个发送任务
+	sending sync.Mutex
+
+	logger *slog.Logger
+}
+
+func NewService(app core.App, sdk *sdk.FishPiSDK) *Service {
+	service := &Service{
+		app: app,
+		sdk: sdk,
+
+		logger: app.Logger().WithGroup("service.jury_reminder"),
+	}
+
+	return service
+}
+
+// Run 注册评审团催投提醒任务，每分钟检查一次
+func (service *Service) Run() error {
+	return service.app.Cron().Add(model.CronKeyJuryReminder.String(), "* * * * *", service.schedule)
+}
+
+func (service *Service) schedule() {
+	if !service.sending.TryLock() {
+		service.logger.Debug("上一次提醒尚未发送完成，跳过本次调度")
+		return
+	}
+	defer service.sending.Unlock()
+
+	var rules []*model.VoteJuryRule
+	if err := service.app.RecordQuery(model.DbNameVoteJuryRules).Where(dbx.HashExp{
+		model.VoteJuryRuleFieldStatus: model.VoteJuryRuleStatusVoting,
+	}).AndWhere(dbx.Not(dbx.HashExp{
+		model.VoteJuryRuleFieldReminderOffsets: "",
+	})).All(&rules); err != nil {
+		service.logger.Error("查询评审团规则失败", slog.Any("err", err))
+		return
+	}
+
+	now := time.Now()
+	budget := maxPerRun
+	for _, rule := range rules {
+		if budget <= 0 {
+			break
+		}
+		budget -= service.scheduleRule(rule, now, budget)
+	}
+}
+
+// scheduleRule 在到达提醒时间点后提醒尚未投票的成员，返回尝试发送的数量
+func (service *Service) scheduleRule(rule *model.VoteJuryRule, now time.Time, budget int) int {
+	logger := service.logger.With(slog.String("voteId", rule.VoteId()))
+
+	offsets := rule.ReminderOffsets()
+	if len(offsets) == 0 {
+		return 0
+	}
+
+	plan, err := service.Plan(rule)
+	if err != nil {
+		if !errors.Is(err, ErrNoDeadline) {
+			logger.Error("获取提醒范围失败", slog.Any("err", err))
+		}
+		return 0
+	}
+
+	offset, ok := dueOffset(offsets, plan.Deadline, now)
+	if !ok || len(plan.Targets) == 0 {
+		return 0
+	}
+
+	// 同一时间点已成功提醒过的成员不再重复提醒，发送失败的会在下一次调度中重试
+	var reminders []*model.VoteJuryReminder
+	if err := service.app.RecordQuery(model.DbNameVoteJuryReminders).Where(dbx.HashExp{
+		model.VoteJuryReminderFieldVoteId:  rule.VoteId(),
+		model.VoteJuryReminderFieldRound:   plan.Round,
+		model.VoteJuryReminderFieldPhase:   plan.Phase,
+		model.VoteJuryReminderFieldOffset:  offset,
+		model.VoteJuryReminderFieldTrigger: model.VoteJuryStatusTriggerSchedule,
+		model.VoteJuryReminderFieldStatus:  model.VoteJuryReminderStatusSent,
+	}).All(&reminders); err != nil {
+		logger.Error("查询提醒记录失败", slog.Any("err", err))
+		return 0
+	}
+	reminded := make(map[string]bool, len(reminders))
+	for _, reminder := range reminders {
+		reminded[reminder.UserId()] = true
+	}
+	plan.Targets = slices.DeleteFunc(plan.Targets, func(target Target) bool {
+		return reminded[target.UserId]
+	})
+	if len(plan.Targets) > budget {
+		plan.Targets = plan.Targets[:budget]
+	}
+
+	return service.send(plan, offset, model.VoteJuryStatusTriggerSchedule, "")
+}
+
+// Remind 由管理员手动提醒当前阶段尚未投票的成员，冷却期内已提醒过的成员会被跳过
+// 私信在后台发送，返回本次提醒的范围
+func (service *Service) Remind(rule *model.VoteJuryRule, actorId string) (*Plan, error) {
+	plan, err := service.Plan(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	var reminders []*model.VoteJuryReminder
+	if err := service.app.RecordQuery(model.DbNameVoteJuryReminders).Where(dbx.HashExp{
+		model.VoteJuryReminderFieldVoteId: rule.VoteId(),
+		model.VoteJuryReminderFieldRound:  plan.Round,
+		model.VoteJuryReminderFieldPhase:  plan.Phase,
+		model.VoteJuryReminderFieldStatus: model.VoteJuryReminderStatusSent,
+	}).AndWhere(dbx.NewExp("created >= {:since}", dbx.Params{
+		"since": types.NowDateTime().Add(-manualCooldown),
+	})).All(&reminders); err != nil {
+		return nil, err
+	}
+	reminded := make(map[string]bool, len(reminders))
+	for _, reminder := range reminders {
+		reminded[reminder.UserId()] = true
+	}
+	plan.Targets = slices.DeleteFunc(plan.Targets, func(target Target) bool {
+		return reminded[target.UserId]
+	})
+	if len(plan.Targets) > maxPerRun {
+		plan.Targets = plan.Targets[:maxPerRun]
+	}
+	if len(plan.Targets) == 0 {
+		return plan, nil
+	}
+
+	if !service.sending.TryLock() {
+		return nil, ErrReminderRun
+	}
+	go func() {
+		defer service.sending.Unlock()
+		service.send(plan, 0, model.VoteJuryStatusTriggerManual, actorId)
+	}()
+
+	return plan, nil
+}
+
+// Plan 计算当前阶段的截止时间与尚未投票的成员
+// 投票阶段只有第一轮有截止时间，秘密投票的揭示阶段以揭示时长为准
+func (service *Service) Plan(rule *model.VoteJuryRule) (*Plan, error) {
+	if rule.Status() != model.VoteJuryRuleStatusVoting {
+		return nil, ErrNotVoting
+	}
+
+	vote := new(model.Vote)
+	if err := service.app.RecordQuery(model.DbNameVotes).Where(dbx.HashExp{model.CommonFieldId: rule.VoteId()}).One(vote); err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{
+		Rule:  rule,
+		Vote:  vote,
+		Round: rule.CurrentRound(),
+		Phase: model.VoteJuryReminderPhaseVote,
+	}
+	switch {
+	case rule.SecretBallot() && rule.Revealing():
+		plan.Phase = model.VoteJuryReminderPhaseReveal
+		plan.Deadline = rule.RevealStart().Time().Add(time.Duration(rule.RevealMinutes()) * time.Minute)
+	case plan.Round == 1 && !vote.End().IsZero():
+		plan.Deadline = vote.End().Time()
+	default:
+		return nil, ErrNoDeadline
+	}
+
+	targets, err := service.pendingTargets(rule, vote, plan.Round, plan.Phase)
+	if err != nil {
+		return nil, err
+	}
+	plan.Targets = targets
+
+	return plan, nil
+}
+
+// pendingTargets 查询尚未完成当前阶段操作的评审团成员
+func (service *Service) pendingTargets(rule *model.VoteJuryRule, vote *model.Vote, round int, phase model.VoteJuryReminderPhase) ([]Target, error) {
+	var members []*model.VoteJuryUser
+	if err := service.app.RecordQuery(model.DbNameVoteJuryUsers).Where(dbx.HashExp{
+		model.VoteJuryUserFieldVoteId: rule.VoteId(),
+		model.VoteJuryUserFieldStatus: model.VoteJuryUserStatusApproved,
+	}).All(&members); err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	// 成员 -> 剩余票数
+	remaining := make(map[string]int, len(members))
+	for _, member := range members {
+		remaining[member.UserId()] = vote.Times()
+	}
+
+	// 待提醒成员 -> 剩余票数，揭示阶段为0
+	pending := make(map[string]int)
+	switch {
+	case phase == model.VoteJuryReminderPhaseReveal:
+		// 揭示阶段只提醒已提交承诺但尚未揭示的成员
+		var commits []*model.VoteJuryCommit
+		if err := service.app.RecordQuery(model.DbNameVoteJuryCommits).Where(dbx.HashExp{
+			model.VoteJuryCommitFieldVoteId:   rule.VoteId(),
+			model.VoteJuryCommitFieldRound:    round,
+			model.VoteJuryCommitFieldRevealed: false,
+		}).All(&commits); err != nil {
+			return nil, err
+		}
+		for _, commit := range commits {
+			if _, ok := remaining[commit.FromUserId()]; ok {
+				pending[commit.FromUserId()] = 0
+			}
+		}
+	case rule.SecretBallot():
+		var commits []*model.VoteJuryCommit
+		if err := service.app.RecordQuery(model.DbNameVoteJuryCommits).Where(dbx.HashExp{
+			model.VoteJuryCommitFieldVoteId: rule.VoteId(),
+			model.VoteJuryCommitFieldRound:  round,
+		}).All(&commits); err != nil {
+			return nil, err
+		}
+		for _, commit := range commits {
+			delete(remaining, commit.FromUserId())
+		}
+		pending = remaining
+	case rule.Scoring() == model.VoteJuryScoringRubric:
+		var scores []*model.VoteJuryScore
+		if err := service.app.RecordQuery(model.DbNameVoteJuryScores).Where(dbx.HashExp{
+			model.VoteJuryScoreFieldVoteId: rule.VoteId(),
+			model.VoteJuryScoreFieldRound:  round,
+		}).All(&scores); err != nil {
+			return nil, err
+		}
+		for _, score := range scores {
+			delete(remaining, score.FromUserId())
+		}
+		pending = remaining
+	default:
+		var logs []*model.VoteJuryLog
+		if err := service.app.RecordQuery(model.DbNameVoteJuryLogs).Where(dbx.HashExp{
+			model.VoteJuryLogFieldVoteId: rule.VoteId(),
+			model.VoteJuryLogFieldRound:  round,
+		}).All(&logs); err != nil {
+			return nil, err
+		}
+		for _, log := range logs {
+			if _, ok := remaining[log.FromUserId()]; ok {
+				remaining[log.FromUserId()] -= log.Times()
+			}
+		}
+		for userId, count := range remaining {
+			if count > 0 {
+				pending[userId] = count
+			}
+		}
+	}
+
+	if len(pending) == 0 {
+		return nil, nil
+	}
+	userIds := make([]any, 0, len(pending))
+	for userId := range pending {
+		userIds = append(userIds, userId)
+	}
+
+	var users []*model.User
+	if err := service.app.RecordQuery(model.DbNameUsers).Where(dbx.In(model.CommonFieldId, userIds...)).
+		OrderBy(model.CommonFieldId + " ASC").All(&users); err != nil {
+		return nil, err
+	}
+
+	targets := make([]Target, 0, len(users))
+	for _, user := range users {
+		targets = append(targets, Target{
+			User:      user,
+			UserId:    user.Id,
+			Remaining: pending[user.Id],
+		})
+	}
+	return targets, nil
+}
+
+// send 逐条发送私信并记录发送结果，返回尝试发送的数量
+func (service *Service) send(plan *Plan, offset int, trigger model.VoteJuryStatusTrigger, actorId string) int {
+	logger := service.logger.With(slog.String("voteId", plan.Rule.VoteId()), slog.Int("round", plan.Round))
+
+	collection, err := service.app.FindCollectionByNameOrId(model.DbNameVoteJuryReminders)
+	if err != nil {
+		logger.Error("获取提醒记录表失败", slog.Any("err", err))
+		return 0
+	}
+
+	sent := 0
+	for i, target := range plan.Targets {
+		if i > 0 {
+			time.Sleep(sendInterval)
+		}
+
+		content := renderTemplate(plan, target, time.Now())
+
+		reminder := model.NewVoteJuryReminderFromCollection(collection)
+		reminder.SetVoteId(plan.Rule.VoteId())
+		reminder.SetRound(plan.Round)
+		reminder.SetPhase(plan.Phase)
+		reminder.SetOffset(offset)
+		reminder.SetUserId(target.UserId)
+		reminder.SetTrigger(trigger)
+		reminder.SetActorId(actorId)
+		reminder.SetContent(content)
+		reminder.SetStatus(model.VoteJuryReminderStatusSent)
+
+		if err := service.sendPrivateMessage(target.User.Name(), content); err != nil {
+			logger.Warn("发送提醒失败", slog.String("userId", target.UserId), slog.Any("err", err))
+			reminder.SetStatus(model.VoteJuryReminderStatusFailed)
+			reminder.SetError(err.Error())
+		} else {
+			sent++
+		}
+
+		if err := service.app.Save(reminder); err != nil {
+			logger.Error("保存提醒记录失败", slog.String("userId", target.UserId), slog.Any("err", err))
+		}
+	}
+
+	logger.Info("评审团提醒发送完成",
+		slog.String("phase", plan.Phase.String()),
+		slog.Int("offset", offset),
+		slog.String("trigger", trigger.String()),
+		slog.Int("targets", len(plan.Targets)),
+		slog.Int("sent", sent),
+	)
+
+	return len(plan.Targets)
+}
+
+// sendPrivateMessage 通过摸鱼派私聊发送一条消息
+func (service *Service) sendPrivateMessage(username string, content string) error {
+	ws := service.sdk.NewPrivateChatWebSocket(username)
+	if err := ws.Connect(); err != nil {
+		return fmt.Errorf("连接私聊失败: %w", err)
+	}
+	defer func() {
+		_ = ws.Close()
+	}()
+
+	return ws.SendMessage(content)
+}
+
+// dueOffset 返回已到达的最近一个提醒时间点，截止后不再提醒
+func dueOffset(offsets []int, deadline time.Time, now time.Time) (int, bool) {
+	if !now.Before(deadline) {
+		return 0, false
+	}
+
+	due := -1
+	for _, offset := range offsets {
+		if offset <= 0 {
+			continue
+		}
+		if !now.Before(deadline.Add(-time.Duration(offset)*time.Minute)) && (due < 0 || offset < due) {
+			due = offset
+		}
+	}
+	return due, due > 0
+}
+
+// renderTemplate 使用评审团配置的模板生成提醒内容
+// 支持的占位符：{nickname} {name} {voteName} {round} {deadline} {remaining} {votes}
+func renderTemplate(plan *Plan, target Target, now time.Time) string {
+	template := plan.Rule.ReminderTemplate()
+	if template == "" {
+		template = DefaultReminderTemplate
+	}
+	if plan.Phase == model.VoteJuryReminderPhaseReveal {
+		template = plan.Rule.RevealTemplate()
+		if template == "" {
+			template = DefaultRevealTemplate
+		}
+	}
+
+	nickname := target.User.Nickname()
+	if nickname == "" {
+		nickname = target.User.Name()
+	}
+
+	return strings.NewReplacer(
+		"{nickname}", nickname,
+		"{name}", target.User.Name(),
+		"{voteName}", plan.Vote.Name(),
+		"{round}", strconv.Itoa(plan.Round),
+		"{deadline}", plan.Deadline.Local().Format("2006-01-02 15:04"),
+		"{remaining}", formatRemaining(plan.Deadline.Sub(now)),
+		"{votes}", strconv.Itoa(target.Remaining),
+	).Replace(template)
+}
+
+// formatRemaining 将剩余时间格式化为便于阅读的文字
+func formatRemaining(duration time.Duration) string {
+	minutes := max(int(duration.Minutes()), 0)
+	switch {
+	case minutes >= 24*60:
+		return fmt.Sprintf("%d天%d小时", minutes/(24*60), minutes%(24*60)/60)
+	case minutes >= 60:
+		return fmt.Sprintf("%d小时%d分钟", minutes/60, minutes%60)
+	default:
+		return fmt.Sprintf("%d分钟", minutes)
+	}
+}
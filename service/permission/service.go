@@ -0,0 +1,202 @@
+package permission
+
+import (
+	"bless-activity/model"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+var (
+	ErrGrantNotFound     = errors.New("授权记录不存在")
+	ErrGrantExists       = errors.New("授权已存在")
+	ErrInvalidPermission = errors.New("无效的权限名称")
+)
+
+type Service struct {
+	app core.App
+
+	logger *slog.Logger
+}
+
+func NewService(app core.App) *Service {
+	service := &Service{
+		app:    app,
+		logger: app.Logger().WithGroup("service.permission"),
+	}
+	return service
+}
+
+// roleAdminPermissions 管理员角色默认拥有的全局权限
+// reward.distribute 需要显式授权；jury.manage 按投票由评审团规则中的管理员或授权决定
+var roleAdminPermissions = []model.Permission{
+	model.PermissionPointDistribute,
+	model.PermissionMedalGrant,
+	model.PermissionVoteManage,
+}
+
+// Has 判断用户是否拥有权限，activityIds 为空时只认全局授权，否则全局授权或任一活动的授权均可
+// 超级管理员拥有全部权限；管理员角色拥有 roleAdminPermissions 中的权限
+func (service *Service) Has(auth *core.Record, permission model.Permission, activityIds ...string) bool {
+	if auth == nil {
+		return false
+	}
+	if auth.IsSuperuser() {
+		return true
+	}
+	if auth.GetString(model.UsersFieldRole) == string(model.UserRoleAdmin) && slices.Contains(roleAdminPermissions, permission) {
+		return true
+	}
+
+	scopes := []any{""}
+	for _, activityId := range activityIds {
+		if activityId != "" {
+			scopes = append(scopes, activityId)
+		}
+	}
+
+	var total int
+	if err := service.app.RecordQuery(model.DbNamePermissions).
+		Select("count(*)").
+		Where(dbx.HashExp{
+			model.PermissionsFieldUserId:     auth.Id,
+			model.PermissionsFieldPermission: string(permission),
+		}).
+		AndWhere(dbx.In(model.PermissionsFieldActivityId, scopes...)).
+		Row(&total); err != nil {
+		service.logger.Error("查询授权失败",
+			slog.String("userId", auth.Id),
+			slog.String("permission", string(permission)),
+			slog.Any("err", err),
+		)
+		return false
+	}
+	return total > 0
+}
+
+// HasForVote 判断用户是否拥有投票所属活动的权限
+// 评审团规则中的管理员视为拥有该投票的 jury.manage 权限
+func (service *Service) HasForVote(auth *core.Record, permission model.Permission, voteId string) bool {
+	if auth == nil {
+		return false
+	}
+
+	if permission == model.PermissionJuryManage {
+		rule := new(model.VoteJuryRule)
+		if err := service.app.RecordQuery(model.DbNameVoteJuryRules).
+			Where(dbx.HashExp{model.VoteJuryRuleFieldVoteId: voteId}).
+			One(rule); err == nil && slices.Contains(rule.Admins(), auth.Id) {
+			return true
+		}
+	}
+
+	return service.Has(auth, permission, service.VoteActivityIds(voteId)...)
+}
+
+// VoteActivityIds 关联了该投票的活动ID
+func (service *Service) VoteActivityIds(voteId string) []string {
+	if voteId == "" {
+		return nil
+	}
+
+	var activities []*model.Activity
+	if err := service.app.RecordQuery(model.DbNameActivities).
+		Where(dbx.HashExp{model.ActivitiesFieldVoteId: voteId}).
+		All(&activities); err != nil {
+		service.logger.Error("查询投票关联活动失败", slog.String("voteId", voteId), slog.Any("err", err))
+		return nil
+	}
+
+	activityIds := make([]string, 0, len(activities))
+	for _, activity := range activities {
+		activityIds = append(activityIds, activity.Id)
+	}
+	return activityIds
+}
+
+// Grant 授予权限，activityId 为空表示全局授权
+func (service *Service) Grant(userId string, permission model.Permission, activityId string, grantedBy string) (*model.PermissionGrant, error) {
+	if !permission.IsValid() {
+		return nil, ErrInvalidPermission
+	}
+
+	exists := new(model.PermissionGrant)
+	err := service.app.RecordQuery(model.DbNamePermissions).
+		Where(dbx.HashExp{
+			model.PermissionsFieldUserId:     userId,
+			model.PermissionsFieldPermission: string(permission),
+			model.PermissionsFieldActivityId: activityId,
+		}).
+		One(exists)
+	if err == nil {
+		return nil, ErrGrantExists
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	collection, err := service.app.FindCollectionByNameOrId(model.DbNamePermissions)
+	if err != nil {
+		return nil, err
+	}
+
+	grant := model.NewPermissionGrantFromCollection(collection)
+	grant.SetUserId(userId)
+	grant.SetPermission(permission)
+	grant.SetActivityId(activityId)
+	grant.SetGrantedBy(grantedBy)
+	if err = service.app.Save(grant); err != nil {
+		return nil, err
+	}
+
+	service.logger.Info("授予权限",
+		slog.String("userId", userId),
+		slog.String("permission", string(permission)),
+		slog.String("activityId", activityId),
+		slog.String("grantedBy", grantedBy),
+	)
+	return grant, nil
+}
+
+// Revoke 撤销授权
+func (service *Service) Revoke(grantId string) error {
+	grant := new(model.PermissionGrant)
+	if err := service.app.RecordQuery(model.DbNamePermissions).
+		Where(dbx.HashExp{model.CommonFieldId: grantId}).
+		One(grant); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrGrantNotFound
+		}
+		return err
+	}
+
+	if err := service.app.Delete(grant); err != nil {
+		return err
+	}
+
+	service.logger.Info("撤销权限",
+		slog.String("userId", grant.UserId()),
+		slog.String("permission", string(grant.Permission())),
+		slog.String("activityId", grant.ActivityId()),
+	)
+	return nil
+}
+
+// Grants 授权列表，userId 为空时返回全部
+func (service *Service) Grants(userId string) ([]*model.PermissionGrant, error) {
+	query := service.app.RecordQuery(model.DbNamePermissions).
+		OrderBy(model.PermissionsFieldCreated + " DESC")
+	if userId != "" {
+		query = query.Where(dbx.HashExp{model.PermissionsFieldUserId: userId})
+	}
+
+	var grants []*model.PermissionGrant
+	if err := query.All(&grants); err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
@@ -0,0 +1,153 @@
+package point_import
+
+import (
+	"bless-activity/pkg/xlsx"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnsupportedFile = errors.New("仅支持 csv 或 xlsx 文件")
+	ErrEmptyFile       = errors.New("文件中没有数据")
+	ErrMissingColumn   = errors.New("缺少必需的列：需要 username 或 oId 列以及 point 列")
+)
+
+const (
+	columnUsername = "username"
+	columnOId      = "oId"
+	columnPoint    = "point"
+	columnMemo     = "memo"
+	columnGroup    = "group"
+)
+
+// columnAliases 表头名称（忽略大小写与首尾空格）对应的列
+var columnAliases = map[string]string{
+	"username": columnUsername,
+	"用户名":      columnUsername,
+	"oid":      columnOId,
+	"point":    columnPoint,
+	"points":   columnPoint,
+	"积分":       columnPoint,
+	"memo":     columnMemo,
+	"备注":       columnMemo,
+	"group":    columnGroup,
+	"分组":       columnGroup,
+}
+
+// Parse 按扩展名解析 csv 或 xlsx 文件，第一行非空行为表头，返回数据行（行号从 1 开始，与表格中一致）
+func Parse(filename string, data []byte) ([]*Row, error) {
+	var (
+		records [][]string
+		err     error
+	)
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+		reader.FieldsPerRecord = -1
+		reader.LazyQuotes = true
+		records, err = reader.ReadAll()
+	case ".xlsx":
+		records, err = xlsx.ReadFirstSheet(data)
+	default:
+		return nil, ErrUnsupportedFile
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析文件失败: %w", err)
+	}
+
+	header := -1
+	for i, record := range records {
+		if !blank(record) {
+			header = i
+			break
+		}
+	}
+	if header < 0 {
+		return nil, ErrEmptyFile
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[header] {
+		if column, ok := columnAliases[strings.ToLower(strings.TrimSpace(name))]; ok {
+			if _, exists := columns[column]; !exists {
+				columns[column] = i
+			}
+		}
+	}
+	_, hasUsername := columns[columnUsername]
+	_, hasOId := columns[columnOId]
+	if _, hasPoint := columns[columnPoint]; !hasPoint || (!hasUsername && !hasOId) {
+		return nil, ErrMissingColumn
+	}
+
+	cell := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []*Row
+	for i := header + 1; i < len(records); i++ {
+		record := records[i]
+		if blank(record) {
+			continue
+		}
+
+		row := &Row{
+			Line:     i + 1,
+			Username: cell(record, columnUsername),
+			OId:      cell(record, columnOId),
+			Memo:     cell(record, columnMemo),
+			Group:    cell(record, columnGroup),
+		}
+
+		if raw := cell(record, columnPoint); raw == "" {
+			row.fail("积分不能为空")
+		} else if point, ok := parsePoint(raw); !ok {
+			row.fail(fmt.Sprintf("积分格式错误：%s", raw))
+		} else if point == 0 {
+			row.fail("积分数量不能为0")
+		} else {
+			row.Point = point
+		}
+
+		if row.Username == "" && row.OId == "" {
+			row.fail("用户名与 oId 不能同时为空")
+		}
+
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, ErrEmptyFile
+	}
+	return rows, nil
+}
+
+// parsePoint 解析积分，xlsx 中的数字可能带有小数部分，只接受整数值
+func parsePoint(raw string) (int, bool) {
+	if point, err := strconv.Atoi(raw); err == nil {
+		return point, true
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || value != float64(int(value)) {
+		return 0, false
+	}
+	return int(value), true
+}
+
+func blank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
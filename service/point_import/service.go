@@ -0,0 +1,278 @@
+package point_import
+
+import (
+	"bless-activity/model"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/FishPiOffical/golang-sdk/sdk"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+var (
+	ErrInvalidRows = errors.New("存在校验失败的行")
+)
+
+const (
+	RowStatusValid   = "valid"   // 校验通过，提交时创建待发放记录
+	RowStatusSkipped = "skipped" // 已存在相同分组的待发放记录，提交时跳过
+	RowStatusInvalid = "invalid" // 校验失败
+)
+
+// Row 导入文件中的一行
+type Row struct {
+	Line     int      `json:"line"` // 文件中的行号
+	Username string   `json:"username"`
+	OId      string   `json:"oId"`
+	Point    int      `json:"point"`
+	Memo     string   `json:"memo"`
+	Group    string   `json:"group"`
+	Nickname string   `json:"nickname"`
+	NewUser  bool     `json:"newUser"` // 本地不存在，提交时自动创建用户
+	Status   string   `json:"status"`
+	Errors   []string `json:"errors"`
+
+	user *model.User // 本地用户，NewUser 时为提交时待创建的用户
+}
+
+func (row *Row) fail(message string) {
+	row.Status = RowStatusInvalid
+	row.Errors = append(row.Errors, message)
+}
+
+// Defaults 行内未填写时使用的默认值
+type Defaults struct {
+	Memo  string
+	Group string
+}
+
+// Report 导入校验结果
+type Report struct {
+	Total   int    `json:"total"`
+	Valid   int    `json:"valid"`
+	Skipped int    `json:"skipped"`
+	Invalid int    `json:"invalid"`
+	Point   int    `json:"point"` // 校验通过的积分合计
+	Rows    []*Row `json:"rows"`
+}
+
+type Service struct {
+	app core.App
+	sdk *sdk.FishPiSDK
+
+	logger *slog.Logger
+}
+
+func NewService(app core.App, sdk *sdk.FishPiSDK) *Service {
+	service := &Service{
+		app:    app,
+		sdk:    sdk,
+		logger: app.Logger().WithGroup("service.point_import"),
+	}
+	return service
+}
+
+// Validate 补全默认值、解析用户并逐行校验，本地不存在的用户通过摸鱼派查询，不写入任何数据
+func (service *Service) Validate(rows []*Row, defaults Defaults) *Report {
+	users := make(map[string]*model.User)
+	seen := make(map[string]int)
+
+	for _, row := range rows {
+		if row.Memo == "" {
+			row.Memo = defaults.Memo
+		}
+		if row.Group == "" {
+			row.Group = defaults.Group
+		}
+		if row.Status == RowStatusInvalid {
+			continue
+		}
+
+		key := "oId:" + row.OId
+		if row.OId == "" {
+			key = "name:" + row.Username
+		}
+		user, ok := users[key]
+		if !ok {
+			var err error
+			if user, err = service.resolveUser(row.Username, row.OId); err != nil {
+				row.fail(err.Error())
+				continue
+			}
+			// 同一用户分别以 oId 和用户名出现时复用同一条记录，避免构造出两条 oId 相同的待创建用户
+			if cached, exists := users["oId:"+user.OId()]; exists {
+				user = cached
+			}
+			users["oId:"+user.OId()] = user
+			users["name:"+user.Name()] = user
+		}
+
+		row.user = user
+		row.NewUser = user.IsNew()
+		row.Username = user.Name()
+		row.OId = user.OId()
+		row.Nickname = user.Nickname()
+
+		// 同一文件中同一用户同一分组只允许出现一次
+		duplicate := row.OId + "\x00" + row.Group
+		if line, exists := seen[duplicate]; exists {
+			row.fail(fmt.Sprintf("与第 %d 行的用户和分组重复", line))
+			continue
+		}
+		seen[duplicate] = row.Line
+
+		if row.Group != "" && !row.NewUser {
+			existing := new(model.Point)
+			if err := service.app.RecordQuery(model.DbNamePoints).Where(dbx.HashExp{
+				model.PointsFieldGroup:  row.Group,
+				model.PointsFieldUserId: user.Id,
+				model.PointsFieldStatus: string(model.PointStatusPending),
+			}).One(existing); err == nil {
+				row.Status = RowStatusSkipped
+				row.Errors = append(row.Errors, "已存在相同分组的待发放记录")
+				continue
+			}
+		}
+
+		row.Status = RowStatusValid
+	}
+
+	report := &Report{
+		Total: len(rows),
+		Rows:  rows,
+	}
+	for _, row := range rows {
+		switch row.Status {
+		case RowStatusValid:
+			report.Valid++
+			report.Point += row.Point
+		case RowStatusSkipped:
+			report.Skipped++
+		default:
+			report.Invalid++
+		}
+	}
+	return report
+}
+
+// Commit 在一个事务中创建缺失的本地用户与待发放积分记录，存在校验失败的行时不写入任何数据
+func (service *Service) Commit(report *Report, activityId string) (int, error) {
+	if report.Invalid > 0 {
+		return 0, ErrInvalidRows
+	}
+
+	created := 0
+	err := service.app.RunInTransaction(func(txApp core.App) error {
+		pointCollection, err := txApp.FindCollectionByNameOrId(model.DbNamePoints)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range report.Rows {
+			if row.Status != RowStatusValid {
+				continue
+			}
+
+			if row.user.IsNew() {
+				if err = txApp.Save(row.user); err != nil {
+					return fmt.Errorf("第 %d 行创建用户失败: %w", row.Line, err)
+				}
+			}
+
+			pointRecord := model.NewPointFromCollection(pointCollection)
+			pointRecord.SetGroup(row.Group)
+			pointRecord.SetActivityId(activityId)
+			pointRecord.SetUserId(row.user.Id)
+			pointRecord.SetPoint(row.Point)
+			pointRecord.SetStatus(model.PointStatusPending)
+			pointRecord.SetMemo(row.Memo)
+			if err = txApp.Save(pointRecord); err != nil {
+				return fmt.Errorf("第 %d 行创建积分记录失败: %w", row.Line, err)
+			}
+			created++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	service.logger.Info("导入积分记录",
+		slog.String("activityId", activityId),
+		slog.Int("created", created),
+		slog.Int("skipped", report.Skipped),
+	)
+	return created, nil
+}
+
+// resolveUser 按 oId 或用户名查找本地用户，本地不存在时从摸鱼派获取并构造待创建的用户
+func (service *Service) resolveUser(username string, oId string) (*model.User, error) {
+	user := new(model.User)
+	where := dbx.HashExp{model.UsersFieldOId: oId}
+	if oId == "" {
+		where = dbx.HashExp{model.UsersFieldName: username}
+	}
+	err := service.app.RecordQuery(model.DbNameUsers).Where(where).One(user)
+	if err == nil {
+		if username != "" && oId != "" && user.Name() != username {
+			return nil, fmt.Errorf("oId %s 对应的用户名为 %s，与 %s 不一致", oId, user.Name(), username)
+		}
+		return user, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("查询本地用户失败")
+	}
+
+	var (
+		name     string
+		nickname string
+		avatar   string
+	)
+	if oId != "" {
+		resp, sdkErr := service.sdk.GetUserInfoById(oId)
+		if sdkErr != nil {
+			service.logger.Error("获取鱼派用户信息失败", slog.String("oId", oId), slog.Any("err", sdkErr))
+			return nil, errors.New("获取鱼派用户信息失败")
+		}
+		if resp.Code != 0 || resp.Data == nil {
+			return nil, fmt.Errorf("鱼派用户不存在：%s", oId)
+		}
+		name, nickname, avatar = resp.Data.UserName, resp.Data.UserNickname, resp.Data.UserAvatarURL
+		if username != "" && name != username {
+			return nil, fmt.Errorf("oId %s 对应的用户名为 %s，与 %s 不一致", oId, name, username)
+		}
+	} else {
+		info, sdkErr := service.sdk.GetUserInfoByUsername(username)
+		if sdkErr != nil {
+			service.logger.Error("获取鱼派用户信息失败", slog.String("username", username), slog.Any("err", sdkErr))
+			return nil, errors.New("获取鱼派用户信息失败")
+		}
+		if info == nil || info.OId == "" {
+			return nil, fmt.Errorf("鱼派用户不存在：%s", username)
+		}
+		oId, name, nickname, avatar = info.OId, info.UserName, info.UserNickname, info.UserAvatarURL
+
+		// 用户改名后本地可能以旧用户名存在
+		if err = service.app.RecordQuery(model.DbNameUsers).Where(dbx.HashExp{model.UsersFieldOId: oId}).One(user); err == nil {
+			return user, nil
+		}
+	}
+
+	collection, err := service.app.FindCollectionByNameOrId(model.DbNameUsers)
+	if err != nil {
+		return nil, errors.New("获取用户集合失败")
+	}
+	user = model.NewUserFromCollection(collection)
+	user.SetEmail(fmt.Sprintf("%s@fishpi.cn", oId))
+	user.SetEmailVisibility(true)
+	user.SetVerified(true)
+	user.SetOId(oId)
+	user.SetName(name)
+	user.SetNickname(nickname)
+	user.SetAvatar(avatar)
+	user.SetRandomPassword()
+	return user, nil
+}
@@ -0,0 +1,284 @@
+package point_ledger
+
+import (
+	"bless-activity/model"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// defaultApprovalWindow 未配置时累计发放的统计窗口
+const defaultApprovalWindow = 24 * time.Hour
+
+var (
+	ErrApprovalNotFound = errors.New("发放审批不存在")
+	ErrApprovalHandled  = errors.New("发放审批已处理")
+	ErrSelfApproval     = errors.New("不能审批自己发起的发放，请由另一位管理员审批")
+)
+
+// ApprovalConfig 读取大额发放审批阈值，未配置时不需要审批
+func (service *Service) ApprovalConfig() model.PayoutApprovalConfig {
+	var cfg model.PayoutApprovalConfig
+
+	record := new(model.Config)
+	if err := service.app.RecordQuery(model.DbNameConfigs).
+		Where(dbx.HashExp{model.ConfigsFieldKey: model.ConfigKeyPayoutApproval}).
+		One(record); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			service.logger.Warn("读取发放审批配置失败", slog.Any("err", err))
+		}
+		return cfg
+	}
+	if err := json.Unmarshal([]byte(record.Value()), &cfg); err != nil {
+		service.logger.Warn("解析发放审批配置失败", slog.Any("err", err))
+	}
+	return cfg
+}
+
+// NeedsApproval 发放的积分总额或人数超过阈值时需要审批
+// actorId 不为空时，该管理员在统计窗口内未经审批的发放与本次合并计算
+func (service *Service) NeedsApproval(actorId string, totalPoint int, recipients int) bool {
+	cfg := service.ApprovalConfig()
+	if cfg.MaxPoints <= 0 && cfg.MaxRecipients <= 0 {
+		return false
+	}
+
+	if actorId != "" {
+		window := time.Duration(cfg.WindowHours) * time.Hour
+		if window <= 0 {
+			window = defaultApprovalWindow
+		}
+		paidPoint, paidRecipients, err := service.OperatorTotal(actorId, window)
+		if err != nil {
+			// 无法统计累计额度时按需要审批处理
+			service.logger.Error("统计累计发放失败", slog.String("actorId", actorId), slog.Any("err", err))
+			return true
+		}
+		totalPoint += paidPoint
+		recipients += paidRecipients
+	}
+
+	return (cfg.MaxPoints > 0 && totalPoint > cfg.MaxPoints) ||
+		(cfg.MaxRecipients > 0 && recipients > cfg.MaxRecipients)
+}
+
+// OperatorTotal 管理员最近 window 内发起的发放积分总额与人次，包含发放中与超时未结算的流水，不含失败的发放
+func (service *Service) OperatorTotal(operatorId string, window time.Duration) (int, int, error) {
+	var row struct {
+		Total      int `db:"total"`
+		Recipients int `db:"recipients"`
+	}
+	if err := service.app.DB().
+		Select("COALESCE(SUM([[point]]), 0) AS total", "COUNT(*) AS recipients").
+		From(model.DbNamePointLedgers).
+		Where(dbx.HashExp{model.PointLedgersFieldOperatorId: operatorId}).
+		AndWhere(dbx.In(model.PointLedgersFieldStatus,
+			model.PointLedgerStatusSuccess.String(),
+			model.PointLedgerStatusPending.String(),
+			model.PointLedgerStatusUnsettled.String(),
+		)).
+		AndWhere(dbx.NewExp(model.PointLedgersFieldCreated+" >= {:since}", dbx.Params{"since": types.NowDateTime().Add(-window)})).
+		One(&row); err != nil {
+		return 0, 0, err
+	}
+	return row.Total, row.Recipients, nil
+}
+
+// ScheduledTotal 管理员已设置定时、尚未发放的积分总额与人次，excludeIds 中的记录不计入
+func (service *Service) ScheduledTotal(operatorId string, excludeIds []string) (int, int, error) {
+	query := service.app.DB().
+		Select("COALESCE(SUM([[point]]), 0) AS total", "COUNT(*) AS recipients").
+		From(model.DbNamePoints).
+		Where(dbx.HashExp{
+			model.PointsFieldScheduledBy: operatorId,
+			model.PointsFieldStatus:      model.PointStatusPending.String(),
+		}).
+		AndWhere(dbx.Not(dbx.HashExp{model.PointsFieldScheduledAt: ""}))
+	if len(excludeIds) > 0 {
+		ids := make([]any, 0, len(excludeIds))
+		for _, id := range excludeIds {
+			ids = append(ids, id)
+		}
+		query = query.AndWhere(dbx.NotIn(model.CommonFieldId, ids...))
+	}
+
+	var row struct {
+		Total      int `db:"total"`
+		Recipients int `db:"recipients"`
+	}
+	if err := query.One(&row); err != nil {
+		return 0, 0, err
+	}
+	return row.Total, row.Recipients, nil
+}
+
+// PendingPointApprovals 待审批的积分发放清单中的积分记录，pointId → approvalId
+func (service *Service) PendingPointApprovals() (map[string]string, error) {
+	var approvals []*model.PayoutApproval
+	if err := service.app.RecordQuery(model.DbNamePayoutApprovals).
+		Where(dbx.HashExp{
+			model.PayoutApprovalsFieldKind:   model.PayoutKindPoint.String(),
+			model.PayoutApprovalsFieldStatus: model.PayoutApprovalStatusPending.String(),
+		}).
+		All(&approvals); err != nil {
+		return nil, err
+	}
+
+	pending := make(map[string]string)
+	for _, approval := range approvals {
+		var items []model.PayoutApprovalItem
+		if err := json.Unmarshal([]byte(approval.Items()), &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			pending[item.Id] = approval.Id
+		}
+	}
+	return pending, nil
+}
+
+// RequestApproval 创建待审批的发放，冻结发放清单
+func (service *Service) RequestApproval(kind model.PayoutKind, activityId string, planId string, items []model.PayoutApprovalItem, requesterId string) (*model.PayoutApproval, error) {
+	collection, err := service.app.FindCollectionByNameOrId(model.DbNamePayoutApprovals)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPoint := 0
+	for _, item := range items {
+		totalPoint += item.Point
+	}
+	itemsJson, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	approval := model.NewPayoutApprovalFromCollection(collection)
+	approval.SetKind(kind)
+	approval.SetActivityId(activityId)
+	approval.SetPlanId(planId)
+	approval.SetItems(string(itemsJson))
+	approval.SetTotalPoint(totalPoint)
+	approval.SetRecipients(len(items))
+	approval.SetStatus(model.PayoutApprovalStatusPending)
+	approval.SetRequesterId(requesterId)
+	if err = service.app.Save(approval); err != nil {
+		return nil, err
+	}
+
+	service.logger.Info("发放等待审批",
+		slog.String("approvalId", approval.Id),
+		slog.String("kind", kind.String()),
+		slog.Int("totalPoint", totalPoint),
+		slog.Int("recipients", len(items)),
+		slog.String("requesterId", requesterId),
+	)
+	return approval, nil
+}
+
+// Approve 审批通过，审批人不能是发起人
+// 审批通过即标记为已执行，避免同一审批被重复执行，执行结果通过 SaveApprovalResult 保存
+func (service *Service) Approve(kind model.PayoutKind, approvalId string, approverId string, note string) (*model.PayoutApproval, error) {
+	var approval *model.PayoutApproval
+	err := service.app.RunInTransaction(func(txApp core.App) error {
+		var err error
+		if approval, err = findApproval(txApp, kind, approvalId); err != nil {
+			return err
+		}
+		if approval.Status() != model.PayoutApprovalStatusPending {
+			return ErrApprovalHandled
+		}
+		if approval.RequesterId() == approverId {
+			return ErrSelfApproval
+		}
+
+		approval.SetStatus(model.PayoutApprovalStatusExecuted)
+		approval.SetApproverId(approverId)
+		approval.SetNote(note)
+		approval.SetApprovedAt(types.NowDateTime())
+		return txApp.Save(approval)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	service.logger.Info("发放审批通过", slog.String("approvalId", approvalId), slog.String("approverId", approverId))
+	return approval, nil
+}
+
+// Reject 驳回待审批的发放，发起人也可以撤回自己的发放
+func (service *Service) Reject(kind model.PayoutKind, approvalId string, approverId string, note string) (*model.PayoutApproval, error) {
+	approval, err := findApproval(service.app, kind, approvalId)
+	if err != nil {
+		return nil, err
+	}
+	if approval.Status() != model.PayoutApprovalStatusPending {
+		return nil, ErrApprovalHandled
+	}
+
+	approval.SetStatus(model.PayoutApprovalStatusRejected)
+	approval.SetApproverId(approverId)
+	approval.SetNote(note)
+	approval.SetApprovedAt(types.NowDateTime())
+	if err = service.app.Save(approval); err != nil {
+		return nil, err
+	}
+
+	service.logger.Info("发放审批驳回", slog.String("approvalId", approvalId), slog.String("approverId", approverId))
+	return approval, nil
+}
+
+// SaveApprovalResult 保存审批通过后的执行结果
+func (service *Service) SaveApprovalResult(approval *model.PayoutApproval, result any) {
+	resultJson, _ := json.Marshal(result)
+	approval.SetResult(string(resultJson))
+	approval.SetExecutedAt(types.NowDateTime())
+	if err := service.app.Save(approval); err != nil {
+		service.logger.Error("保存发放审批结果失败", slog.String("approvalId", approval.Id), slog.Any("err", err))
+	}
+}
+
+// Approval 获取指定类型的发放审批
+func (service *Service) Approval(kind model.PayoutKind, approvalId string) (*model.PayoutApproval, error) {
+	return findApproval(service.app, kind, approvalId)
+}
+
+// Approvals 发放审批列表，status 为空时返回全部
+func (service *Service) Approvals(kind model.PayoutKind, status string) ([]*model.PayoutApproval, error) {
+	query := service.app.RecordQuery(model.DbNamePayoutApprovals).
+		Where(dbx.HashExp{model.PayoutApprovalsFieldKind: kind}).
+		OrderBy(model.PayoutApprovalsFieldCreated + " DESC").
+		Limit(200)
+	if status != "" {
+		query = query.AndWhere(dbx.HashExp{model.PayoutApprovalsFieldStatus: status})
+	}
+
+	var approvals []*model.PayoutApproval
+	if err := query.All(&approvals); err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
+
+// findApproval 查询指定类型的发放审批
+func findApproval(app core.App, kind model.PayoutKind, approvalId string) (*model.PayoutApproval, error) {
+	approval := new(model.PayoutApproval)
+	if err := app.RecordQuery(model.DbNamePayoutApprovals).
+		Where(dbx.HashExp{
+			model.CommonFieldId:            approvalId,
+			model.PayoutApprovalsFieldKind: kind,
+		}).
+		One(approval); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrApprovalNotFound
+		}
+		return nil, err
+	}
+	return approval, nil
+}
@@ -0,0 +1,240 @@
+package point_ledger
+
+import (
+	"bless-activity/model"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// Distribute 逐条发放积分记录，operatorId 为发起发放的管理员，审批通过后执行时为空
+// 每条记录先以条件更新占用为发放中，并发的手动发放与定时任务中只有一个能发放同一条记录
+// 重复发放的记录开始发放时创建下一期记录，返回逐条的发放结果
+func (service *Service) Distribute(ids []string, operatorId string) map[string]any {
+	logger := service.logger.With(slog.String("action", "distribute"))
+
+	isDev := service.app.IsDev()
+
+	var (
+		successCount int
+		failedCount  int
+		skippedCount int
+		results      []map[string]any
+	)
+
+	for _, id := range ids {
+		result := map[string]any{
+			"id":      id,
+			"success": false,
+		}
+
+		// 查询积分记录
+		pointRecord := new(model.Point)
+		if err := service.app.RecordQuery(model.DbNamePoints).Where(dbx.HashExp{
+			model.CommonFieldId: id,
+		}).One(pointRecord); err != nil {
+			result["error"] = "记录不存在"
+			failedCount++
+			results = append(results, result)
+			continue
+		}
+		// 占用待发放或发放失败的记录，已发放或正在发放的记录不再处理
+		wasPending, err := service.claim(pointRecord.Id, model.PointStatusPending)
+		claimed := wasPending
+		if err == nil && !claimed {
+			claimed, err = service.claim(pointRecord.Id, model.PointStatusFailed)
+		}
+		if err != nil {
+			logger.Error("更新状态失败", slog.String("pointId", pointRecord.Id), slog.Any("err", err))
+			result["error"] = "更新状态失败"
+			failedCount++
+			results = append(results, result)
+			continue
+		}
+		if !claimed {
+			result["error"] = "记录已发放或正在发放"
+			skippedCount++
+			results = append(results, result)
+			continue
+		}
+		pointRecord.SetStatus(model.PointStatusDistributing)
+
+		// 查询用户
+		user := new(model.User)
+		if err := service.app.RecordQuery(model.DbNameUsers).Where(dbx.HashExp{
+			model.CommonFieldId: pointRecord.UserId(),
+		}).One(user); err != nil {
+			pointRecord.SetStatus(model.PointStatusFailed)
+			pointRecord.SetMemo(fmt.Sprintf("用户不存在: %v", err))
+			_ = service.app.Save(pointRecord)
+			result["error"] = "用户不存在"
+			failedCount++
+			results = append(results, result)
+			continue
+		}
+
+		if wasPending {
+			service.ScheduleNext(pointRecord)
+		}
+
+		// 构建发送给用户的memo
+		userMemo := pointRecord.Memo()
+		// 移除之前的错误信息
+		if idx := len(userMemo); idx > 0 {
+			if sepIdx := findLastIndex(userMemo, " | "); sepIdx > 0 {
+				userMemo = userMemo[:sepIdx]
+			}
+		}
+		if userMemo != "" {
+			userMemo = fmt.Sprintf("%s 交易单号：%s", userMemo, pointRecord.Id)
+		} else {
+			userMemo = fmt.Sprintf("积分发放 交易单号：%s", pointRecord.Id)
+		}
+
+		// 发放积分并记录流水，积分已发出但流水未结算时按成功处理，避免重复发放
+		if err := service.Pay(PointEntry(pointRecord, user, userMemo, operatorId)); errors.Is(err, ErrLedgerUnsettled) {
+			logger.Error("积分流水结算失败", slog.String("pointId", pointRecord.Id), slog.Any("err", err))
+			result["warning"] = err.Error()
+		} else if err != nil {
+			pointRecord.SetStatus(model.PointStatusFailed)
+			pointRecord.SetMemo(fmt.Sprintf("%s | 发放失败: %s", pointRecord.Memo(), err.Error()))
+			_ = service.app.Save(pointRecord)
+			result["error"] = err.Error()
+			failedCount++
+			results = append(results, result)
+			continue
+		}
+		if !isDev {
+			time.Sleep(500 * time.Millisecond)
+		}
+
+		// 发放成功
+		pointRecord.SetStatus(model.PointStatusSuccess)
+		// 清理memo中的错误信息
+		originalMemo := pointRecord.Memo()
+		if sepIdx := findLastIndex(originalMemo, " | "); sepIdx > 0 {
+			pointRecord.SetMemo(originalMemo[:sepIdx])
+		}
+		_ = service.app.Save(pointRecord)
+
+		result["success"] = true
+		successCount++
+		results = append(results, result)
+	}
+
+	logger.Info("批量发放完成",
+		slog.Int("total", len(ids)),
+		slog.Int("success", successCount),
+		slog.Int("failed", failedCount),
+		slog.Int("skipped", skippedCount),
+		slog.Bool("dev_mode", isDev),
+	)
+
+	return map[string]any{
+		"total":    len(ids),
+		"success":  successCount,
+		"failed":   failedCount,
+		"skipped":  skippedCount,
+		"dev_mode": isDev,
+		"results":  results,
+	}
+}
+
+// claim 以条件更新把处于 from 状态的记录标记为发放中，返回是否占用成功
+func (service *Service) claim(id string, from model.PointStatus) (bool, error) {
+	res, err := service.app.DB().Update(model.DbNamePoints, dbx.Params{
+		model.PointsFieldStatus: model.PointStatusDistributing.String(),
+	}, dbx.HashExp{
+		model.CommonFieldId:     id,
+		model.PointsFieldStatus: from.String(),
+	}).Execute()
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// PointEntry 构建积分记录对应的积分流水
+func PointEntry(pointRecord *model.Point, user *model.User, memo string, operatorId string) Entry {
+	return Entry{
+		ActivityId:    pointRecord.ActivityId(),
+		Group:         pointRecord.Group(),
+		User:          user,
+		Point:         pointRecord.Point(),
+		Memo:          memo,
+		TransactionNo: pointRecord.Id,
+		Source:        model.PointLedgerSourcePoint,
+		SourceId:      pointRecord.Id,
+		OperatorId:    operatorId,
+	}
+}
+
+// ScheduleNext 重复发放的记录开始发放时创建下一期待发放记录，错过的周期不补发
+func (service *Service) ScheduleNext(pointRecord *model.Point) {
+	recurrence := pointRecord.Recurrence()
+	scheduledAt := pointRecord.ScheduledAt()
+	if recurrence == model.PointRecurrenceNone || scheduledAt.IsZero() {
+		return
+	}
+
+	now := time.Now()
+	next := recurrence.Next(scheduledAt.Time())
+	for !next.After(now) {
+		next = recurrence.Next(next)
+	}
+	if until := pointRecord.RecurrenceUntil(); !until.IsZero() && next.After(until.Time()) {
+		return
+	}
+
+	nextAt, err := types.ParseDateTime(next)
+	if err != nil {
+		service.logger.Error("计算下一期发放时间失败", slog.String("id", pointRecord.Id), slog.Any("err", err))
+		return
+	}
+
+	collection, err := service.app.FindCollectionByNameOrId(model.DbNamePoints)
+	if err != nil {
+		service.logger.Error("获取积分集合失败", slog.Any("err", err))
+		return
+	}
+
+	nextRecord := model.NewPointFromCollection(collection)
+	nextRecord.SetGroup(pointRecord.Group())
+	nextRecord.SetActivityId(pointRecord.ActivityId())
+	nextRecord.SetUserId(pointRecord.UserId())
+	nextRecord.SetPoint(pointRecord.Point())
+	nextRecord.SetMemo(pointRecord.Memo())
+	nextRecord.SetStatus(model.PointStatusPending)
+	nextRecord.SetScheduledAt(nextAt)
+	nextRecord.SetRecurrence(recurrence)
+	nextRecord.SetRecurrenceUntil(pointRecord.RecurrenceUntil())
+	nextRecord.SetScheduledBy(pointRecord.ScheduledBy())
+	if err = service.app.Save(nextRecord); err != nil {
+		service.logger.Error("创建下一期发放记录失败", slog.String("id", pointRecord.Id), slog.Any("err", err))
+		return
+	}
+
+	service.logger.Info("创建下一期发放记录",
+		slog.String("id", pointRecord.Id),
+		slog.String("nextId", nextRecord.Id),
+		slog.String("scheduledAt", nextAt.String()),
+	)
+}
+
+// findLastIndex 查找最后一个子串的位置
+func findLastIndex(s, substr string) int {
+	for i := len(s) - len(substr); i >= 0; i-- {
+		if s[i:i+len(substr)] == substr {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,104 @@
+package point_ledger
+
+import (
+	"bless-activity/model"
+
+	"github.com/pocketbase/dbx"
+)
+
+// ReportRow 单个活动的积分对账结果
+type ReportRow struct {
+	ActivityId string `json:"activityId"`
+	Name       string `json:"name"`
+	Budget     int    `json:"budget"`    // 积分预算，0表示不限制
+	Planned    int    `json:"planned"`   // 计划发放：奖励发放记录与关联活动的积分记录之和
+	Paid       int    `json:"paid"`      // 已发放：积分流水中发放成功的总额
+	Failed     int    `json:"failed"`    // 发放失败尚未重试成功的总额
+	Unpaid     int    `json:"unpaid"`    // 计划中尚未发放成功的总额
+	Remaining  int    `json:"remaining"` // 剩余预算，未设置预算时为0
+}
+
+// Report 按活动对比计划、已发放与失败的积分，activityIds 为空时统计所有活动
+func (service *Service) Report(activityIds ...string) ([]ReportRow, error) {
+	query := service.app.RecordQuery(model.DbNameActivities).OrderBy(model.ActivitiesFieldStart + " DESC")
+	if len(activityIds) > 0 {
+		ids := make([]any, 0, len(activityIds))
+		for _, activityId := range activityIds {
+			ids = append(ids, activityId)
+		}
+		query = query.Where(dbx.In(model.CommonFieldId, ids...))
+	}
+
+	var activities []*model.Activity
+	if err := query.All(&activities); err != nil {
+		return nil, err
+	}
+
+	// 奖励发放记录按投票关联活动
+	rewardPlanned, err := service.sumBy(model.DbNameRewardDistributions, model.RewardDistributionsFieldVoteId, dbx.NewExp("1=1"))
+	if err != nil {
+		return nil, err
+	}
+	rewardFailed, err := service.sumBy(model.DbNameRewardDistributions, model.RewardDistributionsFieldVoteId, dbx.HashExp{
+		model.RewardDistributionsFieldStatus: model.DistributionStatusFailed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	rewardPaid, err := service.sumBy(model.DbNameRewardDistributions, model.RewardDistributionsFieldVoteId, dbx.HashExp{
+		model.RewardDistributionsFieldStatus: model.DistributionStatusSuccess,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 积分管理中关联活动的积分记录
+	pointPlanned, err := service.sumBy(model.DbNamePoints, model.PointsFieldActivityId, dbx.NewExp("1=1"))
+	if err != nil {
+		return nil, err
+	}
+	pointFailed, err := service.sumBy(model.DbNamePoints, model.PointsFieldActivityId, dbx.HashExp{
+		model.PointsFieldStatus: model.PointStatusFailed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	pointPaid, err := service.sumBy(model.DbNamePoints, model.PointsFieldActivityId, dbx.HashExp{
+		model.PointsFieldStatus: model.PointStatusSuccess,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ledgerPaid, err := service.sumBy(model.DbNamePointLedgers, model.PointLedgersFieldActivityId, dbx.HashExp{
+		model.PointLedgersFieldStatus: model.PointLedgerStatusSuccess,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]ReportRow, 0, len(activities))
+	for _, activity := range activities {
+		row := ReportRow{
+			ActivityId: activity.Id,
+			Name:       activity.GetName(),
+			Budget:     activity.GetPointBudget(),
+			Planned:    pointPlanned[activity.Id],
+			Paid:       ledgerPaid[activity.Id],
+			Failed:     pointFailed[activity.Id],
+		}
+		succeeded := pointPaid[activity.Id]
+		if voteId := activity.GetVoteId(); voteId != "" {
+			row.Planned += rewardPlanned[voteId]
+			row.Failed += rewardFailed[voteId]
+			succeeded += rewardPaid[voteId]
+		}
+		row.Unpaid = row.Planned - succeeded
+		if row.Budget > 0 {
+			row.Remaining = row.Budget - row.Paid
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,187 @@
+package point_ledger
+
+import (
+	"bless-activity/model"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// pendingTimeout pending 流水超过该时长仍未结算时视为发放进程已中断
+const pendingTimeout = 10 * time.Minute
+
+// Run 注册积分定时发放与流水对账任务
+func (service *Service) Run() error {
+	if err := service.app.Cron().Add(model.CronKeyPointSchedule.String(), "* * * * *", service.distributeScheduled); err != nil {
+		return err
+	}
+	return service.app.Cron().Add(model.CronKeyPointReconcile.String(), "*/5 * * * *", func() {
+		if _, err := service.ReconcilePending(pendingTimeout); err != nil {
+			service.logger.Error("积分流水对账失败", slog.Any("err", err))
+		}
+	})
+}
+
+// ReconcilePending 将创建超过 timeout 仍为 pending 的流水标记为 unsettled，返回标记的条数
+// 无法确认摸鱼派是否已到账，标记后继续占用预算与管理员累计额度，需人工核对
+func (service *Service) ReconcilePending(timeout time.Duration) (int, error) {
+	var ledgers []*model.PointLedger
+	if err := service.app.RecordQuery(model.DbNamePointLedgers).
+		Where(dbx.HashExp{model.PointLedgersFieldStatus: model.PointLedgerStatusPending.String()}).
+		AndWhere(dbx.NewExp(model.PointLedgersFieldCreated+" < {:before}", dbx.Params{"before": types.NowDateTime().Add(-timeout)})).
+		All(&ledgers); err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	marked := 0
+	for _, ledger := range ledgers {
+		// 条件更新，期间已被结算的流水保持结算结果
+		res, err := service.app.DB().Update(model.DbNamePointLedgers, dbx.Params{
+			model.PointLedgersFieldStatus: model.PointLedgerStatusUnsettled.String(),
+			model.PointLedgersFieldError:  "超时未结算，请核对摸鱼派是否已到账",
+		}, dbx.HashExp{
+			model.CommonFieldId:           ledger.Id,
+			model.PointLedgersFieldStatus: model.PointLedgerStatusPending.String(),
+		}).Execute()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if affected, err := res.RowsAffected(); err != nil || affected != 1 {
+			continue
+		}
+		marked++
+		service.logger.Warn("积分流水超时未结算",
+			slog.String("ledgerId", ledger.Id),
+			slog.String("transactionNo", ledger.TransactionNo()),
+			slog.String("userId", ledger.UserId()),
+			slog.Int("point", ledger.Point()),
+		)
+	}
+	return marked, errors.Join(errs...)
+}
+
+// distributeScheduled 定时任务：发放已到发放时间的待发放记录，按设置定时的管理员分别计入其累计发放额度
+// 在待审批发放中的记录等待审批结果；连同累计与其余定时超过审批阈值时转为审批，不直接发放
+func (service *Service) distributeScheduled() {
+	if !service.scheduling.TryLock() {
+		service.logger.Debug("上一轮定时发放尚未完成，跳过本次调度")
+		return
+	}
+	defer service.scheduling.Unlock()
+
+	var points []*model.Point
+	if err := service.app.RecordQuery(model.DbNamePoints).
+		Where(dbx.HashExp{model.PointsFieldStatus: string(model.PointStatusPending)}).
+		AndWhere(dbx.Not(dbx.HashExp{model.PointsFieldScheduledAt: ""})).
+		AndWhere(dbx.NewExp(model.PointsFieldScheduledAt+" <= {:now}", dbx.Params{"now": types.NowDateTime()})).
+		OrderBy(model.PointsFieldScheduledAt + " ASC").
+		All(&points); err != nil {
+		service.logger.Error("查询到期的定时发放失败", slog.Any("err", err))
+		return
+	}
+	if len(points) == 0 {
+		return
+	}
+
+	pending, err := service.PendingPointApprovals()
+	if err != nil {
+		service.logger.Error("查询待审批发放失败", slog.Any("err", err))
+		return
+	}
+
+	var operators []string
+	pointsByOperator := make(map[string][]*model.Point)
+	for _, p := range points {
+		if _, ok := pending[p.Id]; ok {
+			continue
+		}
+		if _, ok := pointsByOperator[p.ScheduledBy()]; !ok {
+			operators = append(operators, p.ScheduledBy())
+		}
+		pointsByOperator[p.ScheduledBy()] = append(pointsByOperator[p.ScheduledBy()], p)
+	}
+
+	for _, operatorId := range operators {
+		due := pointsByOperator[operatorId]
+		held, err := service.holdForApproval(operatorId, due)
+		if err != nil {
+			service.logger.Error("检查定时发放审批阈值失败", slog.String("scheduledBy", operatorId), slog.Any("err", err))
+			continue
+		}
+		if held {
+			continue
+		}
+
+		ids := make([]string, 0, len(due))
+		for _, p := range due {
+			ids = append(ids, p.Id)
+		}
+		result := service.Distribute(ids, operatorId)
+		service.logger.Info("定时发放完成",
+			slog.String("scheduledBy", operatorId),
+			slog.Int("total", len(ids)),
+			slog.Any("success", result["success"]),
+			slog.Any("failed", result["failed"]),
+		)
+	}
+}
+
+// holdForApproval 到期的定时发放连同管理员的累计发放与其余定时超过审批阈值时，提交审批并取消这些记录的定时，返回是否已转为审批
+// 转为审批的记录审批通过后发放一次，重复发放需要重新设置定时
+func (service *Service) holdForApproval(operatorId string, points []*model.Point) (bool, error) {
+	ids := make([]string, 0, len(points))
+	items := make([]model.PayoutApprovalItem, 0, len(points))
+	totalPoint := 0
+	for _, p := range points {
+		ids = append(ids, p.Id)
+		items = append(items, model.PayoutApprovalItem{
+			Id:     p.Id,
+			UserId: p.UserId(),
+			Point:  p.Point(),
+			Memo:   p.Memo(),
+		})
+		totalPoint += p.Point()
+	}
+
+	scheduledPoint, scheduledRecipients, err := service.ScheduledTotal(operatorId, ids)
+	if err != nil {
+		return false, err
+	}
+	if !service.NeedsApproval(operatorId, totalPoint+scheduledPoint, len(points)+scheduledRecipients) {
+		return false, nil
+	}
+
+	approval, err := service.RequestApproval(model.PayoutKindPoint, "", "", items, operatorId)
+	if err != nil {
+		return false, err
+	}
+	if err = service.app.RunInTransaction(func(txApp core.App) error {
+		for _, p := range points {
+			p.SetScheduledAt(types.DateTime{})
+			p.SetRecurrence(model.PointRecurrenceNone)
+			p.SetRecurrenceUntil(types.DateTime{})
+			p.SetScheduledBy("")
+			if err := txApp.Save(p); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		// 记录已在待审批清单中，之后的调度会跳过，不会直接发放
+		service.logger.Error("取消转为审批的定时失败", slog.String("approvalId", approval.Id), slog.Any("err", err))
+	}
+
+	service.logger.Warn("定时发放超过审批阈值，已转为审批",
+		slog.String("scheduledBy", operatorId),
+		slog.String("approvalId", approval.Id),
+		slog.Int("totalPoint", totalPoint),
+		slog.Int("recipients", len(points)),
+	)
+	return true, nil
+}
@@ -0,0 +1,220 @@
+package point_ledger
+
+import (
+	"bless-activity/model"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/FishPiOffical/golang-sdk/sdk"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+var (
+	ErrBudgetExceeded  = errors.New("超出活动积分预算")
+	ErrLedgerUnsettled = errors.New("积分已发放，但更新积分流水失败")
+)
+
+// Entry 一次向摸鱼派发出的积分变动
+type Entry struct {
+	ActivityId    string
+	Group         string
+	User          *model.User
+	Point         int
+	Memo          string // 发送给摸鱼派的备注，需包含交易单号
+	TransactionNo string
+	Source        model.PointLedgerSource
+	SourceId      string
+	OperatorId    string // 发起发放的管理员，计入其累计发放额度；审批通过后执行的发放为空
+}
+
+type Service struct {
+	app core.App
+	sdk *sdk.FishPiSDK
+
+	// 按活动串行预留预算，activityId → *sync.Mutex，调用摸鱼派接口时不持有
+	reserving sync.Map
+	// 定时发放串行执行，上一轮未完成时跳过
+	scheduling sync.Mutex
+
+	logger *slog.Logger
+}
+
+func NewService(app core.App, sdk *sdk.FishPiSDK) *Service {
+	service := &Service{
+		app:    app,
+		sdk:    sdk,
+		logger: app.Logger().WithGroup("service.point_ledger"),
+	}
+	return service
+}
+
+// Pay 发放积分并记录流水，关联活动设置了预算时超出预算的发放会被拒绝
+// 先写入 pending 流水预留预算，再调用摸鱼派接口，最后按结果结算流水
+// 积分已发出但结算失败时返回 ErrLedgerUnsettled，调用方应视为已发放，不能重试，pending 流水继续计入预算，超时后由对账任务标记为 unsettled
+func (service *Service) Pay(entry Entry) error {
+	ledger, err := service.reserve(entry)
+	if err != nil {
+		return err
+	}
+
+	if err = service.send(entry); err != nil {
+		if settleErr := service.settle(ledger, err); settleErr != nil {
+			return errors.Join(err, settleErr)
+		}
+		return err
+	}
+
+	if err = service.settle(ledger, nil); err != nil {
+		return fmt.Errorf("%w：%v", ErrLedgerUnsettled, err)
+	}
+	return nil
+}
+
+// reserve 在事务中检查预算并写入 pending 流水，同一活动的预留串行执行
+func (service *Service) reserve(entry Entry) (*model.PointLedger, error) {
+	if entry.ActivityId != "" {
+		lock, _ := service.reserving.LoadOrStore(entry.ActivityId, new(sync.Mutex))
+		lock.(*sync.Mutex).Lock()
+		defer lock.(*sync.Mutex).Unlock()
+	}
+
+	collection, err := service.app.FindCollectionByNameOrId(model.DbNamePointLedgers)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger := model.NewPointLedgerFromCollection(collection)
+	ledger.SetActivityId(entry.ActivityId)
+	ledger.SetGroup(entry.Group)
+	ledger.SetUserId(entry.User.Id)
+	ledger.SetPoint(entry.Point)
+	ledger.SetMemo(entry.Memo)
+	ledger.SetTransactionNo(entry.TransactionNo)
+	ledger.SetSource(entry.Source)
+	ledger.SetSourceId(entry.SourceId)
+	ledger.SetOperatorId(entry.OperatorId)
+	ledger.SetStatus(model.PointLedgerStatusPending)
+
+	if err = service.app.RunInTransaction(func(txApp core.App) error {
+		if entry.ActivityId != "" && entry.Point > 0 {
+			if err := service.checkBudget(txApp, entry.ActivityId, entry.Point); err != nil {
+				return err
+			}
+		}
+		return txApp.Save(ledger)
+	}); err != nil {
+		return nil, err
+	}
+	return ledger, nil
+}
+
+// send 调用摸鱼派接口变更积分，开发模式下跳过
+func (service *Service) send(entry Entry) error {
+	var err error
+	if service.app.IsDev() {
+		service.logger.Warn("[DEV] 开发模式，跳过实际积分发放",
+			slog.String("userName", entry.User.Name()),
+			slog.Int("point", entry.Point),
+			slog.String("memo", entry.Memo),
+		)
+	} else {
+		resp, sdkErr := service.sdk.PostUserEditPoints(entry.User.Name(), entry.Point, entry.Memo)
+		if sdkErr != nil {
+			err = sdkErr
+		} else if resp.Code != 0 {
+			err = errors.New(resp.Msg)
+		}
+	}
+	return err
+}
+
+// settle 按发放结果结算 pending 流水
+func (service *Service) settle(ledger *model.PointLedger, payErr error) error {
+	ledger.SetStatus(model.PointLedgerStatusSuccess)
+	if payErr != nil {
+		ledger.SetStatus(model.PointLedgerStatusFailed)
+		ledger.SetError(payErr.Error())
+	}
+
+	if err := service.app.Save(ledger); err != nil {
+		service.logger.Error("结算积分流水失败",
+			slog.String("ledgerId", ledger.Id),
+			slog.String("transactionNo", ledger.TransactionNo()),
+			slog.Int("point", ledger.Point()),
+			slog.Bool("paid", payErr == nil),
+			slog.Any("err", err),
+		)
+		return err
+	}
+	return nil
+}
+
+// Committed 活动已发放、发放中与超时未结算的积分总额，后两者已预留预算
+func (service *Service) Committed(app core.App, activityId string) (int, error) {
+	var total int
+	if err := app.DB().
+		Select("COALESCE(SUM([[point]]), 0)").
+		From(model.DbNamePointLedgers).
+		Where(dbx.HashExp{model.PointLedgersFieldActivityId: activityId}).
+		AndWhere(dbx.In(model.PointLedgersFieldStatus,
+			model.PointLedgerStatusSuccess.String(),
+			model.PointLedgerStatusPending.String(),
+			model.PointLedgerStatusUnsettled.String(),
+		)).
+		Row(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// CheckBudget 检查再发放 amount 积分是否超出活动预算，未设置预算时不限制
+func (service *Service) CheckBudget(activityId string, amount int) error {
+	return service.checkBudget(service.app, activityId, amount)
+}
+
+// checkBudget 在指定的 app（可以是事务）中检查预算
+func (service *Service) checkBudget(app core.App, activityId string, amount int) error {
+	activity := new(model.Activity)
+	if err := app.RecordQuery(model.DbNameActivities).
+		Where(dbx.HashExp{model.CommonFieldId: activityId}).
+		One(activity); err != nil {
+		return err
+	}
+	if activity.GetPointBudget() <= 0 {
+		return nil
+	}
+
+	committed, err := service.Committed(app, activityId)
+	if err != nil {
+		return err
+	}
+	if committed+amount > activity.GetPointBudget() {
+		return fmt.Errorf("%w：预算 %d，已发放及发放中 %d，本次 %d", ErrBudgetExceeded, activity.GetPointBudget(), committed, amount)
+	}
+	return nil
+}
+
+// sumBy 按字段分组汇总积分
+func (service *Service) sumBy(table string, field string, where dbx.Expression) (map[string]int, error) {
+	var rows []struct {
+		Key   string `db:"groupKey"`
+		Total int    `db:"total"`
+	}
+	if err := service.app.DB().
+		Select(fmt.Sprintf("[[%s]] AS groupKey", field), "COALESCE(SUM([[point]]), 0) AS total").
+		From(table).
+		Where(where).
+		GroupBy(field).
+		All(&rows); err != nil {
+		return nil, err
+	}
+
+	sums := make(map[string]int, len(rows))
+	for _, row := range rows {
+		sums[row.Key] = row.Total
+	}
+	return sums, nil
+}
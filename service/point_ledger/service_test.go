@@ -0,0 +1,205 @@
+package point_ledger
+
+import (
+	"bless-activity/model"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// newTestService 创建只包含活动、配置与积分流水集合的测试服务，活动预算为 budget
+func newTestService(t *testing.T, budget int) (*Service, string) {
+	app, err := tests.NewTestAppWithConfig(core.BaseAppConfig{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(app.Cleanup)
+
+	activities := core.NewBaseCollection(model.DbNameActivities)
+	activities.Fields.Add(&core.NumberField{Name: model.ActivitiesFieldPointBudget})
+	ledgers := core.NewBaseCollection(model.DbNamePointLedgers)
+	ledgers.Fields.Add(
+		&core.TextField{Name: model.PointLedgersFieldActivityId},
+		&core.TextField{Name: model.PointLedgersFieldUserId},
+		&core.NumberField{Name: model.PointLedgersFieldPoint},
+		&core.TextField{Name: model.PointLedgersFieldTransactionNo},
+		&core.TextField{Name: model.PointLedgersFieldSource},
+		&core.TextField{Name: model.PointLedgersFieldStatus},
+		&core.TextField{Name: model.PointLedgersFieldError},
+		&core.TextField{Name: model.PointLedgersFieldOperatorId},
+		&core.AutodateField{Name: model.PointLedgersFieldCreated, OnCreate: true},
+	)
+	configs := core.NewBaseCollection(model.DbNameConfigs)
+	configs.Fields.Add(
+		&core.TextField{Name: model.ConfigsFieldKey},
+		&core.TextField{Name: model.ConfigsFieldValue},
+	)
+	for _, collection := range []*core.Collection{activities, ledgers, configs} {
+		if err = app.Save(collection); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	activity := core.NewRecord(activities)
+	activity.Set(model.ActivitiesFieldPointBudget, budget)
+	if err = app.Save(activity); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewService(app, nil), activity.Id
+}
+
+func testEntry(activityId string, point int) Entry {
+	user := model.NewUser(core.NewRecord(core.NewAuthCollection(model.DbNameUsers)))
+	user.Id = "user1"
+	return Entry{
+		ActivityId:    activityId,
+		User:          user,
+		Point:         point,
+		TransactionNo: "tx",
+		Source:        model.PointLedgerSourcePoint,
+	}
+}
+
+func TestReserveCountsPendingAgainstBudget(t *testing.T) {
+	service, activityId := newTestService(t, 100)
+
+	// 第一笔预留后尚未结算，仍然占用预算
+	ledger, err := service.reserve(testEntry(activityId, 60))
+	if err != nil {
+		t.Fatalf("first reserve: %v", err)
+	}
+	if ledger.Status() != model.PointLedgerStatusPending {
+		t.Fatalf("expected pending ledger, got %s", ledger.Status())
+	}
+	if _, err = service.reserve(testEntry(activityId, 60)); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("second reserve: expected ErrBudgetExceeded, got %v", err)
+	}
+
+	// 发放失败结算后释放预算
+	if err = service.settle(ledger, errors.New("network error")); err != nil {
+		t.Fatal(err)
+	}
+	if committed, err := service.Committed(service.app, activityId); err != nil || committed != 0 {
+		t.Fatalf("expected 0 committed after failure, got %d (%v)", committed, err)
+	}
+	if _, err = service.reserve(testEntry(activityId, 60)); err != nil {
+		t.Fatalf("reserve after failure: %v", err)
+	}
+}
+
+func TestReserveConcurrent(t *testing.T) {
+	service, activityId := newTestService(t, 100)
+
+	// 10 笔并发预留各 20 积分，预算只够其中 5 笔
+	var wg sync.WaitGroup
+	var reserved atomic.Int32
+	for range 10 {
+		wg.Go(func() {
+			if _, err := service.reserve(testEntry(activityId, 20)); err == nil {
+				reserved.Add(1)
+			} else if !errors.Is(err, ErrBudgetExceeded) {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if reserved.Load() != 5 {
+		t.Errorf("expected 5 reservations, got %d", reserved.Load())
+	}
+}
+
+func TestNeedsApprovalCountsOperatorWindow(t *testing.T) {
+	service, activityId := newTestService(t, 1000)
+
+	configs, err := service.app.FindCollectionByNameOrId(model.DbNameConfigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := core.NewRecord(configs)
+	config.Set(model.ConfigsFieldKey, model.ConfigKeyPayoutApproval)
+	config.Set(model.ConfigsFieldValue, `{"maxPoints":100,"maxRecipients":5}`)
+	if err = service.app.Save(config); err != nil {
+		t.Fatal(err)
+	}
+
+	if service.NeedsApproval("admin1", 60, 1) {
+		t.Fatal("single payout under threshold should not need approval")
+	}
+
+	// 同一管理员已发起的发放计入累计额度，失败的发放不计入
+	entry := testEntry(activityId, 60)
+	entry.OperatorId = "admin1"
+	ledger, err := service.reserve(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !service.NeedsApproval("admin1", 60, 1) {
+		t.Error("expected cumulative payouts of the same admin to need approval")
+	}
+	if service.NeedsApproval("admin2", 60, 1) {
+		t.Error("payouts of another admin should not be counted")
+	}
+	if service.NeedsApproval("", 60, 1) {
+		t.Error("approved payouts should not be counted")
+	}
+
+	if err = service.settle(ledger, errors.New("network error")); err != nil {
+		t.Fatal(err)
+	}
+	if service.NeedsApproval("admin1", 60, 1) {
+		t.Error("failed payouts should not be counted")
+	}
+}
+
+func TestReconcilePendingMarksStaleLedgers(t *testing.T) {
+	service, activityId := newTestService(t, 100)
+
+	stale, err := service.reserve(testEntry(activityId, 30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fresh, err := service.reserve(testEntry(activityId, 30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 模拟发放进程在结算前中断：流水停留在 pending 超过超时时间
+	if _, err = service.app.DB().Update(model.DbNamePointLedgers, dbx.Params{
+		model.PointLedgersFieldCreated: types.NowDateTime().Add(-time.Hour).String(),
+	}, dbx.HashExp{model.CommonFieldId: stale.Id}).Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	marked, err := service.ReconcilePending(10 * time.Minute)
+	if err != nil || marked != 1 {
+		t.Fatalf("expected 1 stale ledger marked, got %d (%v)", marked, err)
+	}
+	for id, expected := range map[string]model.PointLedgerStatus{
+		stale.Id: model.PointLedgerStatusUnsettled,
+		fresh.Id: model.PointLedgerStatusPending,
+	} {
+		record, err := service.app.FindRecordById(model.DbNamePointLedgers, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status := model.NewPointLedger(record).Status(); status != expected {
+			t.Errorf("ledger %s: expected %s, got %s", id, expected, status)
+		}
+	}
+
+	// 无法确认是否到账，超时未结算的流水继续占用预算
+	if committed, err := service.Committed(service.app, activityId); err != nil || committed != 60 {
+		t.Fatalf("expected 60 committed, got %d (%v)", committed, err)
+	}
+	if marked, err = service.ReconcilePending(10 * time.Minute); err != nil || marked != 0 {
+		t.Errorf("expected nothing left to reconcile, got %d (%v)", marked, err)
+	}
+}
@@ -0,0 +1,237 @@
+package vote_chain
+
+import (
+	"bless-activity/model"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+const receiptAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+var ErrReceiptNotFound = errors.New("回执不存在")
+
+// Verification 回执校验结果
+type Verification struct {
+	VoteId     string                `json:"voteId"`
+	Source     model.VoteChainSource `json:"source"`
+	Seq        int                   `json:"seq"`        // 投票在链上的序号
+	Status     model.VoteChainKind   `json:"status"`     // 该回执最新的操作类型
+	LogIntact  bool                  `json:"logIntact"`  // 投票日志与链上摘要一致
+	ChainValid bool                  `json:"chainValid"` // 整条链哈希连续
+	BrokenAt   int                   `json:"brokenAt"`   // 链断裂的序号，完整时为0
+	HeadSeq    int                   `json:"headSeq"`
+	HeadHash   string                `json:"headHash"`
+}
+
+type Service struct {
+	app core.App
+
+	logger *slog.Logger
+}
+
+func NewService(app core.App) *Service {
+	service := &Service{
+		app:    app,
+		logger: app.Logger().WithGroup("service.vote_chain"),
+	}
+	return service
+}
+
+// NewReceipt 生成投票回执
+func NewReceipt() string {
+	return security.RandomStringWithAlphabet(32, receiptAlphabet)
+}
+
+func sum(parts ...string) string {
+	hash := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(hash[:])
+}
+
+// VoteLogDigest 普通投票日志摘要，包含有效性以便发现后台直接修改
+func VoteLogDigest(voteLog *model.VoteLog) string {
+	return sum(
+		voteLog.Receipt(),
+		voteLog.VoteId(),
+		voteLog.Id,
+		voteLog.FromUserId(),
+		voteLog.ToUserId(),
+		strconv.FormatFloat(voteLog.Weight(), 'f', -1, 64),
+		voteLog.GetString(model.VoteLogsFieldValid),
+	)
+}
+
+// JuryLogDigest 评审团投票日志摘要
+func JuryLogDigest(juryLog *model.VoteJuryLog) string {
+	return sum(
+		juryLog.Receipt(),
+		juryLog.VoteId(),
+		juryLog.Id,
+		juryLog.FromUserId(),
+		juryLog.ToUserId(),
+		strconv.Itoa(juryLog.Times()),
+		strconv.Itoa(juryLog.Round()),
+	)
+}
+
+func chainHash(chain *model.VoteChain) string {
+	return sum(
+		chain.PrevHash(),
+		strconv.Itoa(chain.Seq()),
+		chain.GetString(model.VoteChainsFieldKind),
+		chain.GetString(model.VoteChainsFieldSource),
+		chain.LogId(),
+		chain.Digest(),
+	)
+}
+
+// AppendVoteLog 追加普通投票日志到哈希链，需在保存日志的同一事务中调用
+func (service *Service) AppendVoteLog(txApp core.App, kind model.VoteChainKind, voteLog *model.VoteLog) error {
+	return service.append(txApp, voteLog.VoteId(), kind, model.VoteChainSourceVoteLog, voteLog.Id, voteLog.Receipt(), VoteLogDigest(voteLog))
+}
+
+// AppendJuryLog 追加评审团投票日志到哈希链，需在保存日志的同一事务中调用
+func (service *Service) AppendJuryLog(txApp core.App, kind model.VoteChainKind, juryLog *model.VoteJuryLog) error {
+	return service.append(txApp, juryLog.VoteId(), kind, model.VoteChainSourceJuryLog, juryLog.Id, juryLog.Receipt(), JuryLogDigest(juryLog))
+}
+
+func (service *Service) append(txApp core.App, voteId string, kind model.VoteChainKind, source model.VoteChainSource, logId string, receipt string, digest string) error {
+	head, err := service.head(txApp, voteId)
+	if err != nil {
+		return err
+	}
+
+	collection, err := txApp.FindCollectionByNameOrId(model.DbNameVoteChains)
+	if err != nil {
+		return err
+	}
+
+	chain := model.NewVoteChainFromCollection(collection)
+	chain.SetVoteId(voteId)
+	chain.SetKind(kind)
+	chain.SetSource(source)
+	chain.SetLogId(logId)
+	chain.SetReceipt(receipt)
+	chain.SetDigest(digest)
+	if head != nil {
+		chain.SetSeq(head.Seq() + 1)
+		chain.SetPrevHash(head.Hash())
+	} else {
+		chain.SetSeq(1)
+	}
+	chain.SetHash(chainHash(chain))
+
+	return txApp.Save(chain)
+}
+
+func (service *Service) head(app core.App, voteId string) (*model.VoteChain, error) {
+	head := new(model.VoteChain)
+	if err := app.RecordQuery(model.DbNameVoteChains).
+		Where(dbx.HashExp{model.VoteChainsFieldVoteId: voteId}).
+		OrderBy(fmt.Sprintf("%s desc", model.VoteChainsFieldSeq)).
+		Limit(1).
+		One(head); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return head, nil
+}
+
+// Head 获取投票哈希链的链头，没有记录时返回 nil
+func (service *Service) Head(voteId string) (*model.VoteChain, error) {
+	return service.head(service.app, voteId)
+}
+
+// Verify 校验回执是否在链上、日志是否被改动以及整条链是否完整
+func (service *Service) Verify(receipt string) (*Verification, error) {
+	var entries []*model.VoteChain
+	if err := service.app.RecordQuery(model.DbNameVoteChains).
+		Where(dbx.HashExp{model.VoteChainsFieldReceipt: receipt}).
+		OrderBy(fmt.Sprintf("%s asc", model.VoteChainsFieldSeq)).
+		All(&entries); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrReceiptNotFound
+	}
+
+	first := entries[0]
+	latest := entries[len(entries)-1]
+	verification := &Verification{
+		VoteId: first.VoteId(),
+		Source: first.Source(),
+		Seq:    first.Seq(),
+		Status: latest.Kind(),
+	}
+
+	// 比对当前日志与链上最新摘要
+	currentDigest, exists, err := service.currentDigest(latest)
+	if err != nil {
+		return nil, err
+	}
+	if latest.Kind() == model.VoteChainKindRevoke {
+		verification.LogIntact = !exists
+	} else {
+		verification.LogIntact = exists && currentDigest == latest.Digest()
+	}
+
+	// 从头重算整条链
+	var chain []*model.VoteChain
+	if err := service.app.RecordQuery(model.DbNameVoteChains).
+		Where(dbx.HashExp{model.VoteChainsFieldVoteId: verification.VoteId}).
+		OrderBy(fmt.Sprintf("%s asc", model.VoteChainsFieldSeq)).
+		All(&chain); err != nil {
+		return nil, err
+	}
+
+	verification.ChainValid = true
+	prevHash := ""
+	for i, entry := range chain {
+		if entry.Seq() != i+1 || entry.PrevHash() != prevHash || chainHash(entry) != entry.Hash() {
+			verification.ChainValid = false
+			verification.BrokenAt = i + 1
+			break
+		}
+		prevHash = entry.Hash()
+	}
+	if len(chain) > 0 {
+		verification.HeadSeq = chain[len(chain)-1].Seq()
+		verification.HeadHash = chain[len(chain)-1].Hash()
+	}
+
+	return verification, nil
+}
+
+func (service *Service) currentDigest(entry *model.VoteChain) (string, bool, error) {
+	switch entry.Source() {
+	case model.VoteChainSourceJuryLog:
+		juryLog := new(model.VoteJuryLog)
+		if err := service.app.RecordQuery(model.DbNameVoteJuryLogs).Where(dbx.HashExp{model.CommonFieldId: entry.LogId()}).One(juryLog); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return "", false, nil
+			}
+			return "", false, err
+		}
+		return JuryLogDigest(juryLog), true, nil
+	default:
+		voteLog := new(model.VoteLog)
+		if err := service.app.RecordQuery(model.DbNameVoteLogs).Where(dbx.HashExp{model.CommonFieldId: entry.LogId()}).One(voteLog); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return "", false, nil
+			}
+			return "", false, err
+		}
+		return VoteLogDigest(voteLog), true, nil
+	}
+}
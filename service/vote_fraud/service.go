@@ -0,0 +1,420 @@
+package vote_fraud
+
+import (
+	"bless-activity/model"
+	"bless-activity/service/vote_chain"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// 可疑投票标记
+const (
+	FlagBurst       = "burst"        // 短时间内集中投给同一目标
+	FlagSharedIp    = "shared_ip"    // 多个账号共用同一IP
+	FlagSharedPrint = "shared_print" // 多个账号共用同一IP与User-Agent指纹
+	FlagMutual      = "mutual"       // 互投
+	FlagRing        = "ring"         // 三人及以上的环形互投
+	FlagFreshUser   = "fresh_user"   // 投票前刚注册的账号
+)
+
+// Options 检测阈值
+type Options struct {
+	BurstWindow      time.Duration // 集中投票时间窗口
+	BurstCount       int           // 时间窗口内同一目标的票数阈值
+	SharedIpUsers    int           // 共用同一IP的账号数阈值
+	SharedPrintUsers int           // 共用同一指纹的账号数阈值
+	FreshUserDays    int           // 投票时注册天数低于该值视为新账号
+}
+
+func DefaultOptions() Options {
+	return Options{
+		BurstWindow:      10 * time.Minute,
+		BurstCount:       5,
+		SharedIpUsers:    3,
+		SharedPrintUsers: 2,
+		FreshUserDays:    7,
+	}
+}
+
+// Flagged 被标记的投票记录
+type Flagged struct {
+	VoteLog *model.VoteLog
+	Flags   []string
+}
+
+// HashUserAgent 计算User-Agent指纹
+func HashUserAgent(userAgent string) string {
+	if userAgent == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(userAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+type Service struct {
+	app       core.App
+	voteChain *vote_chain.Service
+
+	secretLock sync.Mutex
+	secret     []byte
+
+	logger *slog.Logger
+}
+
+func NewService(app core.App, voteChain *vote_chain.Service) *Service {
+	service := &Service{
+		app:       app,
+		voteChain: voteChain,
+		logger:    app.Logger().WithGroup("service.vote_fraud"),
+	}
+	return service
+}
+
+// HashIp 计算投票来源IP指纹，使用服务端密钥做 HMAC 并按投票区分，避免穷举还原IP或跨投票关联
+func (service *Service) HashIp(voteId string, ip string) (string, error) {
+	secret, err := service.fingerprintSecret()
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(voteId + "|" + ip))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// fingerprintSecret 读取指纹密钥，未配置时生成并保存
+func (service *Service) fingerprintSecret() ([]byte, error) {
+	service.secretLock.Lock()
+	defer service.secretLock.Unlock()
+
+	if service.secret != nil {
+		return service.secret, nil
+	}
+
+	var cfg model.VoteFingerprintConfig
+	err := service.app.RunInTransaction(func(txApp core.App) error {
+		record := new(model.Config)
+		err := txApp.RecordQuery(model.DbNameConfigs).
+			Where(dbx.HashExp{model.ConfigsFieldKey: model.ConfigKeyVoteFingerprint}).
+			One(record)
+		if err == nil {
+			return json.Unmarshal([]byte(record.Value()), &cfg)
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		buf := make([]byte, 32)
+		if _, err = rand.Read(buf); err != nil {
+			return err
+		}
+		cfg.Secret = hex.EncodeToString(buf)
+		value, err := json.Marshal(cfg)
+		if err != nil {
+			return err
+		}
+
+		collection, err := txApp.FindCollectionByNameOrId(model.DbNameConfigs)
+		if err != nil {
+			return err
+		}
+		config := model.NewConfigFromCollection(collection)
+		config.SetKey(model.ConfigKeyVoteFingerprint)
+		config.SetValue(string(value))
+		return txApp.Save(config)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Secret == "" {
+		return nil, errors.New("投票指纹密钥为空")
+	}
+
+	service.secret = []byte(cfg.Secret)
+	return service.secret, nil
+}
+
+// Detect 检测投票中的可疑记录，仅检测仍然有效的投票
+func (service *Service) Detect(voteId string, options Options) ([]*Flagged, error) {
+	var voteLogs []*model.VoteLog
+	if err := service.app.RecordQuery(model.DbNameVoteLogs).Where(dbx.HashExp{
+		model.VoteLogsFieldVoteId: voteId,
+		model.VoteLogsFieldValid:  model.VoteLogValidValid,
+	}).OrderBy(fmt.Sprintf("%s asc", model.VoteLogsFieldCreated)).All(&voteLogs); err != nil {
+		return nil, err
+	}
+
+	flags := make(map[string]map[string]bool)
+	mark := func(voteLog *model.VoteLog, flag string) {
+		if flags[voteLog.Id] == nil {
+			flags[voteLog.Id] = make(map[string]bool)
+		}
+		flags[voteLog.Id][flag] = true
+	}
+
+	service.detectBurst(voteLogs, options, mark)
+	service.detectSharedFingerprint(voteLogs, options, mark)
+	service.detectMutual(voteLogs, mark)
+	service.detectRing(voteLogs, mark)
+	if err := service.detectFreshUser(voteLogs, options, mark); err != nil {
+		return nil, err
+	}
+
+	result := make([]*Flagged, 0, len(flags))
+	for _, voteLog := range voteLogs {
+		logFlags, ok := flags[voteLog.Id]
+		if !ok {
+			continue
+		}
+		flagged := &Flagged{VoteLog: voteLog}
+		for flag := range logFlags {
+			flagged.Flags = append(flagged.Flags, flag)
+		}
+		sort.Strings(flagged.Flags)
+		result = append(result, flagged)
+	}
+
+	return result, nil
+}
+
+// detectBurst 同一目标在时间窗口内收到的票数超过阈值
+func (service *Service) detectBurst(voteLogs []*model.VoteLog, options Options, mark func(*model.VoteLog, string)) {
+	byTarget := make(map[string][]*model.VoteLog)
+	for _, voteLog := range voteLogs {
+		byTarget[voteLog.ToUserId()] = append(byTarget[voteLog.ToUserId()], voteLog)
+	}
+
+	for _, logs := range byTarget {
+		start := 0
+		for end := range logs {
+			for logs[end].Created().Time().Sub(logs[start].Created().Time()) > options.BurstWindow {
+				start++
+			}
+			if end-start+1 >= options.BurstCount {
+				for _, voteLog := range logs[start : end+1] {
+					mark(voteLog, FlagBurst)
+				}
+			}
+		}
+	}
+}
+
+// detectSharedFingerprint 多个账号共用同一IP或同一IP与User-Agent组合
+func (service *Service) detectSharedFingerprint(voteLogs []*model.VoteLog, options Options, mark func(*model.VoteLog, string)) {
+	ipUsers := make(map[string]map[string]bool)
+	printUsers := make(map[string]map[string]bool)
+	for _, voteLog := range voteLogs {
+		if ipUsers[voteLog.IpHash()] == nil {
+			ipUsers[voteLog.IpHash()] = make(map[string]bool)
+		}
+		ipUsers[voteLog.IpHash()][voteLog.FromUserId()] = true
+
+		fingerprint := voteLog.IpHash() + voteLog.UaHash()
+		if printUsers[fingerprint] == nil {
+			printUsers[fingerprint] = make(map[string]bool)
+		}
+		printUsers[fingerprint][voteLog.FromUserId()] = true
+	}
+
+	for _, voteLog := range voteLogs {
+		if len(ipUsers[voteLog.IpHash()]) >= options.SharedIpUsers {
+			mark(voteLog, FlagSharedIp)
+		}
+		if len(printUsers[voteLog.IpHash()+voteLog.UaHash()]) >= options.SharedPrintUsers {
+			mark(voteLog, FlagSharedPrint)
+		}
+	}
+}
+
+// detectMutual 两个用户互相投票
+func (service *Service) detectMutual(voteLogs []*model.VoteLog, mark func(*model.VoteLog, string)) {
+	edges := make(map[string]bool)
+	for _, voteLog := range voteLogs {
+		edges[voteLog.FromUserId()+"|"+voteLog.ToUserId()] = true
+	}
+
+	for _, voteLog := range voteLogs {
+		if voteLog.FromUserId() == voteLog.ToUserId() {
+			continue
+		}
+		if edges[voteLog.ToUserId()+"|"+voteLog.FromUserId()] {
+			mark(voteLog, FlagMutual)
+		}
+	}
+}
+
+// detectRing 投票关系图中三人及以上的强连通分量，分量内的每一张票都处在某个环上
+func (service *Service) detectRing(voteLogs []*model.VoteLog, mark func(*model.VoteLog, string)) {
+	graph := make(map[string][]string)
+	for _, voteLog := range voteLogs {
+		if voteLog.FromUserId() == voteLog.ToUserId() {
+			continue
+		}
+		graph[voteLog.FromUserId()] = append(graph[voteLog.FromUserId()], voteLog.ToUserId())
+	}
+
+	components := stronglyConnected(graph)
+	for _, voteLog := range voteLogs {
+		component, ok := components[voteLog.FromUserId()]
+		if !ok || component.size < 3 {
+			continue
+		}
+		if target, ok := components[voteLog.ToUserId()]; ok && target.id == component.id {
+			mark(voteLog, FlagRing)
+		}
+	}
+}
+
+type component struct {
+	id   int
+	size int
+}
+
+// stronglyConnected 使用 Tarjan 算法计算每个节点所属的强连通分量
+func stronglyConnected(graph map[string][]string) map[string]*component {
+	nodes := make([]string, 0, len(graph))
+	for node := range graph {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	var (
+		index    int
+		stack    []string
+		indices  = make(map[string]int)
+		lowLinks = make(map[string]int)
+		onStack  = make(map[string]bool)
+		result   = make(map[string]*component)
+	)
+
+	var connect func(node string)
+	connect = func(node string) {
+		indices[node] = index
+		lowLinks[node] = index
+		index++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, next := range graph[node] {
+			if _, visited := indices[next]; !visited {
+				connect(next)
+				lowLinks[node] = min(lowLinks[node], lowLinks[next])
+			} else if onStack[next] {
+				lowLinks[node] = min(lowLinks[node], indices[next])
+			}
+		}
+
+		if lowLinks[node] != indices[node] {
+			return
+		}
+		c := &component{id: indices[node]}
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			result[top] = c
+			c.size++
+			if top == node {
+				break
+			}
+		}
+	}
+
+	for _, node := range nodes {
+		if _, visited := indices[node]; !visited {
+			connect(node)
+		}
+	}
+	return result
+}
+
+// detectFreshUser 投票时注册时间不足阈值的账号
+func (service *Service) detectFreshUser(voteLogs []*model.VoteLog, options Options, mark func(*model.VoteLog, string)) error {
+	userIds := make([]any, 0, len(voteLogs))
+	seen := make(map[string]bool)
+	for _, voteLog := range voteLogs {
+		if !seen[voteLog.FromUserId()] {
+			seen[voteLog.FromUserId()] = true
+			userIds = append(userIds, voteLog.FromUserId())
+		}
+	}
+	if len(userIds) == 0 {
+		return nil
+	}
+
+	var users []*model.User
+	if err := service.app.RecordQuery(model.DbNameUsers).Where(dbx.In(model.CommonFieldId, userIds...)).All(&users); err != nil {
+		return err
+	}
+
+	registeredAt := make(map[string]types.DateTime, len(users))
+	for _, user := range users {
+		registeredAt[user.Id] = user.RegisteredAt()
+	}
+
+	freshDuration := time.Duration(options.FreshUserDays*24) * time.Hour
+	for _, voteLog := range voteLogs {
+		registered, ok := registeredAt[voteLog.FromUserId()]
+		if !ok || registered.IsZero() {
+			continue
+		}
+		if voteLog.Created().Time().Sub(registered.Time()) < freshDuration {
+			mark(voteLog, FlagFreshUser)
+		}
+	}
+
+	return nil
+}
+
+// Invalidate 将投票记录批量标记为无效，记录原因与操作的管理员
+func (service *Service) Invalidate(voteId string, voteLogIds []string, reason string, adminId string) (int, error) {
+	ids := make([]any, len(voteLogIds))
+	for i, id := range voteLogIds {
+		ids[i] = id
+	}
+
+	count := 0
+	err := service.app.RunInTransaction(func(txApp core.App) error {
+		var voteLogs []*model.VoteLog
+		if err := txApp.RecordQuery(model.DbNameVoteLogs).Where(dbx.HashExp{
+			model.VoteLogsFieldVoteId: voteId,
+		}).AndWhere(dbx.In(model.CommonFieldId, ids...)).All(&voteLogs); err != nil {
+			return err
+		}
+
+		for _, voteLog := range voteLogs {
+			voteLog.SetValid(model.VoteLogValidInvalid)
+			voteLog.SetInvalidReason(reason)
+			voteLog.SetInvalidatedBy(adminId)
+			voteLog.SetInvalidatedAt(types.NowDateTime())
+			if err := txApp.Save(voteLog); err != nil {
+				return err
+			}
+			if err := service.voteChain.AppendVoteLog(txApp, model.VoteChainKindInvalidate, voteLog); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	service.logger.Info("批量作废投票", slog.String("voteId", voteId), slog.Int("count", count), slog.String("reason", reason), slog.String("adminId", adminId))
+
+	return count, nil
+}
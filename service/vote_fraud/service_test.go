@@ -0,0 +1,76 @@
+package vote_fraud
+
+import (
+	"bless-activity/model"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func newVoteLogs(edges ...[2]string) []*model.VoteLog {
+	collection := core.NewBaseCollection(model.DbNameVoteLogs)
+	collection.Fields.Add(
+		&core.TextField{Name: model.VoteLogsFieldFromUserId},
+		&core.TextField{Name: model.VoteLogsFieldToUserId},
+	)
+
+	voteLogs := make([]*model.VoteLog, 0, len(edges))
+	for i, edge := range edges {
+		voteLog := model.NewVoteLogFromCollection(collection)
+		voteLog.Id = string(rune('a' + i))
+		voteLog.SetFromUserId(edge[0])
+		voteLog.SetToUserId(edge[1])
+		voteLogs = append(voteLogs, voteLog)
+	}
+	return voteLogs
+}
+
+func collect(voteLogs []*model.VoteLog, detect func([]*model.VoteLog, func(*model.VoteLog, string))) map[string][]string {
+	marked := make(map[string][]string)
+	detect(voteLogs, func(voteLog *model.VoteLog, flag string) {
+		marked[voteLog.Id] = append(marked[voteLog.Id], flag)
+	})
+	return marked
+}
+
+func TestDetectRing(t *testing.T) {
+	service := &Service{}
+
+	// a: u1→u2, b: u2→u3, c: u3→u1 组成环；d: u3→u4 不在环上；e: u5→u5 自投
+	voteLogs := newVoteLogs(
+		[2]string{"u1", "u2"},
+		[2]string{"u2", "u3"},
+		[2]string{"u3", "u1"},
+		[2]string{"u3", "u4"},
+		[2]string{"u5", "u5"},
+	)
+
+	marked := collect(voteLogs, service.detectRing)
+	for _, id := range []string{"a", "b", "c"} {
+		if len(marked[id]) != 1 || marked[id][0] != FlagRing {
+			t.Errorf("vote log %s: expected ring flag, got %v", id, marked[id])
+		}
+	}
+	for _, id := range []string{"d", "e"} {
+		if len(marked[id]) != 0 {
+			t.Errorf("vote log %s: expected no flag, got %v", id, marked[id])
+		}
+	}
+}
+
+func TestDetectRingIgnoresPairs(t *testing.T) {
+	service := &Service{}
+
+	// 两人互投由 detectMutual 标记，不计为环
+	voteLogs := newVoteLogs(
+		[2]string{"u1", "u2"},
+		[2]string{"u2", "u1"},
+	)
+
+	if marked := collect(voteLogs, service.detectRing); len(marked) != 0 {
+		t.Errorf("expected no ring flags, got %v", marked)
+	}
+	if marked := collect(voteLogs, service.detectMutual); len(marked) != 2 {
+		t.Errorf("expected both votes flagged mutual, got %v", marked)
+	}
+}
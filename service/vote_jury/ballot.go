@@ -0,0 +1,62 @@
+package vote_jury
+
+import (
+	"bless-activity/model"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var (
+	ErrNotSecretBallot = errors.New("当前评审团未启用秘密投票")
+	ErrSecretBallot    = errors.New("当前评审团为秘密投票，请先提交投票承诺，截止后再揭示")
+	ErrCommitClosed    = errors.New("投票承诺阶段已结束")
+	ErrRevealNotOpen   = errors.New("揭示阶段尚未开启")
+	ErrRevealOpened    = errors.New("揭示阶段已开启")
+	ErrCommitNotFound  = errors.New("本轮没有您的投票承诺")
+	ErrCommitMismatch  = errors.New("揭示的选票与投票承诺不一致")
+	ErrAlreadyRevealed = errors.New("本轮选票已揭示")
+)
+
+// BallotCommitment 计算选票承诺，客户端使用同样的方式计算后提交
+// sha256(voteId|round|被投票用户ID按提交顺序以逗号连接|nonce) 的十六进制小写
+func BallotCommitment(voteId string, round int, toUserIds []string, nonce string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		voteId,
+		strconv.Itoa(round),
+		strings.Join(toUserIds, ","),
+		nonce,
+	}, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+// OpenReveal 结束当前轮次的承诺阶段并开启揭示阶段
+func (service *Service) OpenReveal(rule *model.VoteJuryRule, trigger model.VoteJuryStatusTrigger, actorId string) error {
+	if !rule.SecretBallot() {
+		return ErrNotSecretBallot
+	}
+	if rule.Status() != model.VoteJuryRuleStatusVoting {
+		return ErrNotVoting
+	}
+	if rule.Revealing() {
+		return ErrRevealOpened
+	}
+
+	return service.app.RunInTransaction(func(txApp core.App) error {
+		rule.SetRevealing(true)
+		rule.SetRevealStart(types.NowDateTime())
+		if err := txApp.Save(rule); err != nil {
+			return err
+		}
+
+		service.logger.Info("开启揭示阶段", slog.String("voteId", rule.VoteId()), slog.Int("round", rule.CurrentRound()))
+
+		return service.saveStatusLog(txApp, rule, rule.Status(), trigger, actorId, "开启揭示阶段")
+	})
+}
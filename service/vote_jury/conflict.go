@@ -0,0 +1,181 @@
+package vote_jury
+
+import (
+	"bless-activity/model"
+	"errors"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+var (
+	ErrJurorIsCandidate = errors.New("该用户在本活动中有参赛作品，不能担任评审")
+	ErrSelfVote         = errors.New("不能给自己投票")
+	ErrDeclaredConflict = errors.New("您与该候选人存在利益冲突，不能为其投票")
+)
+
+// 利益冲突类型
+const (
+	ViolationCandidateJuror = "candidate_juror" // 评审团成员同时是参赛者
+	ViolationSelfVote       = "self_vote"       // 评审给自己投票
+	ViolationDeclared       = "declared"        // 评审给已声明冲突的候选人投票
+)
+
+// Violation 利益冲突违规记录
+type Violation struct {
+	Type        string `json:"type"`
+	JurorId     string `json:"jurorId"`
+	CandidateId string `json:"candidateId,omitempty"`
+	LogId       string `json:"logId,omitempty"`
+	Round       int    `json:"round,omitempty"`
+}
+
+// CandidateIds 获取投票所属活动中发表过作品的用户
+func (service *Service) CandidateIds(app core.App, voteId string) (map[string]bool, error) {
+	candidates := make(map[string]bool)
+
+	var activities []*model.Activity
+	if err := app.RecordQuery(model.DbNameActivities).
+		Where(dbx.HashExp{model.ActivitiesFieldVoteId: voteId}).
+		All(&activities); err != nil {
+		return nil, err
+	}
+	if len(activities) == 0 {
+		return candidates, nil
+	}
+
+	activityIds := make([]any, 0, len(activities))
+	for _, activity := range activities {
+		activityIds = append(activityIds, activity.Id)
+	}
+
+	var relArticles []*model.RelArticle
+	if err := app.RecordQuery(model.DbNameRelArticles).
+		Where(dbx.In(model.RelArticlesFieldActivityId, activityIds...)).
+		All(&relArticles); err != nil {
+		return nil, err
+	}
+	for _, relArticle := range relArticles {
+		candidates[relArticle.UserId()] = true
+	}
+
+	var articles []*model.Article
+	if err := app.RecordQuery(model.DbNameArticles).
+		Where(dbx.In(model.ArticlesFieldActivityId, activityIds...)).
+		All(&articles); err != nil {
+		return nil, err
+	}
+	for _, article := range articles {
+		candidates[article.UserId()] = true
+	}
+
+	return candidates, nil
+}
+
+// CheckJuror 检查用户能否担任评审团成员
+func (service *Service) CheckJuror(voteId string, userId string) error {
+	candidates, err := service.CandidateIds(service.app, voteId)
+	if err != nil {
+		return err
+	}
+	if candidates[userId] {
+		return ErrJurorIsCandidate
+	}
+	return nil
+}
+
+// CheckVote 检查评审能否给候选人投票
+func (service *Service) CheckVote(voteId string, jurorId string, candidateId string) error {
+	if jurorId == candidateId {
+		return ErrSelfVote
+	}
+
+	if err := service.CheckJuror(voteId, jurorId); err != nil {
+		return err
+	}
+
+	conflicts, err := service.Conflicts(voteId)
+	if err != nil {
+		return err
+	}
+	for _, conflict := range conflicts {
+		if conflict.JurorId() == jurorId && conflict.CandidateId() == candidateId {
+			return ErrDeclaredConflict
+		}
+	}
+
+	return nil
+}
+
+// Conflicts 获取投票登记的利益冲突声明
+func (service *Service) Conflicts(voteId string) ([]*model.VoteJuryConflict, error) {
+	var conflicts []*model.VoteJuryConflict
+	if err := service.app.RecordQuery(model.DbNameVoteJuryConflicts).
+		Where(dbx.HashExp{model.VoteJuryConflictFieldVoteId: voteId}).
+		OrderBy(model.VoteJuryConflictFieldCreated + " ASC").
+		All(&conflicts); err != nil {
+		return nil, err
+	}
+	return conflicts, nil
+}
+
+// Violations 检查已有成员与投票记录中的利益冲突，用于管理员复核
+func (service *Service) Violations(voteId string) ([]*Violation, error) {
+	candidates, err := service.CandidateIds(service.app, voteId)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts, err := service.Conflicts(voteId)
+	if err != nil {
+		return nil, err
+	}
+	declared := make(map[string]bool, len(conflicts))
+	for _, conflict := range conflicts {
+		declared[conflict.JurorId()+"|"+conflict.CandidateId()] = true
+	}
+
+	violations := make([]*Violation, 0)
+
+	var members []*model.VoteJuryUser
+	if err := service.app.RecordQuery(model.DbNameVoteJuryUsers).Where(dbx.HashExp{
+		model.VoteJuryUserFieldVoteId: voteId,
+		model.VoteJuryUserFieldStatus: model.VoteJuryUserStatusApproved,
+	}).All(&members); err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		if candidates[member.UserId()] {
+			violations = append(violations, &Violation{
+				Type:    ViolationCandidateJuror,
+				JurorId: member.UserId(),
+			})
+		}
+	}
+
+	var juryLogs []*model.VoteJuryLog
+	if err := service.app.RecordQuery(model.DbNameVoteJuryLogs).
+		Where(dbx.HashExp{model.VoteJuryLogFieldVoteId: voteId}).
+		All(&juryLogs); err != nil {
+		return nil, err
+	}
+	for _, juryLog := range juryLogs {
+		violation := &Violation{
+			JurorId:     juryLog.FromUserId(),
+			CandidateId: juryLog.ToUserId(),
+			LogId:       juryLog.Id,
+			Round:       juryLog.Round(),
+		}
+		switch {
+		case juryLog.FromUserId() == juryLog.ToUserId():
+			violation.Type = ViolationSelfVote
+		case declared[juryLog.FromUserId()+"|"+juryLog.ToUserId()]:
+			violation.Type = ViolationDeclared
+		default:
+			continue
+		}
+		violations = append(violations, violation)
+	}
+
+	return violations, nil
+}
@@ -0,0 +1,188 @@
+package vote_jury
+
+import (
+	"bless-activity/model"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/pocketbase/dbx"
+)
+
+var ErrParticipationTooLow = errors.New("往届评审参与率未达到要求")
+
+// JurorVoteRecord 用户在单次投票中担任评审的记录
+type JurorVoteRecord struct {
+	VoteId       string `json:"voteId"`
+	VoteName     string `json:"voteName"`
+	Rounds       int    `json:"rounds"`       // 已算票的轮次数
+	Participated int    `json:"participated"` // 投过票或评过分的轮次数
+}
+
+// JurorHistory 用户担任评审的历史记录
+type JurorHistory struct {
+	UserId            string            `json:"userId"`
+	Served            int               `json:"served"`            // 担任评审的投票数
+	Rounds            int               `json:"rounds"`            // 已算票的轮次数
+	Participated      int               `json:"participated"`      // 参与的轮次数
+	Abstentions       int               `json:"abstentions"`       // 弃权的轮次数
+	ParticipationRate float64           `json:"participationRate"` // 参与率，没有已算票的轮次时为1
+	Objections        int               `json:"objections"`        // 收到的异议数
+	UpheldObjections  int               `json:"upheldObjections"`  // 成立的异议数
+	Votes             []JurorVoteRecord `json:"votes"`
+}
+
+// JurorHistories 汇总用户在其他投票中担任评审的记录，excludeVoteId 为当前投票
+// 只统计已算票的轮次，进行中的轮次不计为弃权
+func (service *Service) JurorHistories(userIds []string, excludeVoteId string) (map[string]*JurorHistory, error) {
+	histories := make(map[string]*JurorHistory, len(userIds))
+	if len(userIds) == 0 {
+		return histories, nil
+	}
+
+	ids := make([]any, 0, len(userIds))
+	for _, userId := range userIds {
+		histories[userId] = &JurorHistory{UserId: userId, ParticipationRate: 1, Votes: make([]JurorVoteRecord, 0)}
+		ids = append(ids, userId)
+	}
+
+	var members []*model.VoteJuryUser
+	if err := service.app.RecordQuery(model.DbNameVoteJuryUsers).
+		Where(dbx.HashExp{model.VoteJuryUserFieldStatus: model.VoteJuryUserStatusApproved}).
+		AndWhere(dbx.In(model.VoteJuryUserFieldUserId, ids...)).
+		AndWhere(dbx.Not(dbx.HashExp{model.VoteJuryUserFieldVoteId: excludeVoteId})).
+		OrderBy(model.VoteJuryUserFieldCreated + " DESC").
+		All(&members); err != nil {
+		return nil, err
+	}
+
+	voteIds := make([]any, 0)
+	voteSeen := make(map[string]bool)
+	for _, member := range members {
+		if !voteSeen[member.VoteId()] {
+			voteSeen[member.VoteId()] = true
+			voteIds = append(voteIds, member.VoteId())
+		}
+	}
+
+	// 投票 -> 已算票的轮次
+	roundsByVote := make(map[string][]int)
+	voteNames := make(map[string]string)
+	// 投票|轮次|用户 -> 是否参与
+	participated := make(map[string]bool)
+	if len(voteIds) > 0 {
+		var results []*model.VoteJuryResult
+		if err := service.app.RecordQuery(model.DbNameVoteJuryResults).
+			Where(dbx.In(model.VoteJuryResultFieldVoteId, voteIds...)).
+			All(&results); err != nil {
+			return nil, err
+		}
+		for _, result := range results {
+			roundsByVote[result.VoteId()] = append(roundsByVote[result.VoteId()], result.Round())
+		}
+
+		var votes []*model.Vote
+		if err := service.app.RecordQuery(model.DbNameVotes).
+			Where(dbx.In(model.CommonFieldId, voteIds...)).
+			All(&votes); err != nil {
+			return nil, err
+		}
+		for _, vote := range votes {
+			voteNames[vote.Id] = vote.Name()
+		}
+
+		var logs []*model.VoteJuryLog
+		if err := service.app.RecordQuery(model.DbNameVoteJuryLogs).
+			Where(dbx.In(model.VoteJuryLogFieldVoteId, voteIds...)).
+			AndWhere(dbx.In(model.VoteJuryLogFieldFromUserId, ids...)).
+			All(&logs); err != nil {
+			return nil, err
+		}
+		for _, log := range logs {
+			participated[participationKey(log.VoteId(), log.Round(), log.FromUserId())] = true
+		}
+
+		var scores []*model.VoteJuryScore
+		if err := service.app.RecordQuery(model.DbNameVoteJuryScores).
+			Where(dbx.In(model.VoteJuryScoreFieldVoteId, voteIds...)).
+			AndWhere(dbx.In(model.VoteJuryScoreFieldFromUserId, ids...)).
+			All(&scores); err != nil {
+			return nil, err
+		}
+		for _, score := range scores {
+			participated[participationKey(score.VoteId(), score.Round(), score.FromUserId())] = true
+		}
+	}
+
+	for _, member := range members {
+		history := histories[member.UserId()]
+		record := JurorVoteRecord{
+			VoteId:   member.VoteId(),
+			VoteName: voteNames[member.VoteId()],
+		}
+		for _, round := range roundsByVote[member.VoteId()] {
+			record.Rounds++
+			if participated[participationKey(member.VoteId(), round, member.UserId())] {
+				record.Participated++
+			}
+		}
+
+		history.Served++
+		history.Rounds += record.Rounds
+		history.Participated += record.Participated
+		history.Votes = append(history.Votes, record)
+	}
+
+	var objections []*model.VoteJuryObjection
+	if err := service.app.RecordQuery(model.DbNameVoteJuryObjections).
+		Where(dbx.In(model.VoteJuryObjectionFieldJurorId, ids...)).
+		All(&objections); err != nil {
+		return nil, err
+	}
+	for _, objection := range objections {
+		history := histories[objection.JurorId()]
+		history.Objections++
+		if objection.Status() == model.VoteJuryObjectionStatusResolved {
+			history.UpheldObjections++
+		}
+	}
+
+	for _, history := range histories {
+		history.Abstentions = history.Rounds - history.Participated
+		if history.Rounds > 0 {
+			history.ParticipationRate = math.Round(float64(history.Participated)/float64(history.Rounds)*100) / 100
+		}
+	}
+
+	return histories, nil
+}
+
+// JurorHistory 获取单个用户担任评审的历史记录
+func (service *Service) JurorHistory(userId string, excludeVoteId string) (*JurorHistory, error) {
+	histories, err := service.JurorHistories([]string{userId}, excludeVoteId)
+	if err != nil {
+		return nil, err
+	}
+	return histories[userId], nil
+}
+
+// CheckParticipation 检查申请人往届评审的参与率是否满足评审团要求
+func (service *Service) CheckParticipation(rule *model.VoteJuryRule, userId string) error {
+	minRate := rule.MinParticipationRate()
+	if minRate <= 0 {
+		return nil
+	}
+
+	history, err := service.JurorHistory(userId, rule.VoteId())
+	if err != nil {
+		return err
+	}
+	if history.Rounds > 0 && history.ParticipationRate < minRate {
+		return fmt.Errorf("%w：要求 %.0f%%，当前 %.0f%%", ErrParticipationTooLow, minRate*100, history.ParticipationRate*100)
+	}
+	return nil
+}
+
+func participationKey(voteId string, round int, userId string) string {
+	return fmt.Sprintf("%s|%d|%s", voteId, round, userId)
+}
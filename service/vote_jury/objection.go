@@ -0,0 +1,198 @@
+package vote_jury
+
+import (
+	"bless-activity/model"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var (
+	ErrObjectionClosed     = errors.New("只能在公示阶段提出异议")
+	ErrObjectionIneligible = errors.New("注册时间不足，暂不能提出异议")
+	ErrObjectionSelf       = errors.New("不能对自己提出异议")
+	ErrObjectionNotJuror   = errors.New("该用户不是评审团成员")
+	ErrObjectionExists     = errors.New("您对该评审的异议正在处理中")
+	ErrObjectionHandled    = errors.New("该异议已处理")
+	ErrObjectionStatus     = errors.New("处理结果无效")
+	ErrOpenObjections      = errors.New("还有未处理的异议，处理完成后才能进入评审")
+)
+
+// FileObjection 在公示阶段对评审团成员提出异议，提出人需满足投票的注册天数要求
+func (service *Service) FileObjection(rule *model.VoteJuryRule, user *model.User, jurorId string, reason string) (*model.VoteJuryObjection, error) {
+	if rule.Status() != model.VoteJuryRuleStatusPublicity {
+		return nil, ErrObjectionClosed
+	}
+	if user.Id == jurorId {
+		return nil, ErrObjectionSelf
+	}
+
+	vote := new(model.Vote)
+	if err := service.app.RecordQuery(model.DbNameVotes).Where(dbx.HashExp{model.CommonFieldId: rule.VoteId()}).One(vote); err != nil {
+		return nil, err
+	}
+	if days := vote.UserRegisterDays(); days > 0 {
+		registeredAt := user.RegisteredAt()
+		if registeredAt.IsZero() || time.Now().Before(registeredAt.Time().Add(time.Duration(days*24)*time.Hour)) {
+			return nil, ErrObjectionIneligible
+		}
+	}
+
+	total, err := service.app.CountRecords(model.DbNameVoteJuryUsers, dbx.HashExp{
+		model.VoteJuryUserFieldVoteId: rule.VoteId(),
+		model.VoteJuryUserFieldUserId: jurorId,
+		model.VoteJuryUserFieldStatus: model.VoteJuryUserStatusApproved,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if total == 0 {
+		return nil, ErrObjectionNotJuror
+	}
+
+	if total, err = service.app.CountRecords(model.DbNameVoteJuryObjections, dbx.HashExp{
+		model.VoteJuryObjectionFieldVoteId:  rule.VoteId(),
+		model.VoteJuryObjectionFieldJurorId: jurorId,
+		model.VoteJuryObjectionFieldUserId:  user.Id,
+		model.VoteJuryObjectionFieldStatus:  model.VoteJuryObjectionStatusPending,
+	}); err != nil {
+		return nil, err
+	}
+	if total > 0 {
+		return nil, ErrObjectionExists
+	}
+
+	collection, err := service.app.FindCollectionByNameOrId(model.DbNameVoteJuryObjections)
+	if err != nil {
+		return nil, err
+	}
+
+	objection := model.NewVoteJuryObjectionFromCollection(collection)
+	objection.SetVoteId(rule.VoteId())
+	objection.SetJurorId(jurorId)
+	objection.SetUserId(user.Id)
+	objection.SetReason(reason)
+	objection.SetStatus(model.VoteJuryObjectionStatusPending)
+	if err = service.app.Save(objection); err != nil {
+		return nil, err
+	}
+
+	service.logger.Info("评审团收到异议",
+		slog.String("voteId", rule.VoteId()),
+		slog.String("jurorId", jurorId),
+		slog.String("userId", user.Id),
+	)
+
+	return objection, nil
+}
+
+// ResolveObjection 处理异议，异议成立时可同时将评审移出评审团，
+// 移出后针对该评审的其他待处理异议一并标记为成立
+func (service *Service) ResolveObjection(rule *model.VoteJuryRule, objectionId string, status model.VoteJuryObjectionStatus, note string, removeJuror bool, adminId string) (*model.VoteJuryObjection, error) {
+	if status != model.VoteJuryObjectionStatusResolved && status != model.VoteJuryObjectionStatusRejected {
+		return nil, ErrObjectionStatus
+	}
+
+	objection := new(model.VoteJuryObjection)
+	if err := service.app.RecordQuery(model.DbNameVoteJuryObjections).Where(dbx.HashExp{
+		model.CommonFieldId:                objectionId,
+		model.VoteJuryObjectionFieldVoteId: rule.VoteId(),
+	}).One(objection); err != nil {
+		return nil, err
+	}
+	if objection.Status() != model.VoteJuryObjectionStatusPending {
+		return nil, ErrObjectionHandled
+	}
+
+	removeJuror = removeJuror && status == model.VoteJuryObjectionStatusResolved
+
+	err := service.app.RunInTransaction(func(txApp core.App) error {
+		objection.SetStatus(status)
+		objection.SetNote(note)
+		objection.SetAdminId(adminId)
+		objection.SetRemoved(removeJuror)
+		objection.SetResolvedAt(types.NowDateTime())
+		if err := txApp.Save(objection); err != nil {
+			return err
+		}
+
+		if !removeJuror {
+			return nil
+		}
+
+		juryUser := new(model.VoteJuryUser)
+		if err := txApp.RecordQuery(model.DbNameVoteJuryUsers).Where(dbx.HashExp{
+			model.VoteJuryUserFieldVoteId: rule.VoteId(),
+			model.VoteJuryUserFieldUserId: objection.JurorId(),
+		}).One(juryUser); err != nil {
+			return err
+		}
+		juryUser.SetStatus(model.VoteJuryUserStatusRejected)
+		if err := txApp.Save(juryUser); err != nil {
+			return err
+		}
+
+		var others []*model.VoteJuryObjection
+		if err := txApp.RecordQuery(model.DbNameVoteJuryObjections).Where(dbx.HashExp{
+			model.VoteJuryObjectionFieldVoteId:  rule.VoteId(),
+			model.VoteJuryObjectionFieldJurorId: objection.JurorId(),
+			model.VoteJuryObjectionFieldStatus:  model.VoteJuryObjectionStatusPending,
+		}).All(&others); err != nil {
+			return err
+		}
+		for _, other := range others {
+			other.SetStatus(model.VoteJuryObjectionStatusResolved)
+			other.SetNote(note)
+			other.SetAdminId(adminId)
+			other.SetRemoved(true)
+			other.SetResolvedAt(types.NowDateTime())
+			if err := txApp.Save(other); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	service.logger.Info("评审团异议已处理",
+		slog.String("voteId", rule.VoteId()),
+		slog.String("objectionId", objection.Id),
+		slog.String("status", status.String()),
+		slog.Bool("removed", removeJuror),
+	)
+
+	return objection, nil
+}
+
+// Objections 获取投票的全部异议
+func (service *Service) Objections(voteId string) ([]*model.VoteJuryObjection, error) {
+	var objections []*model.VoteJuryObjection
+	if err := service.app.RecordQuery(model.DbNameVoteJuryObjections).
+		Where(dbx.HashExp{model.VoteJuryObjectionFieldVoteId: voteId}).
+		OrderBy(model.VoteJuryObjectionFieldCreated + " ASC").
+		All(&objections); err != nil {
+		return nil, err
+	}
+	return objections, nil
+}
+
+// checkObjections 公示结束进入评审前，所有异议都必须处理完成
+func (service *Service) checkObjections(txApp core.App, voteId string) error {
+	total, err := txApp.CountRecords(model.DbNameVoteJuryObjections, dbx.HashExp{
+		model.VoteJuryObjectionFieldVoteId: voteId,
+		model.VoteJuryObjectionFieldStatus: model.VoteJuryObjectionStatusPending,
+	})
+	if err != nil {
+		return err
+	}
+	if total > 0 {
+		return ErrOpenObjections
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package vote_jury
+
+import (
+	"bless-activity/model"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/pocketbase/dbx"
+)
+
+var (
+	ErrJuryNoResult    = errors.New("评审团尚未算票")
+	ErrJuryNotFinished = errors.New("评审团尚未决出最终名次")
+)
+
+// ParseRanking 解析算票结果中的名次
+func ParseRanking(result *model.VoteJuryResult) (*model.VoteJuryRanking, error) {
+	ranking := new(model.VoteJuryRanking)
+	if err := json.Unmarshal([]byte(result.Ranking()), ranking); err != nil {
+		return nil, err
+	}
+	return ranking, nil
+}
+
+// FinalRanking 获取评审团的最终名次
+// 名次在每轮算票后累积，最后一轮结果中的名次已包含各加赛轮次决出的名次
+func (service *Service) FinalRanking(voteId string) (*model.VoteJuryRanking, *model.VoteJuryResult, error) {
+	result := new(model.VoteJuryResult)
+	if err := service.app.RecordQuery(model.DbNameVoteJuryResults).
+		Where(dbx.HashExp{model.VoteJuryResultFieldVoteId: voteId}).
+		OrderBy(model.VoteJuryResultFieldRound + " DESC").
+		Limit(1).
+		One(result); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, ErrJuryNoResult
+		}
+		return nil, nil, err
+	}
+
+	if result.Continue() {
+		return nil, result, ErrJuryNotFinished
+	}
+
+	ranking, err := ParseRanking(result)
+	if err != nil {
+		return nil, result, err
+	}
+	return ranking, result, nil
+}
@@ -0,0 +1,129 @@
+package vote_jury
+
+import (
+	"bless-activity/model"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+var (
+	ErrRollbackStatus   = errors.New("只能在评审中或计票完成时回滚算票")
+	ErrNoResult         = errors.New("没有可回滚的算票结果")
+	ErrNextRoundStarted = errors.New("加赛轮次已有投票记录，无法回滚")
+	ErrRewardExecuted   = errors.New("该投票的奖励已按结果发放，确认需要回滚请强制执行")
+)
+
+// RollbackRound 回滚最近一轮算票：删除该轮结果，恢复轮次与评审中状态，并记录操作人、原因与被删除的结果
+// 回滚后关闭自动算票，避免调度任务立即重新算票，修正后由管理员手动算票
+// 已按结果执行过奖励发放计划时拒绝回滚，除非 force 为 true，强制回滚会记录在变更日志中，已发放的奖励需另行处理
+func (service *Service) RollbackRound(rule *model.VoteJuryRule, actorId string, reason string, force bool) (*model.VoteJuryResult, error) {
+	if rule.Status() != model.VoteJuryRuleStatusVoting && rule.Status() != model.VoteJuryRuleStatusCompleted {
+		return nil, ErrRollbackStatus
+	}
+
+	var rolledBack *model.VoteJuryResult
+	err := service.app.RunInTransaction(func(txApp core.App) error {
+		result := new(model.VoteJuryResult)
+		if err := txApp.RecordQuery(model.DbNameVoteJuryResults).
+			Where(dbx.HashExp{model.VoteJuryResultFieldVoteId: rule.VoteId()}).
+			OrderBy(model.VoteJuryResultFieldRound + " DESC").
+			Limit(1).
+			One(result); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrNoResult
+			}
+			return err
+		}
+
+		// 加赛轮次已经开始投票时，回滚会让这些投票失去对应的轮次
+		if result.Continue() {
+			started, err := service.roundStarted(txApp, rule.VoteId(), result.Round())
+			if err != nil {
+				return err
+			}
+			if started {
+				return ErrNextRoundStarted
+			}
+		}
+
+		executed, err := txApp.CountRecords(model.DbNameRewardPlans, dbx.HashExp{
+			model.RewardPlansFieldVoteId: rule.VoteId(),
+			model.RewardPlansFieldStatus: model.RewardPlanStatusExecuted,
+		})
+		if err != nil {
+			return err
+		}
+		if executed > 0 {
+			if !force {
+				return ErrRewardExecuted
+			}
+			reason = fmt.Sprintf("%s（已执行%d个奖励发放计划，强制回滚）", reason, executed)
+		}
+
+		snapshot, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+
+		if err = txApp.Delete(result); err != nil {
+			return err
+		}
+
+		fromStatus := rule.Status()
+		rule.SetStatus(model.VoteJuryRuleStatusVoting)
+		rule.SetCurrentRound(result.Round())
+		rule.SetAutoCalculate(false)
+		// 秘密投票的选票已在揭示阶段揭示，回到揭示阶段以便重新算票
+		if rule.SecretBallot() {
+			rule.SetRevealing(true)
+		}
+		if err = txApp.Save(rule); err != nil {
+			return err
+		}
+
+		rolledBack = result
+		return service.saveStatusLogWithSnapshot(txApp, rule, fromStatus, model.VoteJuryStatusTriggerManual, actorId,
+			fmt.Sprintf("回滚第%d轮算票：%s", result.Round(), reason), string(snapshot))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	service.logger.Info("回滚评审团算票",
+		slog.String("voteId", rule.VoteId()),
+		slog.Int("round", rolledBack.Round()),
+		slog.String("actorId", actorId),
+		slog.String("reason", reason),
+		slog.Bool("force", force),
+	)
+
+	return rolledBack, nil
+}
+
+// roundStarted 判断指定轮次之后是否已有投票、评分或投票承诺
+func (service *Service) roundStarted(txApp core.App, voteId string, round int) (bool, error) {
+	tables := map[string][2]string{
+		model.DbNameVoteJuryLogs:    {model.VoteJuryLogFieldVoteId, model.VoteJuryLogFieldRound},
+		model.DbNameVoteJuryScores:  {model.VoteJuryScoreFieldVoteId, model.VoteJuryScoreFieldRound},
+		model.DbNameVoteJuryCommits: {model.VoteJuryCommitFieldVoteId, model.VoteJuryCommitFieldRound},
+	}
+	for table, fields := range tables {
+		total, err := txApp.CountRecords(table,
+			dbx.HashExp{fields[0]: voteId},
+			dbx.NewExp(fields[1]+" > {:round}", dbx.Params{"round": round}),
+		)
+		if err != nil {
+			return false, err
+		}
+		if total > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,181 @@
+package vote_jury
+
+import (
+	"bless-activity/model"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+var (
+	ErrNotRubric       = errors.New("当前评审团不是评分模式")
+	ErrNoCriteria      = errors.New("评审团未配置评分项")
+	ErrInvalidCriteria = errors.New("评分项配置无效")
+)
+
+// Criteria 解析评审团评分项配置
+func Criteria(rule *model.VoteJuryRule) ([]model.VoteJuryCriterion, error) {
+	if rule.Criteria() == "" {
+		return nil, ErrNoCriteria
+	}
+
+	var criteria []model.VoteJuryCriterion
+	if err := json.Unmarshal([]byte(rule.Criteria()), &criteria); err != nil {
+		return nil, ErrInvalidCriteria
+	}
+	if len(criteria) == 0 {
+		return nil, ErrNoCriteria
+	}
+
+	keys := make(map[string]bool, len(criteria))
+	for _, criterion := range criteria {
+		if criterion.Key == "" || keys[criterion.Key] || criterion.Max <= criterion.Min || criterion.Weight < 0 {
+			return nil, ErrInvalidCriteria
+		}
+		keys[criterion.Key] = true
+	}
+
+	return criteria, nil
+}
+
+// ValidateScores 校验评审提交的评分，每个评分项都必须在分值范围内
+func ValidateScores(criteria []model.VoteJuryCriterion, scores map[string]float64) error {
+	for _, criterion := range criteria {
+		score, ok := scores[criterion.Key]
+		if !ok {
+			return fmt.Errorf("缺少评分项：%s", criterion.Name)
+		}
+		if math.IsNaN(score) || score < criterion.Min || score > criterion.Max {
+			return fmt.Errorf("评分项 %s 的分数需在 %g 到 %g 之间", criterion.Name, criterion.Min, criterion.Max)
+		}
+	}
+	if len(scores) != len(criteria) {
+		return errors.New("包含未配置的评分项")
+	}
+	return nil
+}
+
+// tallyRubric 汇总当前轮次的评分，按评分项聚合后加权得到总分
+func (service *Service) tallyRubric(app core.App, rule *model.VoteJuryRule, round int) (map[string]*model.VoteJuryScoreBreakdown, error) {
+	criteria, err := Criteria(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []*model.VoteJuryScore
+	if err := app.RecordQuery(model.DbNameVoteJuryScores).
+		Where(dbx.HashExp{
+			model.VoteJuryScoreFieldVoteId: rule.VoteId(),
+			model.VoteJuryScoreFieldRound:  round,
+		}).
+		All(&records); err != nil {
+		return nil, err
+	}
+
+	// 候选人 -> 评分项 -> 评审 -> 分数
+	type jurorScore struct {
+		jurorId string
+		score   float64
+	}
+	collected := make(map[string]map[string][]jurorScore)
+	for _, record := range records {
+		var scores map[string]float64
+		if err := json.Unmarshal([]byte(record.Scores()), &scores); err != nil {
+			service.logger.Warn("解析评分失败", slog.String("scoreId", record.Id), slog.Any("err", err))
+			continue
+		}
+		if collected[record.ToUserId()] == nil {
+			collected[record.ToUserId()] = make(map[string][]jurorScore)
+		}
+		for _, criterion := range criteria {
+			if score, ok := scores[criterion.Key]; ok {
+				collected[record.ToUserId()][criterion.Key] = append(collected[record.ToUserId()][criterion.Key], jurorScore{record.FromUserId(), score})
+			}
+		}
+	}
+
+	breakdown := make(map[string]*model.VoteJuryScoreBreakdown, len(collected))
+	for candidateId, byCriterion := range collected {
+		item := &model.VoteJuryScoreBreakdown{
+			Criteria: make(map[string]model.VoteJuryCriterionStat, len(criteria)),
+			Outliers: make([]model.VoteJuryScoreOutlier, 0),
+		}
+
+		for _, criterion := range criteria {
+			entries := byCriterion[criterion.Key]
+			if len(entries) == 0 {
+				continue
+			}
+
+			values := make([]float64, len(entries))
+			for i, entry := range entries {
+				values[i] = entry.score
+			}
+			slices.Sort(values)
+
+			median := medianOf(values)
+			aggregated := median
+			if rule.Aggregation() == model.VoteJuryAggregationTrimmedMean {
+				aggregated = trimmedMean(values, rule.TrimRatio())
+			}
+
+			item.Criteria[criterion.Key] = model.VoteJuryCriterionStat{
+				Score:  round2(aggregated),
+				Median: round2(median),
+				Count:  len(values),
+				Min:    values[0],
+				Max:    values[len(values)-1],
+			}
+			item.Total += aggregated * criterion.Weight
+
+			// 偏离中位数超过分值范围一定比例的评分视为异常
+			limit := (criterion.Max - criterion.Min) * rule.OutlierThreshold()
+			for _, entry := range entries {
+				if math.Abs(entry.score-median) > limit {
+					item.Outliers = append(item.Outliers, model.VoteJuryScoreOutlier{
+						JurorId:   entry.jurorId,
+						Criterion: criterion.Key,
+						Score:     entry.score,
+						Median:    round2(median),
+					})
+				}
+			}
+		}
+
+		item.Total = round2(item.Total)
+		breakdown[candidateId] = item
+	}
+
+	return breakdown, nil
+}
+
+// medianOf 计算有序数组的中位数
+func medianOf(sorted []float64) float64 {
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// trimmedMean 去掉两端各 ratio 比例的评分后求均值，评分过少时不截尾
+func trimmedMean(sorted []float64, ratio float64) float64 {
+	trim := int(float64(len(sorted)) * ratio)
+	kept := sorted[trim : len(sorted)-trim]
+
+	total := 0.0
+	for _, value := range kept {
+		total += value
+	}
+	return total / float64(len(kept))
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,120 @@
+package vote_jury
+
+import (
+	"bless-activity/model"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/pocketbase/dbx"
+)
+
+// Run 注册评审团状态调度任务，每分钟检查一次
+func (service *Service) Run() error {
+	return service.app.Cron().Add(model.CronKeyJurySchedule.String(), "* * * * *", service.schedule)
+}
+
+func (service *Service) schedule() {
+	var rules []*model.VoteJuryRule
+	if err := service.app.RecordQuery(model.DbNameVoteJuryRules).Where(dbx.HashExp{
+		model.VoteJuryRuleFieldAutoSchedule: true,
+	}).AndWhere(dbx.Not(dbx.HashExp{
+		model.VoteJuryRuleFieldStatus: model.VoteJuryRuleStatusCompleted,
+	})).All(&rules); err != nil {
+		service.logger.Error("查询评审团规则失败", slog.Any("err", err))
+		return
+	}
+
+	now := time.Now()
+	for _, rule := range rules {
+		service.scheduleRule(rule, now)
+	}
+}
+
+// scheduleRule 按时间推进单个评审团的状态
+// 管理员手动回退状态后如不希望被再次推进，需要关闭 autoSchedule
+func (service *Service) scheduleRule(rule *model.VoteJuryRule, now time.Time) {
+	logger := service.logger.With(slog.String("voteId", rule.VoteId()))
+
+	vote := new(model.Vote)
+	if err := service.app.RecordQuery(model.DbNameVotes).Where(dbx.HashExp{model.CommonFieldId: rule.VoteId()}).One(vote); err != nil {
+		logger.Error("获取投票失败", slog.Any("err", err))
+		return
+	}
+
+	// 错过多个时间点时在同一次调度中依次推进
+	for range len(transitions) {
+		next, reason := nextStatus(rule, vote, now)
+		if next == "" {
+			break
+		}
+		if err := service.SwitchStatus(rule, next, model.VoteJuryStatusTriggerSchedule, "", reason); err != nil {
+			if errors.Is(err, ErrOpenObjections) {
+				logger.Debug("存在未处理的异议，暂不进入评审")
+				return
+			}
+			logger.Error("自动切换状态失败", slog.String("to", next.String()), slog.Any("err", err))
+			return
+		}
+	}
+
+	// 以下只处理第一轮，平票后的加赛轮次由管理员手动开启揭示和算票
+	if rule.Status() != model.VoteJuryRuleStatusVoting || rule.CurrentRound() > 1 {
+		return
+	}
+	end := vote.End()
+	if end.IsZero() || now.Before(end.Time()) {
+		return
+	}
+
+	// 秘密投票在投票结束后开启揭示阶段，揭示阶段结束后才能算票
+	if rule.SecretBallot() {
+		if !rule.Revealing() {
+			if err := service.OpenReveal(rule, model.VoteJuryStatusTriggerSchedule, ""); err != nil {
+				logger.Error("自动开启揭示阶段失败", slog.Any("err", err))
+			}
+			return
+		}
+		revealEnd := rule.RevealStart().Time().Add(time.Duration(rule.RevealMinutes()) * time.Minute)
+		if now.Before(revealEnd) {
+			return
+		}
+	}
+
+	// 投票结束后自动算票
+	if !rule.AutoCalculate() {
+		return
+	}
+
+	if _, err := service.Calculate(rule, model.VoteJuryStatusTriggerSchedule, ""); err != nil {
+		if errors.Is(err, ErrNoVotes) || errors.Is(err, ErrRoundCalculated) || errors.Is(err, ErrVoteFinished) {
+			logger.Debug("跳过自动算票", slog.Any("reason", err))
+			return
+		}
+		logger.Error("自动算票失败", slog.Any("err", err))
+	}
+}
+
+// nextStatus 根据配置的时间判断下一个状态，无需切换时返回空
+func nextStatus(rule *model.VoteJuryRule, vote *model.Vote, now time.Time) (model.VoteJuryRuleStatus, string) {
+	reached := func(t time.Time) bool {
+		return !t.IsZero() && !now.Before(t)
+	}
+
+	switch rule.Status() {
+	case model.VoteJuryRuleStatusPending:
+		if reached(rule.ApplyTime().Time()) {
+			return model.VoteJuryRuleStatusApplying, "到达开放申请时间"
+		}
+	case model.VoteJuryRuleStatusApplying:
+		if reached(rule.PublicityTime().Time()) {
+			return model.VoteJuryRuleStatusPublicity, "到达公示时间"
+		}
+	case model.VoteJuryRuleStatusPublicity:
+		if reached(vote.Start().Time()) {
+			return model.VoteJuryRuleStatusVoting, "到达投票开始时间"
+		}
+	}
+
+	return "", ""
+}
@@ -0,0 +1,328 @@
+package vote_jury
+
+import (
+	"bless-activity/model"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var (
+	ErrSelectionManual = errors.New("当前评审团为手动审核模式")
+	ErrSelectionDone   = errors.New("评审团已完成遴选")
+	ErrSelectionStatus = errors.New("只能在申请阶段遴选评审团成员")
+	ErrSeedMismatch    = errors.New("遴选种子与申请阶段公示的承诺不一致")
+)
+
+// SelectionHash 计算随机遴选顺序使用的哈希，任何人都可以用公示的种子复核
+func SelectionHash(seed string, userId string) string {
+	sum := sha256.Sum256([]byte(seed + ":" + userId))
+	return hex.EncodeToString(sum[:])
+}
+
+// SeedHash 计算遴选种子的承诺，申请阶段公示，遴选后任何人都可以用公开的种子核对
+func SeedHash(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:])
+}
+
+// commitSeed 自动遴选模式下生成种子（已指定时沿用）并记录承诺，已完成遴选时不再变更
+func commitSeed(rule *model.VoteJuryRule) {
+	if rule.SelectMode() == model.VoteJurySelectModeManual || rule.Selection() != "" {
+		return
+	}
+	if rule.SelectSeed() == "" {
+		rule.SetSelectSeed(security.RandomString(32))
+	}
+	rule.SetSelectSeedHash(SeedHash(rule.SelectSeed()))
+}
+
+// Select 从申请人中遴选评审团成员，补足席位后其余申请人进入候补
+func (service *Service) Select(rule *model.VoteJuryRule, actorId string) (*model.VoteJurySelection, error) {
+	var selection *model.VoteJurySelection
+	err := service.app.RunInTransaction(func(txApp core.App) error {
+		var err error
+		selection, err = service.selectMembers(txApp, rule, actorId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return selection, nil
+}
+
+func (service *Service) selectMembers(txApp core.App, rule *model.VoteJuryRule, actorId string) (*model.VoteJurySelection, error) {
+	if rule.SelectMode() == model.VoteJurySelectModeManual {
+		return nil, ErrSelectionManual
+	}
+	if rule.Selection() != "" {
+		return nil, ErrSelectionDone
+	}
+	if rule.Status() != model.VoteJuryRuleStatusApplying {
+		return nil, ErrSelectionStatus
+	}
+
+	voteId := rule.VoteId()
+	seed := rule.SelectSeed()
+	seedHash := rule.SelectSeedHash()
+	if seed == "" {
+		// 申请阶段未生成种子（如早于承诺机制创建的规则），遴选记录中 seedHash 为空表示未提前承诺
+		seed = security.RandomString(32)
+		seedHash = ""
+	} else if seedHash != "" && SeedHash(seed) != seedHash {
+		return nil, ErrSeedMismatch
+	}
+	executedAt := types.NowDateTime()
+
+	// 已通过的成员占用席位
+	var members []*model.VoteJuryUser
+	if err := txApp.RecordQuery(model.DbNameVoteJuryUsers).Where(dbx.HashExp{
+		model.VoteJuryUserFieldVoteId: voteId,
+	}).All(&members); err != nil {
+		return nil, err
+	}
+	memberMap := make(map[string]*model.VoteJuryUser, len(members))
+	approvedCount := 0
+	for _, member := range members {
+		memberMap[member.UserId()] = member
+		if member.Status() == model.VoteJuryUserStatusApproved {
+			approvedCount++
+		}
+	}
+	seats := max(rule.Count()-approvedCount, 0)
+
+	// 待审核与候补的申请，每个用户只取最早的一条
+	var applyLogs []*model.VoteJuryApplyLog
+	if err := txApp.RecordQuery(model.DbNameVoteJuryApplyLogs).Where(dbx.HashExp{
+		model.VoteJuryApplyLogFieldVoteId: voteId,
+		model.VoteJuryApplyLogFieldStatus: []any{model.VoteJuryApplyLogStatusPending, model.VoteJuryApplyLogStatusWaitlisted},
+	}).OrderBy(model.VoteJuryApplyLogFieldCreated + " ASC").All(&applyLogs); err != nil {
+		return nil, err
+	}
+
+	// 本活动的参赛者不能担任评审
+	candidateIds, err := service.CandidateIds(txApp, voteId)
+	if err != nil {
+		return nil, err
+	}
+
+	applyMap := make(map[string]*model.VoteJuryApplyLog)
+	excluded := make([]string, 0)
+	candidates := make([]model.VoteJurySelectionCandidate, 0, len(applyLogs))
+	userIds := make([]any, 0, len(applyLogs))
+	for _, applyLog := range applyLogs {
+		userId := applyLog.UserId()
+		if _, exists := applyMap[userId]; exists {
+			continue
+		}
+		if member, ok := memberMap[userId]; ok && member.Status() == model.VoteJuryUserStatusApproved {
+			continue
+		}
+		if candidateIds[userId] {
+			applyLog.SetStatus(model.VoteJuryApplyLogStatusRejected)
+			applyLog.SetAdminId(actorId)
+			if err := txApp.Save(applyLog); err != nil {
+				return nil, err
+			}
+			applyMap[userId] = applyLog
+			excluded = append(excluded, userId)
+			continue
+		}
+		applyMap[userId] = applyLog
+		userIds = append(userIds, userId)
+		candidates = append(candidates, model.VoteJurySelectionCandidate{
+			UserId:  userId,
+			ApplyId: applyLog.Id,
+			Hash:    SelectionHash(seed, userId),
+		})
+	}
+
+	if err := service.fillEligibility(txApp, voteId, userIds, candidates, executedAt.Time()); err != nil {
+		return nil, err
+	}
+
+	selected, waitlist := orderCandidates(rule.SelectMode(), candidates, seats, rule.ReturningQuota())
+
+	// 写入遴选结果
+	for _, userId := range selected {
+		applyLog := applyMap[userId]
+		applyLog.SetStatus(model.VoteJuryApplyLogStatusApproved)
+		applyLog.SetAdminId(actorId)
+		if err := txApp.Save(applyLog); err != nil {
+			return nil, err
+		}
+
+		juryUser, ok := memberMap[userId]
+		if !ok {
+			collection, err := txApp.FindCollectionByNameOrId(model.DbNameVoteJuryUsers)
+			if err != nil {
+				return nil, err
+			}
+			juryUser = model.NewVoteJuryUserFromCollection(collection)
+			juryUser.SetVoteId(voteId)
+			juryUser.SetUserId(userId)
+		}
+		juryUser.SetStatus(model.VoteJuryUserStatusApproved)
+		if err := txApp.Save(juryUser); err != nil {
+			return nil, err
+		}
+	}
+	for _, userId := range waitlist {
+		applyLog := applyMap[userId]
+		applyLog.SetStatus(model.VoteJuryApplyLogStatusWaitlisted)
+		if err := txApp.Save(applyLog); err != nil {
+			return nil, err
+		}
+	}
+
+	selection := &model.VoteJurySelection{
+		Mode:       rule.SelectMode(),
+		Seed:       seed,
+		SeedHash:   seedHash,
+		Seats:      seats,
+		Quota:      rule.ReturningQuota(),
+		Candidates: candidates,
+		Selected:   selected,
+		Waitlist:   waitlist,
+		Excluded:   excluded,
+		ExecutedAt: executedAt.String(),
+	}
+	selectionBytes, err := json.Marshal(selection)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.SetSelectSeed(seed)
+	rule.SetSelection(string(selectionBytes))
+	if err = txApp.Save(rule); err != nil {
+		return nil, err
+	}
+
+	service.logger.Info("评审团遴选完成",
+		slog.String("voteId", voteId),
+		slog.String("mode", rule.SelectMode().String()),
+		slog.Int("seats", seats),
+		slog.Int("selected", len(selected)),
+		slog.Int("waitlist", len(waitlist)),
+	)
+
+	return selection, nil
+}
+
+// fillEligibility 标记往届评审并计算资格分，计算依据写入候选人记录
+// 资格分 = 往届担任评审次数 × 2 + 截至遴选时间的注册年数
+func (service *Service) fillEligibility(txApp core.App, voteId string, userIds []any, candidates []model.VoteJurySelectionCandidate, executedAt time.Time) error {
+	if len(userIds) == 0 {
+		return nil
+	}
+
+	var pastMembers []*model.VoteJuryUser
+	if err := txApp.RecordQuery(model.DbNameVoteJuryUsers).Where(dbx.HashExp{
+		model.VoteJuryUserFieldStatus: model.VoteJuryUserStatusApproved,
+	}).AndWhere(dbx.In(model.VoteJuryUserFieldUserId, userIds...)).
+		AndWhere(dbx.Not(dbx.HashExp{model.VoteJuryUserFieldVoteId: voteId})).
+		All(&pastMembers); err != nil {
+		return err
+	}
+	pastCount := make(map[string]int)
+	for _, member := range pastMembers {
+		pastCount[member.UserId()]++
+	}
+
+	var users []*model.User
+	if err := txApp.RecordQuery(model.DbNameUsers).Where(dbx.In(model.CommonFieldId, userIds...)).All(&users); err != nil {
+		return err
+	}
+	registered := make(map[string]types.DateTime, len(users))
+	for _, user := range users {
+		registered[user.Id] = user.RegisteredAt()
+	}
+
+	for i := range candidates {
+		userId := candidates[i].UserId
+		candidates[i].PastCount = pastCount[userId]
+		candidates[i].Returning = pastCount[userId] > 0
+		candidates[i].Score = EligibilityScore(pastCount[userId], registered[userId], executedAt)
+		if registeredAt := registered[userId]; !registeredAt.IsZero() {
+			candidates[i].RegisteredAt = registeredAt.String()
+		}
+	}
+
+	return nil
+}
+
+// EligibilityScore 资格分，注册年数按遴选时间计算并保留两位小数，相同输入总是得到相同结果
+func EligibilityScore(pastCount int, registeredAt types.DateTime, executedAt time.Time) float64 {
+	years := 0.0
+	if !registeredAt.IsZero() {
+		years = executedAt.Sub(registeredAt.Time()).Hours() / 24 / 365
+	}
+	return float64(pastCount*2) + float64(int(years*100))/100
+}
+
+// orderCandidates 按遴选方式排序候选人，返回入选与候补的用户ID，candidates 会被排序为最终顺序
+func orderCandidates(mode model.VoteJurySelectMode, candidates []model.VoteJurySelectionCandidate, seats int, quota int) ([]string, []string) {
+	byHash := func(i, j int) bool {
+		return candidates[i].Hash < candidates[j].Hash
+	}
+
+	switch mode {
+	case model.VoteJurySelectModeScore:
+		sort.SliceStable(candidates, func(i, j int) bool {
+			if candidates[i].Score != candidates[j].Score {
+				return candidates[i].Score > candidates[j].Score
+			}
+			return byHash(i, j)
+		})
+	case model.VoteJurySelectModeQuota:
+		sort.SliceStable(candidates, byHash)
+
+		// 先按配额抽取往届评审，再由新评审补足，仍有空位时由剩余往届评审补足
+		picked := make(map[string]bool)
+		selected := make([]string, 0, seats)
+		pick := func(returning bool, limit int) {
+			for _, candidate := range candidates {
+				if len(selected) >= limit {
+					return
+				}
+				if candidate.Returning == returning && !picked[candidate.UserId] {
+					picked[candidate.UserId] = true
+					selected = append(selected, candidate.UserId)
+				}
+			}
+		}
+		pick(true, min(quota, seats))
+		pick(false, seats)
+		pick(true, seats)
+
+		waitlist := make([]string, 0, len(candidates))
+		for _, candidate := range candidates {
+			if !picked[candidate.UserId] {
+				waitlist = append(waitlist, candidate.UserId)
+			}
+		}
+		return selected, waitlist
+	default:
+		sort.SliceStable(candidates, byHash)
+	}
+
+	selected := make([]string, 0, seats)
+	waitlist := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if len(selected) < seats {
+			selected = append(selected, candidate.UserId)
+		} else {
+			waitlist = append(waitlist, candidate.UserId)
+		}
+	}
+	return selected, waitlist
+}
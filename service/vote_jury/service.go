@@ -0,0 +1,397 @@
+package vote_jury
+
+import (
+	"bless-activity/model"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+var (
+	ErrInvalidTransition = errors.New("状态流转无效")
+	ErrNotVoting         = errors.New("当前状态不是评审中")
+	ErrRoundCalculated   = errors.New("当前轮次已算票，请等待下一轮投票完成后再算票")
+	ErrVoteFinished      = errors.New("投票已结束，无需再次算票")
+	ErrNoVotes           = errors.New("当前轮次没有投票记录")
+)
+
+// transitions 状态流转规则（支持前进和回退）
+var transitions = map[model.VoteJuryRuleStatus][]model.VoteJuryRuleStatus{
+	model.VoteJuryRuleStatusPending:   {model.VoteJuryRuleStatusApplying},
+	model.VoteJuryRuleStatusApplying:  {model.VoteJuryRuleStatusPublicity, model.VoteJuryRuleStatusPending},   // 可回退到未开启
+	model.VoteJuryRuleStatusPublicity: {model.VoteJuryRuleStatusVoting, model.VoteJuryRuleStatusApplying},     // 可回退到申请中
+	model.VoteJuryRuleStatusVoting:    {model.VoteJuryRuleStatusCompleted, model.VoteJuryRuleStatusPublicity}, // 可回退到公示中
+	model.VoteJuryRuleStatusCompleted: {model.VoteJuryRuleStatusVoting},                                       // 可回退到评审中
+}
+
+// CanTransition 判断状态能否从 from 切换到 to
+func CanTransition(from model.VoteJuryRuleStatus, to model.VoteJuryRuleStatus) bool {
+	return slices.Contains(transitions[from], to)
+}
+
+// CalculateResult 单轮算票结果
+type CalculateResult struct {
+	Round         int
+	NeedNextRound bool
+	TopUsers      []string               // 需要加赛时为进入下一轮的用户，否则为按名次排列的获奖用户
+	MaxVotes      int                    // 最高票数
+	Winner        string                 // 第一名，尚未决出时为空
+	VoteCount     map[string]int         // 用户ID与得票数映射
+	Ranking       *model.VoteJuryRanking // 名次结算状态
+}
+
+type Service struct {
+	app core.App
+
+	logger *slog.Logger
+}
+
+func NewService(app core.App) *Service {
+	service := &Service{
+		app:    app,
+		logger: app.Logger().WithGroup("service.vote_jury"),
+	}
+	return service
+}
+
+// SwitchStatus 切换评审团状态并记录变更日志
+func (service *Service) SwitchStatus(rule *model.VoteJuryRule, newStatus model.VoteJuryRuleStatus, trigger model.VoteJuryStatusTrigger, actorId string, reason string) error {
+	return service.app.RunInTransaction(func(txApp core.App) error {
+		return service.switchStatus(txApp, rule, newStatus, trigger, actorId, reason)
+	})
+}
+
+// switchStatus 在事务中切换评审团状态
+func (service *Service) switchStatus(txApp core.App, rule *model.VoteJuryRule, newStatus model.VoteJuryRuleStatus, trigger model.VoteJuryStatusTrigger, actorId string, reason string) error {
+	fromStatus := rule.Status()
+	if !CanTransition(fromStatus, newStatus) {
+		return ErrInvalidTransition
+	}
+
+	// 进入申请阶段时生成遴选种子并公示其承诺，申请结束后才公开种子
+	if newStatus == model.VoteJuryRuleStatusApplying {
+		commitSeed(rule)
+	}
+
+	// 申请结束进入公示时，自动遴选模式下先完成遴选
+	if fromStatus == model.VoteJuryRuleStatusApplying && newStatus == model.VoteJuryRuleStatusPublicity &&
+		rule.SelectMode() != model.VoteJurySelectModeManual && rule.Selection() == "" {
+		if _, err := service.selectMembers(txApp, rule, actorId); err != nil {
+			return err
+		}
+	}
+
+	// 公示期间的异议处理完成后才能进入评审
+	if fromStatus == model.VoteJuryRuleStatusPublicity && newStatus == model.VoteJuryRuleStatusVoting {
+		if err := service.checkObjections(txApp, rule.VoteId()); err != nil {
+			return err
+		}
+	}
+
+	// 离开评审状态时结束揭示阶段
+	if fromStatus == model.VoteJuryRuleStatusVoting {
+		rule.SetRevealing(false)
+	}
+
+	// 进入评审状态时，初始化轮次
+	if newStatus == model.VoteJuryRuleStatusVoting && rule.CurrentRound() == 0 {
+		rule.SetCurrentRound(1)
+	}
+
+	rule.SetStatus(newStatus)
+	if err := txApp.Save(rule); err != nil {
+		return err
+	}
+
+	return service.saveStatusLog(txApp, rule, fromStatus, trigger, actorId, reason)
+}
+
+func (service *Service) saveStatusLog(txApp core.App, rule *model.VoteJuryRule, fromStatus model.VoteJuryRuleStatus, trigger model.VoteJuryStatusTrigger, actorId string, reason string) error {
+	return service.saveStatusLogWithSnapshot(txApp, rule, fromStatus, trigger, actorId, reason, "")
+}
+
+func (service *Service) saveStatusLogWithSnapshot(txApp core.App, rule *model.VoteJuryRule, fromStatus model.VoteJuryRuleStatus, trigger model.VoteJuryStatusTrigger, actorId string, reason string, snapshot string) error {
+	collection, err := txApp.FindCollectionByNameOrId(model.DbNameVoteJuryStatusLogs)
+	if err != nil {
+		return err
+	}
+
+	statusLog := model.NewVoteJuryStatusLogFromCollection(collection)
+	statusLog.SetVoteId(rule.VoteId())
+	statusLog.SetFromStatus(fromStatus)
+	statusLog.SetToStatus(rule.Status())
+	statusLog.SetRound(rule.CurrentRound())
+	statusLog.SetTrigger(trigger)
+	statusLog.SetActorId(actorId)
+	statusLog.SetReason(reason)
+	statusLog.SetSnapshot(snapshot)
+
+	if err = txApp.Save(statusLog); err != nil {
+		return err
+	}
+
+	service.logger.Info("评审团状态变更",
+		slog.String("voteId", rule.VoteId()),
+		slog.String("from", fromStatus.String()),
+		slog.String("to", rule.Status().String()),
+		slog.String("trigger", trigger.String()),
+	)
+
+	return nil
+}
+
+// Calculate 统计评审团当前轮次的投票并结算名次，前K名中存在平票时进入下一轮加赛，否则结束评审
+func (service *Service) Calculate(rule *model.VoteJuryRule, trigger model.VoteJuryStatusTrigger, actorId string) (*CalculateResult, error) {
+	if rule.Status() != model.VoteJuryRuleStatusVoting {
+		return nil, ErrNotVoting
+	}
+
+	// 秘密投票只统计揭示阶段已揭示的选票
+	if rule.SecretBallot() && !rule.Revealing() {
+		return nil, ErrRevealNotOpen
+	}
+
+	var calculateResult *CalculateResult
+	err := service.app.RunInTransaction(func(txApp core.App) error {
+		var err error
+		calculateResult, err = service.calculate(txApp, rule, trigger, actorId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return calculateResult, nil
+}
+
+// calculate 在事务中完成算票，结果、轮次与状态同时保存，任一步失败都会整体回滚
+func (service *Service) calculate(txApp core.App, rule *model.VoteJuryRule, trigger model.VoteJuryStatusTrigger, actorId string) (*CalculateResult, error) {
+	voteId := rule.VoteId()
+	currentRound := rule.CurrentRound()
+	if currentRound == 0 {
+		currentRound = 1
+	}
+
+	// 检查当前轮次是否已经算过票
+	existingResult := new(model.VoteJuryResult)
+	if err := txApp.RecordQuery(model.DbNameVoteJuryResults).
+		Where(dbx.HashExp{
+			model.VoteJuryResultFieldVoteId: voteId,
+			model.VoteJuryResultFieldRound:  currentRound,
+		}).
+		One(existingResult); err == nil {
+		if !existingResult.Continue() {
+			return nil, ErrVoteFinished
+		}
+		return nil, ErrRoundCalculated
+	}
+
+	// 上一轮的名次结算状态
+	var previous *model.VoteJuryRanking
+	if currentRound > 1 {
+		lastResult := new(model.VoteJuryResult)
+		if err := txApp.RecordQuery(model.DbNameVoteJuryResults).
+			Where(dbx.HashExp{
+				model.VoteJuryResultFieldVoteId: voteId,
+				model.VoteJuryResultFieldRound:  currentRound - 1,
+			}).
+			One(lastResult); err != nil {
+			return nil, err
+		}
+		var err error
+		if previous, err = ParseRanking(lastResult); err != nil {
+			return nil, err
+		}
+	}
+
+	// 统计当前轮次各候选人的得分，投票模式为得票数，评分模式为加权总分
+	voteCount := make(map[string]int)
+	points := make(map[string]float64)
+	decisionCount := make(map[string]float64)
+	var breakdown map[string]*model.VoteJuryScoreBreakdown
+	if rule.Scoring() == model.VoteJuryScoringRubric {
+		var err error
+		if breakdown, err = service.tallyRubric(txApp, rule, currentRound); err != nil {
+			return nil, err
+		}
+		for userId, item := range breakdown {
+			points[userId] = item.Total
+		}
+	} else {
+		var voteLogs []*model.VoteJuryLog
+		if err := txApp.RecordQuery(model.DbNameVoteJuryLogs).
+			Where(dbx.HashExp{
+				model.VoteJuryLogFieldVoteId: voteId,
+				model.VoteJuryLogFieldRound:  currentRound,
+			}).
+			All(&voteLogs); err != nil {
+			return nil, err
+		}
+
+		decisions := rule.Decisions()
+		for _, log := range voteLogs {
+			voteCount[log.ToUserId()] += log.Times()
+			points[log.ToUserId()] += float64(log.Times())
+			if slices.Contains(decisions, log.FromUserId()) {
+				decisionCount[log.ToUserId()] += float64(log.Times())
+			}
+		}
+	}
+
+	if len(points) == 0 {
+		return nil, ErrNoVotes
+	}
+
+	maxVotes := 0
+	for _, count := range voteCount {
+		maxVotes = max(maxVotes, count)
+	}
+
+	ranking := settleRanking(previous, rule.Winners(), currentRound, points, decisionCount)
+	needNextRound := len(ranking.Pending) > 0
+
+	topUsers := make([]string, 0)
+	if needNextRound {
+		for _, group := range ranking.Pending {
+			topUsers = append(topUsers, group.UserIds...)
+		}
+	} else {
+		for _, entry := range ranking.Settled {
+			topUsers = append(topUsers, entry.UserId)
+		}
+	}
+
+	var winner string
+	if len(ranking.Settled) > 0 && ranking.Settled[0].Rank == 1 {
+		winner = ranking.Settled[0].UserId
+	}
+
+	// 保存本轮结果
+	resultsJson, _ := json.Marshal(voteCount)
+	rankingJson, _ := json.Marshal(ranking)
+	if breakdown != nil {
+		resultsJson, _ = json.Marshal(points)
+	}
+
+	resultCollection, err := txApp.FindCollectionByNameOrId(model.DbNameVoteJuryResults)
+	if err != nil {
+		return nil, err
+	}
+
+	result := model.NewVoteJuryResultFromCollection(resultCollection)
+	result.SetVoteId(voteId)
+	result.SetRound(currentRound)
+	result.SetResults(string(resultsJson))
+	result.SetRanking(string(rankingJson))
+	if breakdown != nil {
+		breakdownJson, _ := json.Marshal(breakdown)
+		result.SetBreakdown(string(breakdownJson))
+	}
+	result.SetContinue(needNextRound)
+	result.SetUserIds(topUsers) // 平票的用户进入下一轮，结束时为按名次排列的获奖用户
+
+	if err := txApp.Save(result); err != nil {
+		return nil, err
+	}
+
+	calculateResult := &CalculateResult{
+		Round:         currentRound,
+		NeedNextRound: needNextRound,
+		TopUsers:      topUsers,
+		MaxVotes:      maxVotes,
+		Winner:        winner,
+		VoteCount:     voteCount,
+		Ranking:       ranking,
+	}
+
+	// 如果需要下一轮投票
+	if needNextRound {
+		rule.SetCurrentRound(currentRound + 1)
+		rule.SetRevealing(false) // 加赛轮次重新进入承诺阶段
+		if err := txApp.Save(rule); err != nil {
+			return nil, err
+		}
+		return calculateResult, nil
+	}
+
+	// 投票结束，更新状态为计票完成
+	if err = service.switchStatus(txApp, rule, model.VoteJuryRuleStatusCompleted, trigger, actorId, "算票完成"); err != nil {
+		return nil, err
+	}
+
+	return calculateResult, nil
+}
+
+// settleRanking 根据本轮得票结算名次
+// 每个待定分组按得分、决策票数排序，同票同决策票的用户视为平票；
+// 平票且争夺的名次在前 winners 名以内时进入下一轮加赛，超出的名次不再结算
+func settleRanking(previous *model.VoteJuryRanking, winners int, round int, points map[string]float64, decisionCount map[string]float64) *model.VoteJuryRanking {
+	ranking := &model.VoteJuryRanking{
+		Winners: winners,
+		Settled: make([]model.VoteJuryRankEntry, 0, winners),
+		Pending: make([]model.VoteJuryRankGroup, 0),
+	}
+
+	groups := make([]model.VoteJuryRankGroup, 0)
+	if previous == nil {
+		userIds := make([]string, 0, len(points))
+		for userId := range points {
+			userIds = append(userIds, userId)
+		}
+		groups = append(groups, model.VoteJuryRankGroup{Position: 1, UserIds: userIds})
+	} else {
+		ranking.Settled = append(ranking.Settled, previous.Settled...)
+		groups = append(groups, previous.Pending...)
+	}
+
+	for _, group := range groups {
+		userIds := slices.Clone(group.UserIds)
+		slices.SortFunc(userIds, func(a, b string) int {
+			if c := cmp.Compare(points[b], points[a]); c != 0 {
+				return c
+			}
+			if c := cmp.Compare(decisionCount[b], decisionCount[a]); c != 0 {
+				return c
+			}
+			return strings.Compare(a, b)
+		})
+
+		position := group.Position
+		for start := 0; start < len(userIds) && position <= winners; {
+			end := start + 1
+			for end < len(userIds) &&
+				points[userIds[end]] == points[userIds[start]] &&
+				decisionCount[userIds[end]] == decisionCount[userIds[start]] {
+				end++
+			}
+
+			if end-start == 1 {
+				ranking.Settled = append(ranking.Settled, model.VoteJuryRankEntry{
+					UserId: userIds[start],
+					Rank:   position,
+					Votes:  points[userIds[start]],
+					Round:  round,
+				})
+			} else {
+				ranking.Pending = append(ranking.Pending, model.VoteJuryRankGroup{
+					Position: position,
+					UserIds:  userIds[start:end],
+				})
+			}
+
+			position += end - start
+			start = end
+		}
+	}
+
+	slices.SortFunc(ranking.Settled, func(a, b model.VoteJuryRankEntry) int {
+		return a.Rank - b.Rank
+	})
+
+	return ranking
+}
@@ -0,0 +1,168 @@
+package vote_result
+
+import (
+	"bless-activity/model"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+var (
+	ErrVoteNotEnded     = errors.New("投票尚未结束")
+	ErrAlreadyPublished = errors.New("投票结果已公布")
+	ErrSnapshotNoLogs   = errors.New("结果快照缺少投票记录明细")
+)
+
+// Snapshot 公布时冻结的投票结果
+type Snapshot struct {
+	VoteId      string             `json:"voteId"`
+	Stats       map[string]float64 `json:"stats"`
+	Total       float64            `json:"total"`
+	VoteLogIds  []string           `json:"voteLogIds"` // 计入结果的有效投票记录
+	PublishedAt types.DateTime     `json:"publishedAt"`
+}
+
+// Score 候选人的得票情况
+type Score struct {
+	Votes        float64   // 加权票数
+	Count        int       // 有效票张数
+	LastVoteTime time.Time // 最后一张有效票的时间
+}
+
+type Service struct {
+	app core.App
+
+	logger *slog.Logger
+}
+
+func NewService(app core.App) *Service {
+	service := &Service{
+		app:    app,
+		logger: app.Logger().WithGroup("service.vote_result"),
+	}
+	return service
+}
+
+// Scores 统计每个用户获得的有效票加权票数与最后一张票的时间
+func (service *Service) Scores(voteId string) (map[string]*Score, error) {
+	voteLogs, err := service.validVoteLogs(voteId)
+	if err != nil {
+		return nil, err
+	}
+	return scoreVoteLogs(voteLogs), nil
+}
+
+// validVoteLogs 投票的全部有效票
+func (service *Service) validVoteLogs(voteId string) ([]*model.VoteLog, error) {
+	var voteLogs []*model.VoteLog
+	if err := service.app.RecordQuery(model.DbNameVoteLogs).Where(dbx.HashExp{
+		model.VoteLogsFieldVoteId: voteId,
+		model.VoteLogsFieldValid:  model.VoteLogValidValid,
+	}).All(&voteLogs); err != nil {
+		return nil, err
+	}
+	return voteLogs, nil
+}
+
+func scoreVoteLogs(voteLogs []*model.VoteLog) map[string]*Score {
+	scores := make(map[string]*Score)
+	for _, voteLog := range voteLogs {
+		score, ok := scores[voteLog.ToUserId()]
+		if !ok {
+			score = new(Score)
+			scores[voteLog.ToUserId()] = score
+		}
+		score.Votes += voteLog.Weight()
+		score.Count++
+		if created := voteLog.Created().Time(); created.After(score.LastVoteTime) {
+			score.LastVoteTime = created
+		}
+	}
+	return scores
+}
+
+// Tally 统计每个用户获得的加权票数及总票数
+func (service *Service) Tally(voteId string) (map[string]float64, float64, error) {
+	scores, err := service.Scores(voteId)
+	if err != nil {
+		return nil, 0, err
+	}
+	stats, total := tallyScores(scores)
+	return stats, total, nil
+}
+
+func tallyScores(scores map[string]*Score) (map[string]float64, float64) {
+	stats := make(map[string]float64, len(scores))
+	total := 0.0
+	for userId, score := range scores {
+		stats[userId] = score.Votes
+		total += score.Votes
+	}
+	return stats, total
+}
+
+// Publish 公布投票结果，并将当前统计冻结为快照
+func (service *Service) Publish(vote *model.Vote) (*Snapshot, error) {
+	if vote.Published() {
+		return nil, ErrAlreadyPublished
+	}
+
+	end := vote.End()
+	if !end.IsZero() && time.Now().Before(end.Time()) {
+		return nil, ErrVoteNotEnded
+	}
+
+	// 统计与记录ID取自同一次查询，保证快照中的明细与票数一致
+	voteLogs, err := service.validVoteLogs(vote.Id)
+	if err != nil {
+		return nil, err
+	}
+	stats, total := tallyScores(scoreVoteLogs(voteLogs))
+
+	voteLogIds := make([]string, 0, len(voteLogs))
+	for _, voteLog := range voteLogs {
+		voteLogIds = append(voteLogIds, voteLog.Id)
+	}
+
+	snapshot := &Snapshot{
+		VoteId:      vote.Id,
+		Stats:       stats,
+		Total:       total,
+		VoteLogIds:  voteLogIds,
+		PublishedAt: types.NowDateTime(),
+	}
+
+	snapshotBytes, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	vote.SetPublished(true)
+	vote.SetPublishedAt(snapshot.PublishedAt)
+	vote.SetResultSnapshot(string(snapshotBytes))
+	if err = service.app.Save(vote); err != nil {
+		return nil, err
+	}
+
+	service.logger.Info("投票结果已公布", slog.String("voteId", vote.Id), slog.Float64("total", total))
+
+	return snapshot, nil
+}
+
+// LoadSnapshot 读取已冻结的结果快照，未公布时返回 nil
+func LoadSnapshot(vote *model.Vote) (*Snapshot, error) {
+	if !vote.Published() || vote.ResultSnapshot() == "" {
+		return nil, nil
+	}
+
+	snapshot := new(Snapshot)
+	if err := json.Unmarshal([]byte(vote.ResultSnapshot()), snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
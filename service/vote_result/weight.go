@@ -0,0 +1,123 @@
+package vote_result
+
+import (
+	"bless-activity/model"
+	"encoding/json"
+	"time"
+
+	"github.com/pocketbase/dbx"
+)
+
+// Weight 按投票的权重策略计算用户本次投票的权重，结果在投票时写入投票日志
+func (service *Service) Weight(vote *model.Vote, user *model.User) (float64, error) {
+	policy := vote.WeightPolicy()
+	if policy == model.VoteWeightPolicyNone {
+		return 1, nil
+	}
+
+	config := new(model.VoteWeightConfig)
+	if vote.WeightConfig() != "" {
+		if err := json.Unmarshal([]byte(vote.WeightConfig()), config); err != nil {
+			return 0, err
+		}
+	}
+	if config.Default <= 0 {
+		config.Default = 1
+	}
+
+	switch policy {
+	case model.VoteWeightPolicyAccountAge:
+		registeredAt := user.RegisteredAt()
+		if registeredAt.IsZero() {
+			return config.Default, nil
+		}
+		days := int(time.Since(registeredAt.Time()).Hours() / 24)
+		return tierWeight(config, days), nil
+	case model.VoteWeightPolicyMedal:
+		return service.medalWeight(config, user.Id)
+	case model.VoteWeightPolicyParticipation:
+		count, err := service.participationCount(vote.Id, user.Id)
+		if err != nil {
+			return 0, err
+		}
+		return tierWeight(config, count), nil
+	}
+
+	return config.Default, nil
+}
+
+// tierWeight 取达到门槛的最高档位权重
+func tierWeight(config *model.VoteWeightConfig, value int) float64 {
+	weight := config.Default
+	best := -1
+	for _, tier := range config.Tiers {
+		if value >= tier.Min && tier.Min > best && tier.Weight > 0 {
+			best = tier.Min
+			weight = tier.Weight
+		}
+	}
+	return weight
+}
+
+// medalWeight 取用户持有的未过期勋章中权重最高的一个
+func (service *Service) medalWeight(config *model.VoteWeightConfig, userId string) (float64, error) {
+	if len(config.Medals) == 0 {
+		return config.Default, nil
+	}
+
+	medalIds := make([]any, 0, len(config.Medals))
+	for medalId := range config.Medals {
+		medalIds = append(medalIds, medalId)
+	}
+
+	var owners []*model.MedalOwner
+	if err := service.app.RecordQuery(model.DbNameMedalOwners).Where(dbx.HashExp{
+		model.MedalOwnersFieldUserId: userId,
+	}).AndWhere(dbx.In(model.MedalOwnersFieldMedalId, medalIds...)).All(&owners); err != nil {
+		return 0, err
+	}
+
+	weight := 0.0
+	now := time.Now()
+	for _, owner := range owners {
+		if expired := owner.Expired(); !expired.IsZero() && expired.Time().Before(now) {
+			continue
+		}
+		if medalWeight := config.Medals[owner.MedalId()]; medalWeight > weight {
+			weight = medalWeight
+		}
+	}
+	if weight <= 0 {
+		return config.Default, nil
+	}
+	return weight, nil
+}
+
+// participationCount 统计用户参与过的其他投票次数（有效投票或担任评审团成员）
+func (service *Service) participationCount(voteId string, userId string) (int, error) {
+	voteIds := make(map[string]bool)
+
+	var voteLogs []*model.VoteLog
+	if err := service.app.RecordQuery(model.DbNameVoteLogs).Where(dbx.HashExp{
+		model.VoteLogsFieldFromUserId: userId,
+		model.VoteLogsFieldValid:      model.VoteLogValidValid,
+	}).AndWhere(dbx.Not(dbx.HashExp{model.VoteLogsFieldVoteId: voteId})).All(&voteLogs); err != nil {
+		return 0, err
+	}
+	for _, voteLog := range voteLogs {
+		voteIds[voteLog.VoteId()] = true
+	}
+
+	var juryUsers []*model.VoteJuryUser
+	if err := service.app.RecordQuery(model.DbNameVoteJuryUsers).Where(dbx.HashExp{
+		model.VoteJuryUserFieldUserId: userId,
+		model.VoteJuryUserFieldStatus: model.VoteJuryUserStatusApproved,
+	}).AndWhere(dbx.Not(dbx.HashExp{model.VoteJuryUserFieldVoteId: voteId})).All(&juryUsers); err != nil {
+		return 0, err
+	}
+	for _, juryUser := range juryUsers {
+		voteIds[juryUser.VoteId()] = true
+	}
+
+	return len(voteIds), nil
+}